@@ -0,0 +1,72 @@
+package main
+
+import (
+	"context"
+	"fmt"
+)
+
+// jxaAreaLookup returns JXA statements setting area to the area named areaName
+// They throw "Area not found" if there's no such area, since byName alone doesn't fail until the area is used
+func jxaAreaLookup(areaName string) string {
+	return fmt.Sprintf(`
+    var area = app.areas.byName('%s');
+    try {
+        area.name();
+    } catch (e) {
+        throw new Error('Area not found');
+    }`, jxaEscape(areaName))
+}
+
+// createArea creates an area named areaName
+// Things.app allows several areas with the same name, which byName can't tell apart, so a duplicate name is refused
+func createArea(ctx context.Context, areaName string) (OperationResult, error) {
+	jxaScript := fmt.Sprintf(`
+try {
+    var app = Application('Things3');
+    var name = '%s';
+    if (app.areas.whose({name: name}).length > 0) {
+        throw new Error('Area already exists');
+    }
+    var area = app.Area({name: name});
+    app.areas.push(area);
+    'SUCCESS: 1 ' + JSON.stringify([area.id()]);
+} catch (e) {
+    'ERROR: ' + e.message;
+}
+`, jxaEscape(areaName))
+
+	if dryRun {
+		return dryRunResult(jxaScript, fmt.Sprintf("create area \"%s\"", areaName)), nil
+	}
+
+	result, err := runProjectOrAreaScript(ctx, jxaScript, "", areaName)
+	if err != nil || !result.Success {
+		return result, err
+	}
+	result.Message = fmt.Sprintf("Area \"%s\" created!", areaName)
+	return result, nil
+}
+
+// renameArea renames the area named areaName to newName
+func renameArea(ctx context.Context, areaName, newName string) (OperationResult, error) {
+	jxaScript := fmt.Sprintf(`
+try {
+    var app = Application('Things3');%s
+    area.name = '%s';
+    'SUCCESS: 1 ' + JSON.stringify([area.id()]);
+} catch (e) {
+    'ERROR: ' + e.message;
+}
+`, jxaAreaLookup(areaName), jxaEscape(newName))
+
+	if dryRun {
+		return dryRunResult(jxaScript, fmt.Sprintf("rename area \"%s\" to \"%s\"", areaName, newName)), nil
+	}
+
+	result, err := runProjectOrAreaScript(ctx, jxaScript, "", areaName)
+	if err != nil || !result.Success {
+		return result, err
+	}
+	result.Message = fmt.Sprintf("Area \"%s\" renamed to \"%s\"!", areaName, newName)
+	return result, nil
+}
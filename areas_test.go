@@ -0,0 +1,75 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestCreateArea(t *testing.T) {
+	tests := []struct {
+		name          string
+		output        string
+		expectSuccess bool
+		expectMessage string
+	}{
+		{"created", `SUCCESS: 1 ["A1"]`, true, `Area "Home" created!`},
+		{"duplicate name", "ERROR: Area already exists", false, `ERROR: Area "Home" already exists`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cleanup := setupMockExecutor(tt.output, nil)
+			defer cleanup()
+
+			result, err := createArea(context.Background(), "Home")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result.Success != tt.expectSuccess || result.Message != tt.expectMessage {
+				t.Errorf("expected success %v with %q, got %+v", tt.expectSuccess, tt.expectMessage, result)
+			}
+			script := executor.(*MockExecutor).lastScript()
+			for _, want := range []string{"app.areas.whose({name: name}).length > 0", "app.Area({name: name})", "app.areas.push(area);"} {
+				if !strings.Contains(script, want) {
+					t.Errorf("expected script to contain %q:\n%s", want, script)
+				}
+			}
+		})
+	}
+}
+
+func TestRenameArea(t *testing.T) {
+	t.Run("renamed", func(t *testing.T) {
+		cleanup := setupMockExecutor(`SUCCESS: 1 ["A1"]`, nil)
+		defer cleanup()
+
+		result, err := renameArea(context.Background(), "Home", "Personal")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !result.Success || result.Message != `Area "Home" renamed to "Personal"!` {
+			t.Errorf("unexpected result: %+v", result)
+		}
+		if script := executor.(*MockExecutor).lastScript(); !strings.Contains(script, "area.name = 'Personal';") {
+			t.Errorf("expected the rename in the script:\n%s", script)
+		}
+	})
+
+	t.Run("missing area", func(t *testing.T) {
+		cleanup := setupMockExecutor("ERROR: Area not found", nil)
+		defer cleanup()
+
+		result, err := renameArea(context.Background(), "Nowhere", "Personal")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result.Success || result.Message != `ERROR: Area "Nowhere" not found` {
+			t.Errorf("unexpected result: %+v", result)
+		}
+		if !errors.Is(result.Err, ErrAreaNotFound) {
+			t.Errorf("expected ErrAreaNotFound, got %v", result.Err)
+		}
+	})
+}
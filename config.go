@@ -0,0 +1,68 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// Config holds defaults read from the config file, used in place of the built-in defaults for flags that aren't given
+type Config struct {
+	List      string `json:"list"`       // the list show, add, and import use without --list
+	Timezone  string `json:"timezone"`   // the IANA zone for --timezone
+	WeekStart string `json:"week-start"` // the first day of the week for --week-start
+	Color     string `json:"color"`      // when to colorize output, as for --color
+	AuthToken string `json:"auth-token"` // the Things URL scheme auth token, for changes scripts can't make
+}
+
+// Global config file location - can be replaced in tests; empty means no config file is read
+var configPath = defaultConfigPath()
+
+// defaultConfigPath returns things/config.json under $XDG_CONFIG_HOME, or ~/.config if that isn't set
+// It's empty if neither can be found
+func defaultConfigPath() string {
+	dir := os.Getenv("XDG_CONFIG_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return ""
+		}
+		dir = filepath.Join(home, ".config")
+	}
+	return filepath.Join(dir, "things", "config.json")
+}
+
+// loadConfig reads the JSON config file at configPath
+// A missing file isn't an error and gives an empty Config; unknown keys are, so a misspelled one isn't silently ignored
+func loadConfig() (Config, error) {
+	var config Config
+	if configPath == "" {
+		return config, nil
+	}
+	data, err := os.ReadFile(configPath)
+	if errors.Is(err, fs.ErrNotExist) {
+		return config, nil
+	}
+	if err != nil {
+		return config, fmt.Errorf("ERROR: can't read config file: %v", err)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&config); err != nil {
+		return Config{}, fmt.Errorf("ERROR: invalid config file %s: %v", configPath, err)
+	}
+	return config, nil
+}
+
+// orDefault returns value, or fallback if value is empty
+func orDefault(value, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+	return value
+}
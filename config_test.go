@@ -0,0 +1,140 @@
+package main
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestMain(m *testing.M) {
+	// Keep the config file of whoever runs the tests from changing flag defaults
+	configPath = ""
+	os.Exit(m.Run())
+}
+
+// useConfig points configPath at a temporary file with the given contents for the rest of the test
+func useConfig(t *testing.T, contents string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(contents), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	configPath = path
+	t.Cleanup(func() { configPath = "" })
+}
+
+func TestLoadConfig(t *testing.T) {
+	tests := []struct {
+		name      string
+		contents  string
+		expected  Config
+		expectErr bool
+	}{
+		{
+			name:     "all keys",
+			contents: `{"list": "Work", "timezone": "Europe/Berlin", "week-start": "monday", "color": "never"}`,
+			expected: Config{List: "Work", Timezone: "Europe/Berlin", WeekStart: "monday", Color: "never"},
+		},
+		{
+			name:     "some keys",
+			contents: `{"list": "Today"}`,
+			expected: Config{List: "Today"},
+		},
+		{
+			name:      "unknown key",
+			contents:  `{"weekStart": "monday"}`,
+			expectErr: true,
+		},
+		{
+			name:      "malformed",
+			contents:  `{"list": `,
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			useConfig(t, tt.contents)
+
+			config, err := loadConfig()
+			if tt.expectErr {
+				if err == nil || !strings.HasPrefix(err.Error(), "ERROR: invalid config file") {
+					t.Errorf("expected an invalid config error, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if config != tt.expected {
+				t.Errorf("expected %+v, got %+v", tt.expected, config)
+			}
+		})
+	}
+}
+
+func TestLoadConfig_Missing(t *testing.T) {
+	configPath = filepath.Join(t.TempDir(), "missing", "config.json")
+	defer func() { configPath = "" }()
+
+	config, err := loadConfig()
+	if err != nil {
+		t.Fatalf("expected a missing file to be fine, got %v", err)
+	}
+	if config != (Config{}) {
+		t.Errorf("expected an empty config, got %+v", config)
+	}
+}
+
+func TestConfigDefaults(t *testing.T) {
+	tests := []struct {
+		name         string
+		args         []string
+		expectScript string
+		expectOutput string
+	}{
+		{"config list", []string{"things", "show"}, "app.lists.byName('Work')", ""},
+		{"flag overrides config list", []string{"things", "show", "--list", "Today"}, "app.lists.byName('Today')", ""},
+		{"config list for add", []string{"things", "add", "--name", "Task"}, "app.lists.byName('Work')", ""},
+		{"config color", []string{"things", "show"}, "", "\x1b[32m✔︎\x1b[0m Done\n"},
+		{"flag overrides config color", []string{"things", "show", "--color", "never"}, "", "✔︎ Done\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			useConfig(t, `{"list": "Work", "color": "always"}`)
+			cleanup := setupMockExecutorIntegration(`[{"name":"Done","status":"completed"}]`, nil)
+			defer cleanup()
+
+			var out strings.Builder
+			app := createTestAppWithWriters(&out, io.Discard)
+			if err := app.Run(context.Background(), tt.args); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if script := executor.(*MockExecutor).lastScript(); !strings.Contains(script, tt.expectScript) {
+				t.Errorf("expected script to contain %q:\n%s", tt.expectScript, script)
+			}
+			if tt.expectOutput != "" && out.String() != tt.expectOutput {
+				t.Errorf("expected %q, got %q", tt.expectOutput, out.String())
+			}
+		})
+	}
+}
+
+func TestConfigDefaults_InvalidFile(t *testing.T) {
+	useConfig(t, `{"list": 3}`)
+	cleanup := setupMockExecutorIntegration("[]", nil)
+	defer cleanup()
+
+	app := createTestApp()
+	err := app.Run(context.Background(), []string{"things", "show", "--list", "Today"})
+	if err == nil || !strings.HasPrefix(err.Error(), "ERROR: invalid config file") {
+		t.Errorf("expected an invalid config error, got %v", err)
+	}
+	if calls := len(executor.(*MockExecutor).calls); calls != 0 {
+		t.Errorf("expected no script to run, got %d calls", calls)
+	}
+}
@@ -0,0 +1,157 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// trickyName is a to-do name that has broken script escaping before, or easily could,
+// along with how a single-quoted script literal should spell it
+// The spellings are written out by hand so the checks don't take jxaEscape's word for what's right
+type trickyName struct {
+	text    string
+	escaped string
+}
+
+// trickyNames are the names runEscapeChecks tries
+var trickyNames = []trickyName{
+	{`O'Brien`, `O\'Brien`},
+	{`say "hi"`, `say "hi"`},
+	{`back\slash`, `back\\slash`},
+	{`back\'quote`, `back\\\'quote`},
+	{"emoji 🎉", "emoji 🎉"},
+	{"multi\nline", `multi\nline`},
+}
+
+// escapeCheck is the outcome of checking how one action's script embeds one tricky name
+type escapeCheck struct {
+	Action string
+	Name   string
+	Err    error
+}
+
+// runEscapeChecks builds the add, delete, and rename scripts for every tricky name without running them,
+// checking each script embeds the names as string literals that read back unchanged
+func runEscapeChecks(ctx context.Context) []escapeCheck {
+	previousDryRun := dryRun
+	dryRun = true
+	defer func() { dryRun = previousDryRun }()
+
+	actions := []struct {
+		name  string
+		run   func(name string) (OperationResult, error)
+		names func(name trickyName) []trickyName
+	}{
+		{
+			name: "add",
+			run: func(name string) (OperationResult, error) {
+				return addTodoToList(ctx, "Inbox", name, nil, nil)
+			},
+			names: func(name trickyName) []trickyName { return []trickyName{name} },
+		},
+		{
+			name: "delete",
+			run: func(name string) (OperationResult, error) {
+				return deleteTodoFromList(ctx, "Inbox", name, MatchOptions{})
+			},
+			names: func(name trickyName) []trickyName { return []trickyName{name} },
+		},
+		{
+			name: "rename",
+			run: func(name string) (OperationResult, error) {
+				return renameTodoInList(ctx, "Inbox", name, name+" (renamed)", MatchOptions{})
+			},
+			names: func(name trickyName) []trickyName {
+				return []trickyName{name, {name.text + " (renamed)", name.escaped + " (renamed)"}}
+			},
+		},
+	}
+
+	var checks []escapeCheck
+	for _, action := range actions {
+		for _, name := range trickyNames {
+			check := escapeCheck{Action: action.name, Name: name.text}
+			result, err := action.run(name.text)
+			if err != nil {
+				check.Err = err
+			} else {
+				script, _, _ := strings.Cut(result.Message, "\n\nDry run:")
+				for _, expected := range action.names(name) {
+					if err := checkScriptLiteral(script, expected.text, expected.escaped); err != nil {
+						check.Err = err
+						break
+					}
+				}
+			}
+			checks = append(checks, check)
+		}
+	}
+	return checks
+}
+
+// checkScriptLiteral checks that script contains text as the single-quoted string literal spelled with escaped,
+// and that the literal reads back as text
+func checkScriptLiteral(script, text, escaped string) error {
+	literal := "'" + escaped + "'"
+	if !strings.Contains(script, literal) {
+		return fmt.Errorf("script doesn't contain %s", literal)
+	}
+	decoded, err := jxaUnquote(literal)
+	if err != nil {
+		return fmt.Errorf("%s is not a valid string literal: %v", literal, err)
+	}
+	if decoded != text {
+		return fmt.Errorf("%s reads back as %q", literal, decoded)
+	}
+	return nil
+}
+
+// jxaUnquote interprets a single-quoted JavaScript string literal, returning the string it stands for
+// It handles the escapes jxaEscape produces, and rejects quotes or line terminators that would end the literal early
+func jxaUnquote(literal string) (string, error) {
+	if len(literal) < 2 || literal[0] != '\'' || literal[len(literal)-1] != '\'' {
+		return "", fmt.Errorf("not single-quoted")
+	}
+	body := []rune(literal[1 : len(literal)-1])
+
+	var result strings.Builder
+	for i := 0; i < len(body); i++ {
+		switch r := body[i]; r {
+		case '\'':
+			return "", fmt.Errorf("unescaped quote at offset %d", i)
+		case '\n', '\r', '\u2028', '\u2029':
+			return "", fmt.Errorf("unescaped line terminator at offset %d", i)
+		case '\\':
+			i++
+			if i == len(body) {
+				return "", fmt.Errorf("escape at end of literal")
+			}
+			switch e := body[i]; e {
+			case 'n':
+				result.WriteRune('\n')
+			case 'r':
+				result.WriteRune('\r')
+			case 't':
+				result.WriteRune('\t')
+			case 'u':
+				if i+4 >= len(body) {
+					return "", fmt.Errorf("short \\u escape at offset %d", i-1)
+				}
+				code, err := strconv.ParseUint(string(body[i+1:i+5]), 16, 16)
+				if err != nil {
+					return "", fmt.Errorf("invalid \\u escape at offset %d", i-1)
+				}
+				result.WriteRune(rune(code))
+				i += 4
+			default:
+				// Any other escaped character, including \\ and \', stands for itself
+				result.WriteRune(e)
+			}
+		default:
+			result.WriteRune(r)
+		}
+	}
+	return result.String(), nil
+}
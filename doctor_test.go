@@ -0,0 +1,115 @@
+package main
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestJXAEscape(t *testing.T) {
+	tests := []struct {
+		text     string
+		expected string
+	}{
+		{"plain", "plain"},
+		{`O'Brien`, `O\'Brien`},
+		{`say "hi"`, `say "hi"`},
+		{`back\slash`, `back\\slash`},
+		{`back\'quote`, `back\\\'quote`},
+		{"multi\nline", `multi\nline`},
+		{"carriage\rreturn", `carriage\rreturn`},
+		{"line\u2028separator", `line\u2028separator`},
+		{"emoji 🎉", "emoji 🎉"},
+	}
+
+	for _, tt := range tests {
+		escaped := jxaEscape(tt.text)
+		if escaped != tt.expected {
+			t.Errorf("jxaEscape(%q): expected %q, got %q", tt.text, tt.expected, escaped)
+		}
+		decoded, err := jxaUnquote("'" + escaped + "'")
+		if err != nil {
+			t.Errorf("jxaUnquote of jxaEscape(%q): unexpected error: %v", tt.text, err)
+		} else if decoded != tt.text {
+			t.Errorf("jxaUnquote of jxaEscape(%q): got %q", tt.text, decoded)
+		}
+	}
+}
+
+func TestJXAUnquote_Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		literal string
+	}{
+		{"unquoted", `O'Brien`},
+		{"quote ends literal early", `'O'Brien'`},
+		{"escaped backslash before quote", `'back\\'quote'`},
+		{"raw newline", "'multi\nline'"},
+		{"escape at end", `'trailing\'`},
+		{"short unicode escape", `'\u20'`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := jxaUnquote(tt.literal); err == nil {
+				t.Errorf("expected error for %q but got none", tt.literal)
+			}
+		})
+	}
+}
+
+func TestRunEscapeChecks(t *testing.T) {
+	cleanup := setupMockExecutor("", nil)
+	defer cleanup()
+
+	checks := runEscapeChecks(context.Background())
+	if len(checks) != 3*len(trickyNames) {
+		t.Fatalf("expected %d checks, got %d", 3*len(trickyNames), len(checks))
+	}
+	for _, check := range checks {
+		if check.Err != nil {
+			t.Errorf("%s %q: %v", check.Action, check.Name, check.Err)
+		}
+	}
+
+	if calls := executor.(*MockExecutor).calls; len(calls) != 0 {
+		t.Errorf("expected no scripts to be run, got %d", len(calls))
+	}
+	if dryRun {
+		t.Error("expected dry run to be switched back off")
+	}
+}
+
+func TestCheckScriptLiteral(t *testing.T) {
+	// The escaping used before jxaEscape only handled single quotes
+	script := "var name = 'back\\'quote';"
+	if err := checkScriptLiteral(script, `back\'quote`, `back\\\'quote`); err == nil {
+		t.Error("expected error for a name escaped without its backslash but got none")
+	}
+	if err := checkScriptLiteral("var name = 'back\\\\\\'quote';", `back\'quote`, `back\\\'quote`); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	// A hand-written spelling that doesn't read back as the name is caught too
+	if err := checkScriptLiteral("var name = 'back\\'quote';", `back\'quote`, `back\'quote`); err == nil {
+		t.Error("expected error for a spelling that reads back differently but got none")
+	}
+}
+
+
+func TestDoctorCommand(t *testing.T) {
+	cleanup := setupMockExecutorIntegration("", nil)
+	defer cleanup()
+
+	var out strings.Builder
+	app := createTestAppWithWriters(&out, io.Discard)
+	if err := app.Run(context.Background(), []string{"things", "doctor"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(out.String(), "All 18 escape checks passed") {
+		t.Errorf("expected all checks to pass, got:\n%s", out.String())
+	}
+	if strings.Contains(out.String(), "FAIL") {
+		t.Errorf("expected no failures, got:\n%s", out.String())
+	}
+}
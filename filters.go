@@ -0,0 +1,269 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// todoStatuses lists the status values Things.app reports for to-dos
+var todoStatuses = []string{"open", "completed", "canceled"}
+
+// normalizeStatus returns status as one of todoStatuses where it's a variant spelling of one
+// Things.app sometimes reports canceled to-dos as "cancelled"
+func normalizeStatus(status string) string {
+	status = strings.ToLower(strings.TrimSpace(status))
+	if status == "cancelled" {
+		return "canceled"
+	}
+	return status
+}
+
+// filterTodosByTags returns the todos carrying at least one of the given tags
+// If no tags are given, all todos are returned
+func filterTodosByTags(todos []Todo, tags []string) []Todo {
+	if len(tags) == 0 {
+		return todos
+	}
+
+	var filtered []Todo
+	for _, todo := range todos {
+		for _, tag := range tags {
+			if slices.Contains(todo.TagNames, tag) {
+				filtered = append(filtered, todo)
+				break
+			}
+		}
+	}
+	return filtered
+}
+
+// filterTodosByStatus returns the todos with the given status
+// A status of "all" (or empty) returns all todos
+func filterTodosByStatus(todos []Todo, status string) []Todo {
+	if status == "" || status == "all" {
+		return todos
+	}
+	status = normalizeStatus(status)
+
+	var filtered []Todo
+	for _, todo := range todos {
+		if normalizeStatus(todo.Status) == status {
+			filtered = append(filtered, todo)
+		}
+	}
+	return filtered
+}
+
+// filterByNameRegex returns the todos whose names match re
+// If re is nil, all todos are returned
+func filterByNameRegex(todos []Todo, re *regexp.Regexp) []Todo {
+	if re == nil {
+		return todos
+	}
+
+	var filtered []Todo
+	for _, todo := range todos {
+		if re.MatchString(todo.Name) {
+			filtered = append(filtered, todo)
+		}
+	}
+	return filtered
+}
+
+// limitTodos returns at most n todos
+// A limit of zero or less returns all todos
+func limitTodos(todos []Todo, n int) []Todo {
+	if n <= 0 || n >= len(todos) {
+		return todos
+	}
+	return todos[:n]
+}
+
+// filterTodosByAreaProject returns the todos in the given area and project
+// An empty area or project matches any
+func filterTodosByAreaProject(todos []Todo, area, project string) []Todo {
+	if area == "" && project == "" {
+		return todos
+	}
+
+	var filtered []Todo
+	for _, todo := range todos {
+		if area != "" && todo.Area != area {
+			continue
+		}
+		if project != "" && todo.Project != project {
+			continue
+		}
+		filtered = append(filtered, todo)
+	}
+	return filtered
+}
+
+// filterByTimeOfDay returns the todos completed between the from and to clock times (HH:MM) in loc, inclusive
+// An empty from or to leaves that end of the day open, and todos without a completion date are left out
+func filterByTimeOfDay(todos []Todo, from, to string, loc *time.Location) ([]Todo, error) {
+	if from == "" && to == "" {
+		return todos, nil
+	}
+
+	start, end := 0, 24*60-1
+	var err error
+	if from != "" {
+		if start, err = parseTimeOfDay("--from-time", from); err != nil {
+			return nil, err
+		}
+	}
+	if to != "" {
+		if end, err = parseTimeOfDay("--to-time", to); err != nil {
+			return nil, err
+		}
+	}
+	if start > end {
+		return nil, fmt.Errorf("ERROR: --from-time must not be later than --to-time")
+	}
+
+	var filtered []Todo
+	for _, todo := range todos {
+		if todo.CompletionDate == nil {
+			continue
+		}
+		completed := todo.CompletionDate.In(loc)
+		minute := completed.Hour()*60 + completed.Minute()
+		if minute >= start && minute <= end {
+			filtered = append(filtered, todo)
+		}
+	}
+	return filtered, nil
+}
+
+// parseTimeOfDay parses an HH:MM clock time given with flag into minutes since midnight
+func parseTimeOfDay(flag, value string) (int, error) {
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, fmt.Errorf("ERROR: %s must be a time in HH:MM format", flag)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// filterOverdue returns the open todos due before the day it is at now, in now's timezone
+// Todos due that day aren't overdue yet
+func filterOverdue(todos []Todo, now time.Time) []Todo {
+	today := calendarDay(now)
+
+	var filtered []Todo
+	for _, todo := range todos {
+		if normalizeStatus(todo.Status) != "open" || todo.DueDate == nil {
+			continue
+		}
+		// Due dates are days in the local timezone, whatever zone today is worked out in
+		if calendarDay(todo.DueDate.In(time.Local)).Before(today) {
+			filtered = append(filtered, todo)
+		}
+	}
+	return filtered
+}
+
+// calendarDay returns t's year, month, and day as midnight UTC, so days from different timezones compare as dates
+func calendarDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// filterByDueRange returns the todos due strictly before before and strictly after after, comparing days
+// A nil bound is left open, and while either is set, todos without a due date are left out
+func filterByDueRange(todos []Todo, before, after *time.Time) []Todo {
+	if before == nil && after == nil {
+		return todos
+	}
+
+	var filtered []Todo
+	for _, todo := range todos {
+		if todo.DueDate == nil {
+			continue
+		}
+		due := calendarDay(todo.DueDate.In(time.Local))
+		if before != nil && !due.Before(calendarDay(*before)) {
+			continue
+		}
+		if after != nil && !due.After(calendarDay(*after)) {
+			continue
+		}
+		filtered = append(filtered, todo)
+	}
+	return filtered
+}
+
+// filterByModifiedAfter returns the todos last modified strictly after t
+// Todos without a modification date are left out
+func filterByModifiedAfter(todos []Todo, t time.Time) []Todo {
+	var filtered []Todo
+	for _, todo := range todos {
+		if todo.ModificationDate != nil && todo.ModificationDate.After(t) {
+			filtered = append(filtered, todo)
+		}
+	}
+	return filtered
+}
+
+// parseModifiedAfter parses a --modified-after value: an RFC3339 timestamp, or a YYYY-MM-DD date meaning the start of that day in loc
+func parseModifiedAfter(value string, loc *time.Location) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	t, err := parseDay(value, loc)
+	if err != nil {
+		return time.Time{}, errors.New("ERROR: --modified-after must be a date in YYYY-MM-DD format or an RFC3339 timestamp")
+	}
+	return t, nil
+}
+
+// parseDayDuration parses a duration such as "30d" (days), or anything time.ParseDuration accepts, such as "12h"
+// A day is always 24 hours, and negative durations aren't accepted
+func parseDayDuration(value string) (time.Duration, error) {
+	var d time.Duration
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration: %s", value)
+		}
+		d = time.Duration(n) * 24 * time.Hour
+	} else {
+		parsed, err := time.ParseDuration(value)
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration: %s", value)
+		}
+		d = parsed
+	}
+	if d < 0 {
+		return 0, fmt.Errorf("negative duration: %s", value)
+	}
+	return d, nil
+}
+
+// filterByAge returns the todos created at least minAge and at most maxAge before now
+// A zero bound is left open, and while either is set, todos without a creation date are left out
+func filterByAge(todos []Todo, now time.Time, minAge, maxAge time.Duration) []Todo {
+	if minAge == 0 && maxAge == 0 {
+		return todos
+	}
+
+	var filtered []Todo
+	for _, todo := range todos {
+		if todo.CreationDate == nil {
+			continue
+		}
+		age := now.Sub(*todo.CreationDate)
+		if minAge > 0 && age < minAge {
+			continue
+		}
+		if maxAge > 0 && age > maxAge {
+			continue
+		}
+		filtered = append(filtered, todo)
+	}
+	return filtered
+}
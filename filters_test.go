@@ -0,0 +1,383 @@
+package main
+
+import (
+	"encoding/json"
+	"regexp"
+	"testing"
+	"time"
+)
+
+func TestFilterTodosByTags(t *testing.T) {
+	todos := []Todo{
+		{Name: "Task 1", Status: "open", TagNames: []string{"Important", "Work"}},
+		{Name: "Task 2", Status: "open", TagNames: []string{"Home"}},
+		{Name: "Task 3", Status: "open"},
+		{Name: "Task 4", Status: "open", TagNames: []string{"Work"}},
+	}
+
+	tests := []struct {
+		name          string
+		tags          []string
+		expectedNames []string
+	}{
+		{
+			name:          "no tags returns all",
+			tags:          nil,
+			expectedNames: []string{"Task 1", "Task 2", "Task 3", "Task 4"},
+		},
+		{
+			name:          "single tag",
+			tags:          []string{"Work"},
+			expectedNames: []string{"Task 1", "Task 4"},
+		},
+		{
+			name:          "multiple tags use OR semantics",
+			tags:          []string{"Important", "Home"},
+			expectedNames: []string{"Task 1", "Task 2"},
+		},
+		{
+			name:          "no matches",
+			tags:          []string{"Errands"},
+			expectedNames: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := filterTodosByTags(todos, tt.tags)
+			assertTodoNames(t, result, tt.expectedNames)
+		})
+	}
+}
+
+func TestFilterTodosByStatus(t *testing.T) {
+	todos := []Todo{
+		{Name: "Task 1", Status: "open"},
+		{Name: "Task 2", Status: "completed"},
+		{Name: "Task 3", Status: "canceled"},
+		{Name: "Task 4", Status: "open"},
+		{Name: "Task 5", Status: "cancelled"},
+	}
+
+	tests := []struct {
+		status        string
+		expectedNames []string
+	}{
+		{"open", []string{"Task 1", "Task 4"}},
+		{"completed", []string{"Task 2"}},
+		{"canceled", []string{"Task 3", "Task 5"}},
+		{"cancelled", []string{"Task 3", "Task 5"}},
+		{"all", []string{"Task 1", "Task 2", "Task 3", "Task 4", "Task 5"}},
+		{"", []string{"Task 1", "Task 2", "Task 3", "Task 4", "Task 5"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.status, func(t *testing.T) {
+			result := filterTodosByStatus(todos, tt.status)
+			assertTodoNames(t, result, tt.expectedNames)
+		})
+	}
+}
+
+func TestFilterByNameRegex(t *testing.T) {
+	todos := []Todo{
+		{Name: "Buy milk"},
+		{Name: "Buy eggs"},
+		{Name: "Call mom"},
+	}
+
+	tests := []struct {
+		name          string
+		re            *regexp.Regexp
+		expectedNames []string
+	}{
+		{"anchored", regexp.MustCompile(`^Buy `), []string{"Buy milk", "Buy eggs"}},
+		{"alternation", regexp.MustCompile(`milk|mom`), []string{"Buy milk", "Call mom"}},
+		{"no match", regexp.MustCompile(`^bread$`), nil},
+		{"nil keeps all", nil, []string{"Buy milk", "Buy eggs", "Call mom"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertTodoNames(t, filterByNameRegex(todos, tt.re), tt.expectedNames)
+		})
+	}
+}
+
+func TestNormalizeStatus(t *testing.T) {
+	tests := []struct {
+		status   string
+		expected string
+	}{
+		{"open", "open"},
+		{"completed", "completed"},
+		{"canceled", "canceled"},
+		{"cancelled", "canceled"},
+		{"Cancelled", "canceled"},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.status, func(t *testing.T) {
+			if result := normalizeStatus(tt.status); result != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestLimitTodos(t *testing.T) {
+	todos := []Todo{
+		{Name: "Task 1", Status: "open"},
+		{Name: "Task 2", Status: "open"},
+		{Name: "Task 3", Status: "open"},
+	}
+
+	tests := []struct {
+		name          string
+		n             int
+		expectedNames []string
+	}{
+		{"limit greater than length", 5, []string{"Task 1", "Task 2", "Task 3"}},
+		{"limit less than length", 2, []string{"Task 1", "Task 2"}},
+		{"zero means no limit", 0, []string{"Task 1", "Task 2", "Task 3"}},
+		{"negative means no limit", -1, []string{"Task 1", "Task 2", "Task 3"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := limitTodos(todos, tt.n)
+			assertTodoNames(t, result, tt.expectedNames)
+		})
+	}
+}
+
+// assertTodoNames checks that todos have exactly the expected names, in order
+func assertTodoNames(t *testing.T, todos []Todo, expectedNames []string) {
+	t.Helper()
+	if len(todos) != len(expectedNames) {
+		t.Fatalf("expected %d todos, got %d: %+v", len(expectedNames), len(todos), todos)
+	}
+	for i, todo := range todos {
+		if todo.Name != expectedNames[i] {
+			t.Errorf("todo %d: expected name %q, got %q", i, expectedNames[i], todo.Name)
+		}
+	}
+}
+
+func TestFilterTodosByAreaProject(t *testing.T) {
+	var todos []Todo
+	if err := json.Unmarshal([]byte(areaProjectFixture), &todos); err != nil {
+		t.Fatalf("invalid fixture: %v", err)
+	}
+
+	for _, tt := range areaProjectFilterCases {
+		t.Run(tt.name, func(t *testing.T) {
+			result := filterTodosByAreaProject(todos, tt.areaFilter, tt.projectFilter)
+			if len(result) != tt.expectCount {
+				t.Errorf("expected %d todos, got %d", tt.expectCount, len(result))
+			}
+			for _, todo := range result {
+				if (tt.areaFilter != "" && todo.Area != tt.areaFilter) || (tt.projectFilter != "" && todo.Project != tt.projectFilter) {
+					t.Errorf("unexpected todo %+v", todo)
+				}
+			}
+		})
+	}
+}
+
+func TestFilterByTimeOfDay(t *testing.T) {
+	morning := time.Date(2024, 1, 15, 8, 0, 0, 0, time.UTC)
+	evening := time.Date(2024, 1, 15, 20, 0, 0, 0, time.UTC)
+	todos := []Todo{
+		{Name: "Morning", Status: "completed", CompletionDate: &morning},
+		{Name: "Evening", Status: "completed", CompletionDate: &evening},
+		{Name: "Undated", Status: "completed"},
+	}
+
+	tests := []struct {
+		name        string
+		from, to    string
+		loc         *time.Location
+		expected    []string
+		expectedErr string
+	}{
+		{name: "morning window", from: "06:00", to: "12:00", loc: time.UTC, expected: []string{"Morning"}},
+		{name: "inclusive ends", from: "08:00", to: "20:00", loc: time.UTC, expected: []string{"Morning", "Evening"}},
+		{name: "from only", from: "12:00", loc: time.UTC, expected: []string{"Evening"}},
+		{name: "to only", to: "12:00", loc: time.UTC, expected: []string{"Morning"}},
+		{name: "no window", loc: time.UTC, expected: []string{"Morning", "Evening", "Undated"}},
+		// 08:00 UTC is 03:00 five hours west, before the window
+		{name: "other timezone", from: "06:00", to: "12:00", loc: time.FixedZone("EST", -5*60*60), expected: nil},
+		{name: "malformed from", from: "6am", loc: time.UTC, expectedErr: "ERROR: --from-time must be a time in HH:MM format"},
+		{name: "malformed to", to: "24:00", loc: time.UTC, expectedErr: "ERROR: --to-time must be a time in HH:MM format"},
+		{name: "backwards window", from: "12:00", to: "06:00", loc: time.UTC, expectedErr: "ERROR: --from-time must not be later than --to-time"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := filterByTimeOfDay(todos, tt.from, tt.to, tt.loc)
+			if tt.expectedErr != "" {
+				if err == nil || err.Error() != tt.expectedErr {
+					t.Errorf("expected error %q, got %v", tt.expectedErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			assertTodoNames(t, result, tt.expected)
+		})
+	}
+}
+
+func TestFilterOverdue(t *testing.T) {
+	now := time.Date(2024, 1, 15, 9, 30, 0, 0, time.Local)
+	yesterday := Date{time.Date(2024, 1, 14, 0, 0, 0, 0, time.Local)}
+	today := Date{time.Date(2024, 1, 15, 0, 0, 0, 0, time.Local)}
+	todos := []Todo{
+		{Name: "Due yesterday", Status: "open", DueDate: &yesterday},
+		{Name: "Due today", Status: "open", DueDate: &today},
+		{Name: "No due date", Status: "open"},
+		{Name: "Done late", Status: "completed", DueDate: &yesterday},
+	}
+
+	assertTodoNames(t, filterOverdue(todos, now), []string{"Due yesterday"})
+}
+
+func TestFilterByDueRange(t *testing.T) {
+	jan10 := Date{time.Date(2024, 1, 10, 0, 0, 0, 0, time.Local)}
+	jan20 := Date{time.Date(2024, 1, 20, 0, 0, 0, 0, time.Local)}
+	feb5 := Date{time.Date(2024, 2, 5, 0, 0, 0, 0, time.Local)}
+	todos := []Todo{
+		{Name: "Early", Status: "open", DueDate: &jan10},
+		{Name: "Middle", Status: "open", DueDate: &jan20},
+		{Name: "Late", Status: "open", DueDate: &feb5},
+		{Name: "Undated", Status: "open"},
+	}
+	jan15 := time.Date(2024, 1, 15, 0, 0, 0, 0, time.Local)
+	feb1 := time.Date(2024, 2, 1, 0, 0, 0, 0, time.Local)
+
+	tests := []struct {
+		name          string
+		before, after *time.Time
+		expected      []string
+	}{
+		{name: "before only", before: &feb1, expected: []string{"Early", "Middle"}},
+		{name: "after only", after: &jan15, expected: []string{"Middle", "Late"}},
+		{name: "both", before: &feb1, after: &jan15, expected: []string{"Middle"}},
+		{name: "bounds are exclusive", before: &jan20.Time, after: &jan10.Time, expected: nil},
+		{name: "no filter keeps undated", expected: []string{"Early", "Middle", "Late", "Undated"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertTodoNames(t, filterByDueRange(todos, tt.before, tt.after), tt.expected)
+		})
+	}
+}
+
+func TestFilterByModifiedAfter(t *testing.T) {
+	cutoff := time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)
+	before := cutoff.Add(-time.Hour)
+	after := cutoff.Add(time.Minute)
+	todos := []Todo{
+		{Name: "Stale", ModificationDate: &before},
+		{Name: "Fresh", ModificationDate: &after},
+		{Name: "At cutoff", ModificationDate: &cutoff},
+		{Name: "Never modified"},
+	}
+
+	assertTodoNames(t, filterByModifiedAfter(todos, cutoff), []string{"Fresh"})
+	assertTodoNames(t, filterByModifiedAfter(todos, before.Add(-time.Hour)), []string{"Stale", "Fresh", "At cutoff"})
+}
+
+func TestParseModifiedAfter(t *testing.T) {
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	if err != nil {
+		t.Skip("timezone data not available")
+	}
+
+	tests := []struct {
+		value     string
+		expected  time.Time
+		expectErr bool
+	}{
+		{value: "2024-01-15", expected: time.Date(2024, 1, 15, 0, 0, 0, 0, tokyo)},
+		{value: "2024-01-15T09:30:00Z", expected: time.Date(2024, 1, 15, 9, 30, 0, 0, time.UTC)},
+		{value: "last tuesday", expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := parseModifiedAfter(tt.value, tokyo)
+			if tt.expectErr {
+				if err == nil {
+					t.Error("expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !got.Equal(tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestParseDayDuration(t *testing.T) {
+	tests := []struct {
+		value     string
+		expected  time.Duration
+		expectErr bool
+	}{
+		{value: "7d", expected: 7 * 24 * time.Hour},
+		{value: "0d", expected: 0},
+		{value: "24h", expected: 24 * time.Hour},
+		{value: "90m", expected: 90 * time.Minute},
+		{value: "soon", expectErr: true},
+		{value: "d", expectErr: true},
+		{value: "1.5d", expectErr: true},
+		{value: "-7d", expectErr: true},
+		{value: "-1h", expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := parseDayDuration(tt.value)
+			if tt.expectErr {
+				if err == nil {
+					t.Errorf("expected error but got %v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestFilterByAge(t *testing.T) {
+	now := time.Date(2024, 1, 31, 12, 0, 0, 0, time.UTC)
+	monthOld := now.AddDate(0, 0, -30)
+	weekOld := now.AddDate(0, 0, -7)
+	fresh := now.Add(-time.Hour)
+	todos := []Todo{
+		{Name: "Month old", CreationDate: &monthOld},
+		{Name: "Week old", CreationDate: &weekOld},
+		{Name: "Fresh", CreationDate: &fresh},
+		{Name: "No creation date"},
+	}
+
+	day := 24 * time.Hour
+	assertTodoNames(t, filterByAge(todos, now, 0, 0), []string{"Month old", "Week old", "Fresh", "No creation date"})
+	assertTodoNames(t, filterByAge(todos, now, 7*day, 0), []string{"Month old", "Week old"})
+	assertTodoNames(t, filterByAge(todos, now, 0, 7*day), []string{"Week old", "Fresh"})
+	assertTodoNames(t, filterByAge(todos, now, day, 10*day), []string{"Week old"})
+}
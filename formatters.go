@@ -1,18 +1,85 @@
 package main
 
 import (
+	"encoding/csv"
 	"encoding/json"
 	"fmt"
+	"slices"
+	"sort"
 	"strings"
+	"text/tabwriter"
+	"text/template"
+	"time"
+	"unicode/utf8"
 )
 
-// formatTodosForDisplay formats a list of todos with status symbols for display
-func formatTodosForDisplay(todos []Todo) string {
+// ANSI escape codes used to colorize status symbols and to redraw the screen for show --watch
+const (
+	ansiGreen       = "\x1b[32m"
+	ansiRed         = "\x1b[31m"
+	ansiReset       = "\x1b[0m"
+	ansiClearScreen = "\x1b[H\x1b[2J"
+)
+
+// formatTodosForDisplay formats a list of todos with status symbols for display, using ASCII symbols if ascii is true
+// When width is positive, long names wrap at that many columns, lining up under the start of the name
+func formatTodosForDisplay(todos []Todo, color, ascii bool, width int) string {
 	var result strings.Builder
 	for i, todo := range todos {
-		symbol := getStatusSymbol(todo.Status)
+		symbol := colorizeSymbol(todo.Status, color, ascii)
 		result.WriteString(symbol)
+		result.WriteString(wrapLine(todo.Name, width, statusSymbolColumns(todo.Status, ascii)))
+		if i < len(todos)-1 {
+			result.WriteString("\n")
+		}
+	}
+	return result.String()
+}
+
+// notesIndent is the indentation of notes shown beneath a to-do
+const notesIndent = "  "
+
+// defaultNotesWidth is the column notes are wrapped at unless --notes-width says otherwise
+const defaultNotesWidth = 80
+
+// formatTodosDetailed formats a list of todos like formatTodosForDisplay, appending due dates, completion times, and tags
+// When notesWidth is positive, each to-do's notes follow beneath it, indented and wrapped to that many columns
+// When showAge is set, completed to-dos also show how long they were open, and when showModified is set,
+// every to-do shows when it was last modified
+func formatTodosDetailed(todos []Todo, color, ascii bool, notesWidth int, showAge, showModified bool) string {
+	var result strings.Builder
+	for i, todo := range todos {
+		result.WriteString(colorizeSymbol(todo.Status, color, ascii))
 		result.WriteString(todo.Name)
+		if todo.DueDate != nil {
+			result.WriteString("  due ")
+			result.WriteString(todo.DueDate.In(time.Local).Format("2006-01-02"))
+		}
+		if todo.CompletionDate != nil {
+			result.WriteString("  completed ")
+			result.WriteString(formatTimestamp(todo.CompletionDate))
+		}
+		if age := formatAge(todo.CreationDate, todo.CompletionDate); showAge && age != "" {
+			result.WriteString("  open ")
+			result.WriteString(age)
+		}
+		if showModified && todo.ModificationDate != nil {
+			result.WriteString("  modified ")
+			result.WriteString(formatTimestamp(todo.ModificationDate))
+		}
+		if len(todo.TagNames) > 0 {
+			result.WriteString("  ")
+			result.WriteString(formatTags(todo.TagNames))
+		}
+		if notesWidth > 0 && strings.TrimSpace(todo.Notes) != "" {
+			for _, line := range wrapText(strings.TrimRight(todo.Notes, "\n"), notesWidth-len(notesIndent)) {
+				result.WriteString("\n")
+				if line != "" {
+					result.WriteString(notesIndent)
+					result.WriteString(line)
+				}
+			}
+		}
 		if i < len(todos)-1 {
 			result.WriteString("\n")
 		}
@@ -20,18 +87,119 @@ func formatTodosForDisplay(todos []Todo) string {
 	return result.String()
 }
 
-// getStatusSymbol returns the display symbol for a todo status
-func getStatusSymbol(status string) string {
-	switch status {
-	case "open":
-		return "○ "
+// wrapText splits text into lines of at most width characters, breaking between words
+// Existing line breaks are kept, and a word longer than width gets a line to itself
+func wrapText(text string, width int) []string {
+	var lines []string
+	for _, paragraph := range strings.Split(text, "\n") {
+		words := strings.Fields(paragraph)
+		if len(words) == 0 {
+			lines = append(lines, "")
+			continue
+		}
+		line := words[0]
+		for _, word := range words[1:] {
+			if utf8.RuneCountInString(line)+1+utf8.RuneCountInString(word) > width {
+				lines = append(lines, line)
+				line = word
+				continue
+			}
+			line += " " + word
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// formatTimestamp formats a to-do timestamp, such as when it was completed, in local time, or "" if it's unset
+func formatTimestamp(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.In(time.Local).Format("2006-01-02 15:04")
+}
+
+// formatAge formats how long a to-do was open, from creation to completion, as days and hours (e.g. "3d 4h")
+// Spans under a day show hours and minutes, and under an hour just minutes; it's "" if either time is unset
+func formatAge(creation, completion *time.Time) string {
+	if creation == nil || completion == nil {
+		return ""
+	}
+	age := max(completion.Sub(*creation), 0)
+	days := int(age / (24 * time.Hour))
+	hours := int(age % (24 * time.Hour) / time.Hour)
+	minutes := int(age % time.Hour / time.Minute)
+	switch {
+	case days > 0:
+		return fmt.Sprintf("%dd %dh", days, hours)
+	case hours > 0:
+		return fmt.Sprintf("%dh %dm", hours, minutes)
+	default:
+		return fmt.Sprintf("%dm", minutes)
+	}
+}
+
+// wrapLine wraps text that starts indent columns into a line so no line is wider than width columns,
+// indenting the continuation lines to line up with the first
+// A width of 0 leaves text as it is
+func wrapLine(text string, width, indent int) string {
+	if width <= 0 {
+		return text
+	}
+	return strings.Join(wrapText(text, width-indent), "\n"+strings.Repeat(" ", indent))
+}
+
+// formatTags formats tag names as space-separated #tag tokens
+func formatTags(tags []string) string {
+	tokens := make([]string, len(tags))
+	for i, tag := range tags {
+		tokens[i] = "#" + tag
+	}
+	return strings.Join(tokens, " ")
+}
+
+// statusSymbols are the display symbols for each todo status, each followed by a space
+// The Unicode symbols are one column wide; asciiStatusSymbols are for terminals that can't show them
+var (
+	unicodeStatusSymbols = map[string]string{"open": "○ ", "completed": "✔︎ ", "canceled": "✕ "}
+	asciiStatusSymbols   = map[string]string{"open": "[ ] ", "completed": "[x] ", "canceled": "[-] "}
+)
+
+// getStatusSymbol returns the display symbol for a todo status, from the ASCII set if ascii is true
+func getStatusSymbol(status string, ascii bool) string {
+	if ascii {
+		return asciiStatusSymbols[normalizeStatus(status)]
+	}
+	return unicodeStatusSymbols[normalizeStatus(status)]
+}
+
+// statusSymbolColumns returns how many columns a todo's status symbol and its space take up
+func statusSymbolColumns(status string, ascii bool) int {
+	symbol := getStatusSymbol(status, ascii)
+	if ascii || symbol == "" {
+		return len(symbol)
+	}
+	return 2
+}
+
+// colorizeSymbol returns the status symbol, wrapped in ANSI color codes when enabled
+// Completed todos are green, canceled todos are red, and open todos keep the default color
+func colorizeSymbol(status string, enabled, ascii bool) string {
+	symbol := getStatusSymbol(status, ascii)
+	if !enabled {
+		return symbol
+	}
+
+	var color string
+	switch normalizeStatus(status) {
 	case "completed":
-		return "✔︎ "
+		color = ansiGreen
 	case "canceled":
-		return "✕ "
+		color = ansiRed
 	default:
-		return ""
+		return symbol
 	}
+	return color + strings.TrimSuffix(symbol, " ") + ansiReset + " "
 }
 
 // formatTodoAsJSONL formats a single todo as a JSONL string
@@ -43,7 +211,463 @@ func formatTodoAsJSONL(todo Todo) (string, error) {
 	return string(jsonBytes), nil
 }
 
+// formatTodosAsJSON formats todos as a single JSON array, indented by two spaces when indent is set
+func formatTodosAsJSON(todos []Todo, indent bool) (string, error) {
+	// An empty list is still an array rather than null
+	if todos == nil {
+		todos = []Todo{}
+	}
+
+	var jsonBytes []byte
+	var err error
+	if indent {
+		jsonBytes, err = json.MarshalIndent(todos, "", "  ")
+	} else {
+		jsonBytes, err = json.Marshal(todos)
+	}
+	if err != nil {
+		return "", fmt.Errorf("error marshaling todos: %v", err)
+	}
+	return string(jsonBytes), nil
+}
+
+// todoFieldKeys maps the field names --fields accepts to Todo's JSON keys
+// Each JSON key can be given as is, or by the shorter name the CSV header uses
+var todoFieldKeys = map[string]string{
+	"id":               "id",
+	"name":             "name",
+	"notes":            "notes",
+	"status":           "status",
+	"creationDate":     "creationDate",
+	"modificationDate": "modificationDate",
+	"dueDate":          "dueDate",
+	"due":              "dueDate",
+	"activationDate":   "activationDate",
+	"completionDate":   "completionDate",
+	"cancellationDate": "cancellationDate",
+	"tagNames":         "tagNames",
+	"tags":             "tagNames",
+	"checklistItems":   "checklistItems",
+	"checklist":        "checklistItems",
+	"area":             "area",
+	"project":          "project",
+}
+
+// parseFields splits a comma-separated --fields value into field names, rejecting unknown ones
+func parseFields(value string) ([]string, error) {
+	var fields []string
+	for _, field := range strings.Split(value, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		if _, ok := todoFieldKeys[field]; !ok {
+			return nil, unknownFieldError(field)
+		}
+		fields = append(fields, field)
+	}
+	return fields, nil
+}
+
+// unknownFieldError reports a field name --fields doesn't accept, listing the ones it does
+func unknownFieldError(field string) error {
+	names := make([]string, 0, len(todoFieldKeys))
+	for name := range todoFieldKeys {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return fmt.Errorf("ERROR: unknown field \"%s\" in --fields; choose from: %s", field, strings.Join(names, ", "))
+}
+
+// projectTodoFields returns the todo's JSON representation with only the given fields, keyed by their JSON names
+// Unset fields are left out, as in the full JSON
+func projectTodoFields(todo Todo, fields []string) (map[string]any, error) {
+	jsonBytes, err := json.Marshal(todo)
+	if err != nil {
+		return nil, fmt.Errorf("error marshaling todo: %v", err)
+	}
+	var all map[string]any
+	if err := json.Unmarshal(jsonBytes, &all); err != nil {
+		return nil, fmt.Errorf("error marshaling todo: %v", err)
+	}
+
+	projected := make(map[string]any, len(fields))
+	for _, field := range fields {
+		key, ok := todoFieldKeys[field]
+		if !ok {
+			return nil, unknownFieldError(field)
+		}
+		if value, ok := all[key]; ok {
+			projected[key] = value
+		}
+	}
+	return projected, nil
+}
+
+// formatProjectedTodoAsJSONL formats a single todo as a JSONL string with only the given fields
+func formatProjectedTodoAsJSONL(todo Todo, fields []string) (string, error) {
+	projected, err := projectTodoFields(todo, fields)
+	if err != nil {
+		return "", err
+	}
+	jsonBytes, err := json.Marshal(projected)
+	if err != nil {
+		return "", fmt.Errorf("error marshaling todo: %v", err)
+	}
+	return string(jsonBytes), nil
+}
+
+// formatTodoAsJSONLWithURL formats a single todo as a JSONL string with a thingsURL field that opens it in Things.app
+// Only the given fields are kept alongside the URL, or all of them if there are none; the to-do must have an id
+func formatTodoAsJSONLWithURL(todo Todo, fields []string) (string, error) {
+	if todo.ID == "" {
+		return "", fmt.Errorf("ERROR: can't link to to-do \"%s\" without its id", todo.Name)
+	}
+	url := buildShowURL(todo.ID)
+
+	var record any = struct {
+		Todo
+		ThingsURL string `json:"thingsURL"`
+	}{todo, url}
+	if len(fields) > 0 {
+		projected, err := projectTodoFields(todo, fields)
+		if err != nil {
+			return "", err
+		}
+		projected["thingsURL"] = url
+		record = projected
+	}
+
+	jsonBytes, err := json.Marshal(record)
+	if err != nil {
+		return "", fmt.Errorf("error marshaling todo: %v", err)
+	}
+	return string(jsonBytes), nil
+}
+
+// formatTodosAsCSV formats todos as CSV, with a header row when header is set
+// Tags are joined with ";" and dates are emitted as RFC3339 (or empty when unset)
+func formatTodosAsCSV(todos []Todo, header bool) (string, error) {
+	var result strings.Builder
+	writer := csv.NewWriter(&result)
+
+	if header {
+		if err := writer.Write([]string{"name", "status", "notes", "due", "tags", "area", "project"}); err != nil {
+			return "", fmt.Errorf("error writing CSV: %v", err)
+		}
+	}
+	for _, todo := range todos {
+		record := []string{
+			todo.Name,
+			normalizeStatus(todo.Status),
+			todo.Notes,
+			formatOptionalTime(todo.DueDate.timePtr()),
+			strings.Join(todo.TagNames, ";"),
+			todo.Area,
+			todo.Project,
+		}
+		if err := writer.Write(record); err != nil {
+			return "", fmt.Errorf("error writing CSV: %v", err)
+		}
+	}
+
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return "", fmt.Errorf("error writing CSV: %v", err)
+	}
+	return result.String(), nil
+}
+
+// formatTodosAsMarkdown formats todos as a Markdown checklist
+// Completed and canceled todos are checked, and canceled ones are struck through
+func formatTodosAsMarkdown(todos []Todo) string {
+	var result strings.Builder
+	for i, todo := range todos {
+		switch normalizeStatus(todo.Status) {
+		case "completed":
+			result.WriteString("- [x] " + todo.Name)
+		case "canceled":
+			result.WriteString("- [x] ~~" + todo.Name + "~~")
+		default:
+			result.WriteString("- [ ] " + todo.Name)
+		}
+		if len(todo.TagNames) > 0 {
+			result.WriteString(" ")
+			result.WriteString(formatTags(todo.TagNames))
+		}
+		if i < len(todos)-1 {
+			result.WriteString("\n")
+		}
+	}
+	return result.String()
+}
+
+// formatTodosAsTable formats todos in aligned columns, with a header row when header is set
+// The area, project, and due columns are omitted when no todo has a value for them
+func formatTodosAsTable(todos []Todo, header bool) string {
+	type column struct {
+		header string
+		value  func(Todo) string
+	}
+	columns := []column{
+		{"STATUS", func(t Todo) string { return normalizeStatus(t.Status) }},
+		{"NAME", func(t Todo) string { return t.Name }},
+	}
+	optional := []column{
+		{"AREA", func(t Todo) string { return t.Area }},
+		{"PROJECT", func(t Todo) string { return t.Project }},
+		{"DUE", func(t Todo) string {
+			if t.DueDate == nil {
+				return ""
+			}
+			return t.DueDate.In(time.Local).Format("2006-01-02")
+		}},
+		{"COMPLETED", func(t Todo) string { return formatTimestamp(t.CompletionDate) }},
+	}
+	for _, col := range optional {
+		for _, todo := range todos {
+			if col.value(todo) != "" {
+				columns = append(columns, col)
+				break
+			}
+		}
+	}
+
+	var result strings.Builder
+	writer := tabwriter.NewWriter(&result, 0, 0, 2, ' ', 0)
+	cells := make([]string, len(columns))
+	if header {
+		for i, col := range columns {
+			cells[i] = col.header
+		}
+		fmt.Fprintln(writer, strings.Join(cells, "\t"))
+	}
+	for _, todo := range todos {
+		for i, col := range columns {
+			cells[i] = col.value(todo)
+		}
+		fmt.Fprintln(writer, strings.Join(cells, "\t"))
+	}
+	_ = writer.Flush()
+
+	// Empty trailing cells still pad the previous column, so trim each line
+	lines := strings.Split(strings.TrimSuffix(result.String(), "\n"), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " ")
+	}
+	return strings.Join(lines, "\n")
+}
+
+// formatOptionalTime formats t as RFC3339, or returns an empty string when t is nil
+func formatOptionalTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
+// formatTodosWithHeader returns the title line shown above a list of todos, including how many there are
+func formatTodosWithHeader(title string, todos []Todo) string {
+	switch len(todos) {
+	case 0:
+		return fmt.Sprintf("%s (no to-dos)", title)
+	case 1:
+		return fmt.Sprintf("%s (1 to-do):", title)
+	default:
+		return fmt.Sprintf("%s (%d to-dos):", title, len(todos))
+	}
+}
+
+// countTodos returns how many todos have each status, with variant spellings counted under normalizeStatus
+func countTodos(todos []Todo) map[string]int {
+	counts := make(map[string]int)
+	for _, todo := range todos {
+		counts[normalizeStatus(todo.Status)]++
+	}
+	return counts
+}
+
+// formatTodoCounts returns a one-line summary of todos by status, such as "Work: 12 open, 3 completed"
+// Statuses with no to-dos are left out; any Things.app reports beyond todoStatuses follow in alphabetical order
+func formatTodoCounts(title string, todos []Todo) string {
+	if len(todos) == 0 {
+		return fmt.Sprintf("%s: no to-dos", title)
+	}
+	counts := countTodos(todos)
+	var others []string
+	for status := range counts {
+		if !slices.Contains(todoStatuses, status) {
+			others = append(others, status)
+		}
+	}
+	sort.Strings(others)
+
+	var parts []string
+	for _, status := range append(slices.Clone(todoStatuses), others...) {
+		if counts[status] > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", counts[status], status))
+		}
+	}
+	return fmt.Sprintf("%s: %s", title, strings.Join(parts, ", "))
+}
+
+// groupTodosByDate groups todos by the local date they're scheduled for, keyed as YYYY-MM-DD
+// Todos without a scheduled date are grouped under the empty key
+func groupTodosByDate(todos []Todo) map[string][]Todo {
+	groups := make(map[string][]Todo)
+	for _, todo := range todos {
+		key := ""
+		if todo.ActivationDate != nil {
+			key = todo.ActivationDate.In(time.Local).Format("2006-01-02")
+		}
+		groups[key] = append(groups[key], todo)
+	}
+	return groups
+}
+
+// formatGroupedByDate formats todos under a header for each scheduled date, earliest first
+// Todos without a scheduled date come last, and long adds due dates, tags, and notes like formatTodosDetailed
+func formatGroupedByDate(todos []Todo, long, color, ascii bool, notesWidth, width int) string {
+	groups := groupTodosByDate(todos)
+	keys := make([]string, 0, len(groups))
+	for key := range groups {
+		if key != "" {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	if _, ok := groups[""]; ok {
+		keys = append(keys, "")
+	}
+
+	sections := make([]string, len(keys))
+	for i, key := range keys {
+		header := "No date:"
+		if key != "" {
+			header = key + ":"
+		}
+		body := formatTodosForDisplay(groups[key], color, ascii, width)
+		if long {
+			body = formatTodosDetailed(groups[key], color, ascii, notesWidth, false, false)
+		}
+		sections[i] = header + "\n" + body
+	}
+	return strings.Join(sections, "\n\n")
+}
+
+// formatTodosGroupedBy formats todos under a header for each area or project, as chosen by key
+// Groups are sorted by name, and todos without one go under a final "(none)" header
+func formatTodosGroupedBy(todos []Todo, key string, color, ascii bool, width int) string {
+	groups := make(map[string][]Todo)
+	for _, todo := range todos {
+		name := todo.Area
+		if key == "project" {
+			name = todo.Project
+		}
+		groups[name] = append(groups[name], todo)
+	}
+
+	names := make([]string, 0, len(groups))
+	for name := range groups {
+		if name != "" {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	if _, ok := groups[""]; ok {
+		names = append(names, "")
+	}
+
+	sections := make([]string, len(names))
+	for i, name := range names {
+		header := name
+		if header == "" {
+			header = "(none)"
+		}
+		sections[i] = header + ":\n" + formatTodosForDisplay(groups[name], color, ascii, width)
+	}
+	return strings.Join(sections, "\n\n")
+}
+
 // formatOperationResult formats an operation result for display
 func formatOperationResult(result OperationResult) string {
 	return result.Message
 }
+
+// formatOperationResultJSON formats an operation result as a JSON object, for scripts to read
+// ids is left out when the action didn't report which to-dos it changed
+func formatOperationResultJSON(result OperationResult) (string, error) {
+	jsonBytes, err := json.Marshal(struct {
+		Success       bool     `json:"success"`
+		Message       string   `json:"message"`
+		AffectedCount int      `json:"affectedCount"`
+		IDs           []string `json:"ids,omitempty"`
+	}{result.Success, result.Message, result.AffectedCount, result.IDs})
+	if err != nil {
+		return "", fmt.Errorf("error marshaling result: %v", err)
+	}
+	return string(jsonBytes), nil
+}
+
+// formatAddResultJSON formats the result of adding the to-do named name as a JSON object, like formatOperationResultJSON with the name first
+// Batch adds print one per to-do, so a script can tell which lines failed
+func formatAddResultJSON(name string, result OperationResult) (string, error) {
+	jsonBytes, err := json.Marshal(struct {
+		Name          string   `json:"name"`
+		Success       bool     `json:"success"`
+		Message       string   `json:"message"`
+		AffectedCount int      `json:"affectedCount"`
+		IDs           []string `json:"ids,omitempty"`
+	}{name, result.Success, result.Message, result.AffectedCount, result.IDs})
+	if err != nil {
+		return "", fmt.Errorf("error marshaling result: %v", err)
+	}
+	return string(jsonBytes), nil
+}
+
+// todoTemplateFuncs are the helpers available to --format templates
+// date formats a to-do date with a Go time layout, e.g. {{date "Jan 2" .DueDate}}, giving "" when it's unset
+var todoTemplateFuncs = template.FuncMap{
+	"date": func(layout string, value any) string {
+		switch t := value.(type) {
+		case *time.Time:
+			if t != nil {
+				return t.In(time.Local).Format(layout)
+			}
+		case *Date:
+			if t != nil {
+				return t.In(time.Local).Format(layout)
+			}
+		case time.Time:
+			return t.In(time.Local).Format(layout)
+		}
+		return ""
+	},
+}
+
+// parseTodoTemplate parses a --format template, which is evaluated against each Todo
+func parseTodoTemplate(tmpl string) (*template.Template, error) {
+	parsed, err := template.New("format").Funcs(todoTemplateFuncs).Parse(tmpl)
+	if err != nil {
+		return nil, fmt.Errorf("ERROR: invalid --format template: %v", err)
+	}
+	return parsed, nil
+}
+
+// formatTodosWithTemplate formats each todo with a Go template, one per line
+func formatTodosWithTemplate(todos []Todo, tmpl string) (string, error) {
+	parsed, err := parseTodoTemplate(tmpl)
+	if err != nil {
+		return "", err
+	}
+
+	lines := make([]string, len(todos))
+	for i, todo := range todos {
+		var line strings.Builder
+		if err := parsed.Execute(&line, todo); err != nil {
+			return "", fmt.Errorf("ERROR: --format template failed on to-do \"%s\": %v", todo.Name, err)
+		}
+		lines[i] = line.String()
+	}
+	return strings.Join(lines, "\n"), nil
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"encoding/json"
+	"fmt"
 	"strings"
 	"testing"
 	"time"
@@ -60,7 +61,7 @@ func TestFormatTodosForDisplay(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := formatTodosForDisplay(tt.todos)
+			result := formatTodosForDisplay(tt.todos, false, false, 0)
 			if result != tt.expected {
 				t.Errorf("expected:\n%s\ngot:\n%s", tt.expected, result)
 			}
@@ -68,21 +69,282 @@ func TestFormatTodosForDisplay(t *testing.T) {
 	}
 }
 
+func TestFormatTodosDetailed(t *testing.T) {
+	due := Date{time.Date(2024, 1, 20, 0, 0, 0, 0, time.Local)}
+	completed := time.Date(2024, 1, 21, 17, 45, 30, 0, time.Local)
+
+	tests := []struct {
+		name         string
+		todos        []Todo
+		showModified bool
+		expected     string
+	}{
+		{
+			name: "todo with due date",
+			todos: []Todo{
+				{Name: "File taxes", Status: "open", DueDate: &due},
+			},
+			expected: "○ File taxes  due 2024-01-20",
+		},
+		{
+			name: "todo with tags",
+			todos: []Todo{
+				{Name: "Review PR", Status: "open", TagNames: []string{"Work", "Urgent"}},
+			},
+			expected: "○ Review PR  #Work #Urgent",
+		},
+		{
+			name: "todo with due date and tags",
+			todos: []Todo{
+				{Name: "File taxes", Status: "completed", DueDate: &due, TagNames: []string{"Home"}},
+			},
+			expected: "✔︎ File taxes  due 2024-01-20  #Home",
+		},
+		{
+			name: "completed todo with completion time",
+			todos: []Todo{
+				{Name: "File taxes", Status: "completed", DueDate: &due, CompletionDate: &completed, TagNames: []string{"Home"}},
+			},
+			expected: "✔︎ File taxes  due 2024-01-20  completed 2024-01-21 17:45  #Home",
+		},
+		{
+			name: "modification time",
+			todos: []Todo{
+				{Name: "Draft plan", Status: "open", ModificationDate: &completed, TagNames: []string{"Work"}},
+			},
+			showModified: true,
+			expected:     "○ Draft plan  modified 2024-01-21 17:45  #Work",
+		},
+		{
+			name: "modification time not asked for",
+			todos: []Todo{
+				{Name: "Draft plan", Status: "open", ModificationDate: &completed, TagNames: []string{"Work"}},
+			},
+			expected: "○ Draft plan  #Work",
+		},
+		{
+			name: "nil completion time",
+			todos: []Todo{
+				{Name: "File taxes", Status: "completed", CompletionDate: nil},
+			},
+			expected: "✔︎ File taxes",
+		},
+		{
+			name: "todo with neither",
+			todos: []Todo{
+				{Name: "Buy groceries", Status: "open"},
+				{Name: "Call dentist", Status: "canceled"},
+			},
+			expected: "○ Buy groceries\n✕ Call dentist",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := formatTodosDetailed(tt.todos, false, false, 0, false, tt.showModified)
+			if result != tt.expected {
+				t.Errorf("expected:\n%s\ngot:\n%s", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestFormatTodosDetailed_Notes(t *testing.T) {
+	tests := []struct {
+		name     string
+		todos    []Todo
+		width    int
+		expected string
+	}{
+		{
+			name: "multi-line note",
+			todos: []Todo{
+				{Name: "Plan trip", Status: "open", Notes: "Book flights\n\nAsk about the hotel"},
+			},
+			width:    80,
+			expected: "○ Plan trip\n  Book flights\n\n  Ask about the hotel",
+		},
+		{
+			name: "long note wraps",
+			todos: []Todo{
+				{Name: "Plan trip", Status: "open", Notes: "Compare the prices of the early and late flights"},
+			},
+			width:    24,
+			expected: "○ Plan trip\n  Compare the prices of\n  the early and late\n  flights",
+		},
+		{
+			name: "word longer than the width",
+			todos: []Todo{
+				{Name: "Read", Status: "open", Notes: "see https://example.com/a/long/path"},
+			},
+			width:    12,
+			expected: "○ Read\n  see\n  https://example.com/a/long/path",
+		},
+		{
+			name: "todo without notes",
+			todos: []Todo{
+				{Name: "Buy groceries", Status: "open"},
+				{Name: "Call dentist", Status: "open", Notes: "Ask about Tuesday"},
+			},
+			width:    80,
+			expected: "○ Buy groceries\n○ Call dentist\n  Ask about Tuesday",
+		},
+		{
+			name: "notes hidden",
+			todos: []Todo{
+				{Name: "Call dentist", Status: "open", Notes: "Ask about Tuesday"},
+			},
+			expected: "○ Call dentist",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := formatTodosDetailed(tt.todos, false, false, tt.width, false, false)
+			if result != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestWrapLine(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		width    int
+		indent   int
+		expected string
+	}{
+		{
+			name:     "wraps between words",
+			text:     "Write the quarterly report for the team",
+			width:    21,
+			indent:   2,
+			expected: "Write the quarterly\n  report for the team",
+		},
+		{
+			name:     "zero width doesn't wrap",
+			text:     "Write the quarterly report for the team",
+			width:    0,
+			indent:   2,
+			expected: "Write the quarterly report for the team",
+		},
+		{
+			name:     "exact fit",
+			text:     "Write the report",
+			width:    18,
+			indent:   2,
+			expected: "Write the report",
+		},
+		{
+			name:     "one column over",
+			text:     "Write the report",
+			width:    17,
+			indent:   2,
+			expected: "Write the\n  report",
+		},
+		{
+			name:     "word longer than the width",
+			text:     "Read https://example.com/a/long/path today",
+			width:    16,
+			indent:   2,
+			expected: "Read\n  https://example.com/a/long/path\n  today",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := wrapLine(tt.text, tt.width, tt.indent)
+			if result != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestFormatTodosForDisplay_Width(t *testing.T) {
+	todos := []Todo{
+		{Name: "Write the quarterly report for the team", Status: "open"},
+		{Name: "Short", Status: "completed"},
+	}
+
+	expected := "○ Write the quarterly\n  report for the team\n✔︎ Short"
+	if result := formatTodosForDisplay(todos, false, false, 21); result != expected {
+		t.Errorf("expected %q, got %q", expected, result)
+	}
+}
+
 func TestGetStatusSymbol(t *testing.T) {
+	tests := []struct {
+		status  string
+		unicode string
+		ascii   string
+		columns int
+	}{
+		{"open", "○ ", "[ ] ", 2},
+		{"completed", "✔︎ ", "[x] ", 2},
+		{"canceled", "✕ ", "[-] ", 2},
+		{"cancelled", "✕ ", "[-] ", 2},
+		{"unknown", "", "", 0},
+		{"", "", "", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run("status_"+tt.status, func(t *testing.T) {
+			if result := getStatusSymbol(tt.status, false); result != tt.unicode {
+				t.Errorf("expected %q, got %q", tt.unicode, result)
+			}
+			if result := getStatusSymbol(tt.status, true); result != tt.ascii {
+				t.Errorf("expected ASCII %q, got %q", tt.ascii, result)
+			}
+			if columns := statusSymbolColumns(tt.status, false); columns != tt.columns {
+				t.Errorf("expected %d columns, got %d", tt.columns, columns)
+			}
+			if columns := statusSymbolColumns(tt.status, true); columns != len(tt.ascii) {
+				t.Errorf("expected %d ASCII columns, got %d", len(tt.ascii), columns)
+			}
+		})
+	}
+}
+
+func TestFormatTodosForDisplay_ASCII(t *testing.T) {
+	todos := []Todo{
+		{Name: "Write the quarterly report", Status: "open"},
+		{Name: "Buy milk", Status: "completed"},
+		{Name: "Old plan", Status: "canceled"},
+	}
+
+	expected := "[ ] Write the\n    quarterly report\n[x] Buy milk\n[-] Old plan"
+	if result := formatTodosForDisplay(todos, false, true, 20); result != expected {
+		t.Errorf("expected %q, got %q", expected, result)
+	}
+
+	colored := "[ ] Write the quarterly report\n\x1b[32m[x]\x1b[0m Buy milk\n\x1b[31m[-]\x1b[0m Old plan"
+	if result := formatTodosForDisplay(todos, true, true, 0); result != colored {
+		t.Errorf("expected %q, got %q", colored, result)
+	}
+}
+
+func TestColorizeSymbol(t *testing.T) {
 	tests := []struct {
 		status   string
+		enabled  bool
 		expected string
 	}{
-		{"open", "○ "},
-		{"completed", "✔︎ "},
-		{"canceled", "✕ "},
-		{"unknown", ""},
-		{"", ""},
+		{"open", false, "○ "},
+		{"completed", false, "✔︎ "},
+		{"canceled", false, "✕ "},
+		{"open", true, "○ "},
+		{"completed", true, "\x1b[32m✔︎\x1b[0m "},
+		{"canceled", true, "\x1b[31m✕\x1b[0m "},
+		{"cancelled", true, "\x1b[31m✕\x1b[0m "},
+		{"unknown", true, ""},
 	}
 
 	for _, tt := range tests {
-		t.Run("status_"+tt.status, func(t *testing.T) {
-			result := getStatusSymbol(tt.status)
+		t.Run(fmt.Sprintf("%s enabled=%v", tt.status, tt.enabled), func(t *testing.T) {
+			result := colorizeSymbol(tt.status, tt.enabled, false)
 			if result != tt.expected {
 				t.Errorf("expected %q, got %q", tt.expected, result)
 			}
@@ -90,9 +352,28 @@ func TestGetStatusSymbol(t *testing.T) {
 	}
 }
 
+func TestFormatTodosForDisplay_Color(t *testing.T) {
+	todos := []Todo{
+		{Name: "Buy groceries", Status: "open"},
+		{Name: "Write report", Status: "completed"},
+		{Name: "Call dentist", Status: "canceled"},
+	}
+
+	plain := formatTodosForDisplay(todos, false, false, 0)
+	if strings.Contains(plain, "\x1b[") {
+		t.Errorf("expected no escape codes when color is disabled, got %q", plain)
+	}
+
+	colored := formatTodosForDisplay(todos, true, false, 0)
+	expected := "○ Buy groceries\n\x1b[32m✔︎\x1b[0m Write report\n\x1b[31m✕\x1b[0m Call dentist"
+	if colored != expected {
+		t.Errorf("expected %q, got %q", expected, colored)
+	}
+}
+
 func TestFormatTodoAsJSONL(t *testing.T) {
 	creationDate := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
-	dueDate := time.Date(2024, 1, 20, 0, 0, 0, 0, time.UTC)
+	dueDate := Date{time.Date(2024, 1, 20, 0, 0, 0, 0, time.Local)}
 
 	tests := []struct {
 		name     string
@@ -151,6 +432,24 @@ func TestFormatTodoAsJSONL(t *testing.T) {
 				if len(tags) != 2 {
 					t.Errorf("expected 2 tags, got %d", len(tags))
 				}
+				// Due dates are whole days, while other dates stay timestamps
+				if result["dueDate"] != "2024-01-20" {
+					t.Errorf("expected dueDate '2024-01-20', got %v", result["dueDate"])
+				}
+				if result["creationDate"] != "2024-01-15T10:30:00Z" {
+					t.Errorf("expected creationDate '2024-01-15T10:30:00Z', got %v", result["creationDate"])
+				}
+
+				var roundTrip Todo
+				if err := json.Unmarshal([]byte(jsonStr), &roundTrip); err != nil {
+					t.Fatalf("round trip failed: %v", err)
+				}
+				if roundTrip.DueDate == nil || !roundTrip.DueDate.Equal(dueDate.Time) {
+					t.Errorf("expected due date %v after round trip, got %v", dueDate, roundTrip.DueDate)
+				}
+				if roundTrip.CreationDate == nil || !roundTrip.CreationDate.Equal(creationDate) {
+					t.Errorf("expected creation date %v after round trip, got %v", creationDate, roundTrip.CreationDate)
+				}
 			},
 		},
 		{
@@ -167,6 +466,29 @@ func TestFormatTodoAsJSONL(t *testing.T) {
 				if strings.Contains(jsonStr, "tagNames") {
 					t.Error("should not contain 'tagNames' field")
 				}
+				if strings.Contains(jsonStr, "checklistItems") {
+					t.Error("should not contain 'checklistItems' field")
+				}
+				if strings.Contains(jsonStr, `"id"`) {
+					t.Error("should not contain 'id' field")
+				}
+			},
+		},
+		{
+			name: "todo with checklist items",
+			todo: Todo{
+				Name:   "Pack for trip",
+				Status: "open",
+				ChecklistItems: []ChecklistItem{
+					{Name: "Passport", Completed: true},
+					{Name: "Charger"},
+				},
+			},
+			validate: func(t *testing.T, jsonStr string) {
+				expected := `"checklistItems":[{"name":"Passport","completed":true},{"name":"Charger","completed":false}]`
+				if !strings.Contains(jsonStr, expected) {
+					t.Errorf("expected %s in %s", expected, jsonStr)
+				}
 			},
 		},
 	}
@@ -182,6 +504,419 @@ func TestFormatTodoAsJSONL(t *testing.T) {
 	}
 }
 
+func TestFormatTodosAsJSON(t *testing.T) {
+	due := Date{time.Date(2024, 1, 20, 0, 0, 0, 0, time.Local)}
+	todos := []Todo{
+		{ID: "1", Name: "Write report", Status: "open", DueDate: &due, TagNames: []string{"Work"}},
+		{ID: "2", Name: "Buy milk", Status: "completed"},
+	}
+
+	t.Run("compact", func(t *testing.T) {
+		result, err := formatTodosAsJSON(todos, false)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if strings.Contains(result, "\n") {
+			t.Errorf("expected a single line, got %q", result)
+		}
+	})
+
+	t.Run("indented", func(t *testing.T) {
+		result, err := formatTodosAsJSON(todos, true)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !strings.HasPrefix(result, "[\n  {\n    \"id\": \"1\",\n") {
+			t.Errorf("expected two-space indentation, got:\n%s", result)
+		}
+
+		var parsed []Todo
+		if err := json.Unmarshal([]byte(result), &parsed); err != nil {
+			t.Fatalf("invalid JSON: %v", err)
+		}
+		if len(parsed) != len(todos) {
+			t.Fatalf("expected %d todos, got %d", len(todos), len(parsed))
+		}
+		for i := range todos {
+			if parsed[i].ID != todos[i].ID || parsed[i].Name != todos[i].Name || parsed[i].Status != todos[i].Status {
+				t.Errorf("todo %d: expected %+v, got %+v", i, todos[i], parsed[i])
+			}
+		}
+		if parsed[0].DueDate == nil || !parsed[0].DueDate.Equal(due.Time) || parsed[0].TagNames[0] != "Work" {
+			t.Errorf("expected due date and tags to survive, got %+v", parsed[0])
+		}
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		for _, indent := range []bool{false, true} {
+			result, err := formatTodosAsJSON(nil, indent)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result != "[]" {
+				t.Errorf("expected [], got %q", result)
+			}
+		}
+	})
+}
+
+func TestFormatTodosAsCSV(t *testing.T) {
+	due := Date{time.Date(2024, 1, 20, 0, 0, 0, 0, time.UTC)}
+
+	tests := []struct {
+		name     string
+		todos    []Todo
+		expected string
+	}{
+		{
+			name:     "header only for empty list",
+			todos:    []Todo{},
+			expected: "name,status,notes,due,tags,area,project\n",
+		},
+		{
+			name: "simple row",
+			todos: []Todo{
+				{Name: "Buy groceries", Status: "open", DueDate: &due, TagNames: []string{"Home", "Errands"}, Area: "Personal"},
+			},
+			expected: "name,status,notes,due,tags,area,project\n" +
+				"Buy groceries,open,,2024-01-20T00:00:00Z,Home;Errands,Personal,\n",
+		},
+		{
+			name: "fields with commas, quotes, and newlines are quoted",
+			todos: []Todo{
+				{Name: "Eggs, milk, bread", Status: "open", Notes: "Say \"hi\"\nto the baker", Project: "Shopping"},
+			},
+			expected: "name,status,notes,due,tags,area,project\n" +
+				"\"Eggs, milk, bread\",open,\"Say \"\"hi\"\"\nto the baker\",,,,Shopping\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := formatTodosAsCSV(tt.todos, true)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result != tt.expected {
+				t.Errorf("expected:\n%q\ngot:\n%q", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestFormatTodosWithTemplate(t *testing.T) {
+	completed := time.Date(2024, 3, 5, 14, 30, 0, 0, time.Local)
+	due := Date{time.Date(2024, 3, 8, 0, 0, 0, 0, time.Local)}
+	todos := []Todo{
+		{Name: "Ship release", Area: "Work", CompletionDate: &completed, DueDate: &due, TagNames: []string{"urgent", "deploy"}},
+		{Name: "Call mom"},
+	}
+
+	tests := []struct {
+		name        string
+		tmpl        string
+		expected    string
+		expectedErr string
+	}{
+		{
+			name:     "simple fields",
+			tmpl:     "{{.Name}} ({{.Area}})",
+			expected: "Ship release (Work)\nCall mom ()",
+		},
+		{
+			name:     "dates",
+			tmpl:     `{{.Name}}: done {{date "2006-01-02 15:04" .CompletionDate}}, due {{date "Jan 2" .DueDate}}`,
+			expected: "Ship release: done 2024-03-05 14:30, due Mar 8\nCall mom: done , due ",
+		},
+		{
+			name:     "ranging over tags",
+			tmpl:     "{{.Name}}{{range .TagNames}} #{{.}}{{end}}",
+			expected: "Ship release #urgent #deploy\nCall mom",
+		},
+		{
+			name:        "parse error",
+			tmpl:        "{{.Name",
+			expectedErr: "ERROR: invalid --format template: template: format:1: unclosed action",
+		},
+		{
+			name:        "unknown field",
+			tmpl:        "{{.Title}}",
+			expectedErr: `ERROR: --format template failed on to-do "Ship release": template: format:1:2: executing "format" at <.Title>: can't evaluate field Title in type main.Todo`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := formatTodosWithTemplate(todos, tt.tmpl)
+			if tt.expectedErr != "" {
+				if err == nil || err.Error() != tt.expectedErr {
+					t.Fatalf("expected error %q, got %v", tt.expectedErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestFormatTodosAsMarkdown(t *testing.T) {
+	tests := []struct {
+		name     string
+		todos    []Todo
+		expected string
+	}{
+		{
+			name:     "empty list",
+			todos:    []Todo{},
+			expected: "",
+		},
+		{
+			name:     "open todo",
+			todos:    []Todo{{Name: "Buy groceries", Status: "open"}},
+			expected: "- [ ] Buy groceries",
+		},
+		{
+			name:     "completed todo",
+			todos:    []Todo{{Name: "Write report", Status: "completed"}},
+			expected: "- [x] Write report",
+		},
+		{
+			name:     "canceled todo",
+			todos:    []Todo{{Name: "Call dentist", Status: "canceled"}},
+			expected: "- [x] ~~Call dentist~~",
+		},
+		{
+			name:     "tagged todo",
+			todos:    []Todo{{Name: "Review PR", Status: "open", TagNames: []string{"Work", "Urgent"}}},
+			expected: "- [ ] Review PR #Work #Urgent",
+		},
+		{
+			name: "multiple todos",
+			todos: []Todo{
+				{Name: "Task 1", Status: "open"},
+				{Name: "Task 2", Status: "completed"},
+			},
+			expected: "- [ ] Task 1\n- [x] Task 2",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := formatTodosAsMarkdown(tt.todos)
+			if result != tt.expected {
+				t.Errorf("expected:\n%s\ngot:\n%s", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestFormatTodosAsTable(t *testing.T) {
+	due := Date{time.Date(2024, 1, 20, 0, 0, 0, 0, time.Local)}
+	completed := time.Date(2024, 1, 21, 17, 45, 30, 0, time.Local)
+
+	tests := []struct {
+		name     string
+		todos    []Todo
+		expected string
+	}{
+		{
+			name: "aligned columns",
+			todos: []Todo{
+				{Name: "Buy groceries", Status: "open", Area: "Personal", Project: "House"},
+				{Name: "Write report", Status: "completed", Area: "Work", Project: "Q1 Goals", DueDate: &due},
+			},
+			expected: "STATUS     NAME           AREA      PROJECT   DUE\n" +
+				"open       Buy groceries  Personal  House\n" +
+				"completed  Write report   Work      Q1 Goals  2024-01-20",
+		},
+		{
+			name: "project column omitted when no todo has a project",
+			todos: []Todo{
+				{Name: "Buy groceries", Status: "open", Area: "Personal"},
+				{Name: "Write report", Status: "open", Area: "Work"},
+			},
+			expected: "STATUS  NAME           AREA\n" +
+				"open    Buy groceries  Personal\n" +
+				"open    Write report   Work",
+		},
+		{
+			name: "completion times",
+			todos: []Todo{
+				{Name: "Write report", Status: "completed", CompletionDate: &completed},
+				{Name: "Old task", Status: "canceled"},
+			},
+			expected: "STATUS     NAME          COMPLETED\n" +
+				"completed  Write report  2024-01-21 17:45\n" +
+				"canceled   Old task",
+		},
+		{
+			name:     "only required columns",
+			todos:    []Todo{{Name: "Task", Status: "open"}},
+			expected: "STATUS  NAME\nopen    Task",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := formatTodosAsTable(tt.todos, true)
+			if result != tt.expected {
+				t.Errorf("expected:\n%q\ngot:\n%q", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestFormatTodosWithoutHeader(t *testing.T) {
+	todos := []Todo{
+		{Name: "Buy groceries", Status: "open", Area: "Personal"},
+		{Name: "Write report", Status: "completed", Area: "Work"},
+	}
+
+	csvOutput, err := formatTodosAsCSV(todos, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expected := "Buy groceries,open,,,,Personal,\nWrite report,completed,,,,Work,\n"; csvOutput != expected {
+		t.Errorf("expected CSV:\n%q\ngot:\n%q", expected, csvOutput)
+	}
+	if csvOutput, _ := formatTodosAsCSV(nil, false); csvOutput != "" {
+		t.Errorf("expected no CSV output for an empty list, got %q", csvOutput)
+	}
+
+	// Columns stay aligned to the widest value without the header row
+	if table, expected := formatTodosAsTable(todos, false), "open       Buy groceries  Personal\ncompleted  Write report   Work"; table != expected {
+		t.Errorf("expected table:\n%q\ngot:\n%q", expected, table)
+	}
+}
+
+func TestFormatTodosWithHeader(t *testing.T) {
+	tests := []struct {
+		name     string
+		todos    []Todo
+		expected string
+	}{
+		{"empty list", nil, "Today (no to-dos)"},
+		{"single to-do", []Todo{{Name: "One"}}, "Today (1 to-do):"},
+		{"several to-dos", []Todo{{Name: "One"}, {Name: "Two"}, {Name: "Three"}}, "Today (3 to-dos):"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := formatTodosWithHeader("Today", tt.todos)
+			if result != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestGroupTodosByDate(t *testing.T) {
+	jan18 := time.Date(2024, 1, 18, 0, 0, 0, 0, time.Local)
+	jan19 := time.Date(2024, 1, 19, 0, 0, 0, 0, time.Local)
+	todos := []Todo{
+		{Name: "Later", Status: "open", ActivationDate: &jan19},
+		{Name: "Sooner", Status: "open", ActivationDate: &jan18},
+		{Name: "Also sooner", Status: "open", ActivationDate: &jan18},
+		{Name: "Unscheduled", Status: "open"},
+	}
+
+	groups := groupTodosByDate(todos)
+	if len(groups) != 3 {
+		t.Fatalf("expected 3 groups, got %d", len(groups))
+	}
+	if len(groups["2024-01-18"]) != 2 || groups["2024-01-18"][0].Name != "Sooner" {
+		t.Errorf("unexpected 2024-01-18 group: %+v", groups["2024-01-18"])
+	}
+	if len(groups["2024-01-19"]) != 1 {
+		t.Errorf("unexpected 2024-01-19 group: %+v", groups["2024-01-19"])
+	}
+	if len(groups[""]) != 1 {
+		t.Errorf("unexpected undated group: %+v", groups[""])
+	}
+}
+
+func TestFormatGroupedByDate(t *testing.T) {
+	jan18 := time.Date(2024, 1, 18, 0, 0, 0, 0, time.Local)
+	jan19 := time.Date(2024, 1, 19, 0, 0, 0, 0, time.Local)
+	todos := []Todo{
+		{Name: "Unscheduled", Status: "open"},
+		{Name: "Later", Status: "open", ActivationDate: &jan19, TagNames: []string{"Home"}},
+		{Name: "Sooner", Status: "open", ActivationDate: &jan18},
+	}
+
+	tests := []struct {
+		name     string
+		todos    []Todo
+		long     bool
+		expected string
+	}{
+		{
+			name:     "dates in order with undated last",
+			todos:    todos,
+			expected: "2024-01-18:\n○ Sooner\n\n2024-01-19:\n○ Later\n\nNo date:\n○ Unscheduled",
+		},
+		{
+			name:     "long",
+			todos:    todos[1:],
+			long:     true,
+			expected: "2024-01-18:\n○ Sooner\n\n2024-01-19:\n○ Later  #Home",
+		},
+		{
+			name:     "empty",
+			expected: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := formatGroupedByDate(tt.todos, tt.long, false, false, 0, 0)
+			if result != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestFormatTodosGroupedBy(t *testing.T) {
+	todos := []Todo{
+		{Name: "Loose end", Status: "completed"},
+		{Name: "Ship it", Status: "completed", Area: "Work", Project: "Launch"},
+		{Name: "Water plants", Status: "completed", Area: "Home"},
+		{Name: "Write docs", Status: "completed", Area: "Work", Project: "Launch"},
+	}
+
+	tests := []struct {
+		name     string
+		key      string
+		expected string
+	}{
+		{
+			name:     "by area with one ungrouped item",
+			key:      "area",
+			expected: "Home:\n✔︎ Water plants\n\nWork:\n✔︎ Ship it\n✔︎ Write docs\n\n(none):\n✔︎ Loose end",
+		},
+		{
+			name:     "by project",
+			key:      "project",
+			expected: "Launch:\n✔︎ Ship it\n✔︎ Write docs\n\n(none):\n✔︎ Loose end\n✔︎ Water plants",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := formatTodosGroupedBy(todos, tt.key, false, false, 0)
+			if result != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, result)
+			}
+		})
+	}
+}
+
 func TestFormatOperationResult(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -215,3 +950,214 @@ func TestFormatOperationResult(t *testing.T) {
 		})
 	}
 }
+
+func TestProjectTodoFields(t *testing.T) {
+	due := Date{time.Date(2024, 1, 20, 0, 0, 0, 0, time.Local)}
+	todo := Todo{
+		ID:       "abc",
+		Name:     "Review PR",
+		Notes:    "Some notes",
+		Status:   "open",
+		DueDate:  &due,
+		TagNames: []string{"Work"},
+		Area:     "Projects",
+	}
+
+	t.Run("two fields", func(t *testing.T) {
+		projected, err := projectTodoFields(todo, []string{"name", "due"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(projected) != 2 {
+			t.Errorf("expected 2 keys, got %v", projected)
+		}
+		if projected["name"] != "Review PR" || projected["dueDate"] != "2024-01-20" {
+			t.Errorf("unexpected projection %v", projected)
+		}
+	})
+
+	t.Run("unset field is left out", func(t *testing.T) {
+		projected, err := projectTodoFields(todo, []string{"name", "project"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if _, ok := projected["project"]; ok || len(projected) != 1 {
+			t.Errorf("expected only name, got %v", projected)
+		}
+	})
+
+	t.Run("unknown field", func(t *testing.T) {
+		_, err := projectTodoFields(todo, []string{"name", "priority"})
+		if err == nil || !strings.HasPrefix(err.Error(), `ERROR: unknown field "priority" in --fields`) {
+			t.Errorf("expected unknown field error, got %v", err)
+		}
+	})
+}
+
+func TestFormatTodoAsJSONLWithURL(t *testing.T) {
+	tests := []struct {
+		name      string
+		todo      Todo
+		fields    []string
+		expected  string
+		expectErr string
+	}{
+		{
+			name:     "all fields",
+			todo:     Todo{ID: "ABC123", Name: "Task", Status: "open"},
+			expected: `{"id":"ABC123","name":"Task","status":"open","thingsURL":"things:///show?id=ABC123"}`,
+		},
+		{
+			name:     "special characters in the id",
+			todo:     Todo{ID: "2Xr4 ab&c/d?e=f", Name: "Task", Status: "open"},
+			fields:   []string{"name"},
+			expected: `{"name":"Task","thingsURL":"things:///show?id=2Xr4%20ab%26c%2Fd%3Fe%3Df"}`,
+		},
+		{
+			name:      "no id",
+			todo:      Todo{Name: "Task", Status: "open"},
+			expectErr: `ERROR: can't link to to-do "Task" without its id`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := formatTodoAsJSONLWithURL(tt.todo, tt.fields)
+			if tt.expectErr != "" {
+				if err == nil || err.Error() != tt.expectErr {
+					t.Errorf("expected error %q, got %v", tt.expectErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result != tt.expected {
+				t.Errorf("expected %s, got %s", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestParseFields(t *testing.T) {
+	fields, err := parseFields(" name, due ,,tags")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Join(fields, ",") != "name,due,tags" {
+		t.Errorf("expected name,due,tags, got %v", fields)
+	}
+
+	if _, err := parseFields("name,bogus"); err == nil {
+		t.Error("expected error for unknown field")
+	}
+}
+
+func TestFormatOperationResultJSON(t *testing.T) {
+	tests := []struct {
+		name     string
+		result   OperationResult
+		expected string
+	}{
+		{
+			name:     "success",
+			result:   OperationResult{Success: true, Message: "To-do added successfully!", AffectedCount: 1, IDs: []string{"ABC123"}},
+			expected: `{"success":true,"message":"To-do added successfully!","affectedCount":1,"ids":["ABC123"]}`,
+		},
+		{
+			name:     "failure",
+			result:   OperationResult{Success: false, Message: `ERROR: To-do "Task" not found in list "Inbox"`},
+			expected: `{"success":false,"message":"ERROR: To-do \"Task\" not found in list \"Inbox\"","affectedCount":0}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := formatOperationResultJSON(tt.result)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result != tt.expected {
+				t.Errorf("expected %s, got %s", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestFormatAddResultJSON(t *testing.T) {
+	result, err := formatAddResultJSON("Buy milk", OperationResult{Success: true, Message: `To-do "Buy milk" added successfully to list "Work"!`, AffectedCount: 1, IDs: []string{"T1"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := `{"name":"Buy milk","success":true,"message":"To-do \"Buy milk\" added successfully to list \"Work\"!","affectedCount":1,"ids":["T1"]}`
+	if result != expected {
+		t.Errorf("expected %s, got %s", expected, result)
+	}
+}
+
+func TestFormatAge(t *testing.T) {
+	created := time.Date(2026, 10, 12, 6, 0, 0, 0, time.UTC)
+	at := func(d time.Duration) *time.Time {
+		completed := created.Add(d)
+		return &completed
+	}
+
+	tests := []struct {
+		name       string
+		creation   *time.Time
+		completion *time.Time
+		expected   string
+	}{
+		{name: "several days", creation: &created, completion: at(76*time.Hour + 30*time.Minute), expected: "3d 4h"},
+		{name: "under a day", creation: &created, completion: at(5*time.Hour + 12*time.Minute), expected: "5h 12m"},
+		{name: "under an hour", creation: &created, completion: at(42 * time.Minute), expected: "42m"},
+		{name: "completed before it was created", creation: &created, completion: at(-time.Hour), expected: "0m"},
+		{name: "no creation date", completion: at(time.Hour), expected: ""},
+		{name: "not completed", creation: &created, expected: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := formatAge(tt.creation, tt.completion); got != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestFormatTodoCounts(t *testing.T) {
+	tests := []struct {
+		name     string
+		todos    []Todo
+		expected string
+	}{
+		{
+			name: "mixed statuses",
+			todos: []Todo{
+				{Name: "A", Status: "open"},
+				{Name: "B", Status: "completed"},
+				{Name: "C", Status: "open"},
+				{Name: "D", Status: "cancelled"},
+				{Name: "E", Status: "open"},
+			},
+			expected: "Work: 3 open, 1 completed, 1 canceled",
+		},
+		{
+			name:     "only completed",
+			todos:    []Todo{{Name: "A", Status: "completed"}},
+			expected: "Work: 1 completed",
+		},
+		{
+			name:     "empty list",
+			expected: "Work: no to-dos",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := formatTodoCounts("Work", tt.todos); result != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, result)
+			}
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// importTodos reads to-dos in the JSONL format written by --jsonl and recreates them in listName
+// Notes, tags, checklist items, due dates, and scheduled dates are preserved; blank lines are ignored
+// Lines that can't be parsed are reported to report with their line number and skipped,
+// unless strict is set, in which case nothing is added and an error is returned
+func importTodos(ctx context.Context, r io.Reader, listName string, strict bool, report io.Writer) (imported, skipped int, err error) {
+	var todos []NewTodo
+	var badLines []string
+
+	scanner := bufio.NewScanner(r)
+	// Notes can be long, so allow lines well beyond bufio's 64KB default
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		var todo Todo
+		if err := json.Unmarshal([]byte(line), &todo); err != nil {
+			badLines = append(badLines, fmt.Sprintf("line %d: %v", lineNumber, err))
+			continue
+		}
+		if todo.Name == "" {
+			badLines = append(badLines, fmt.Sprintf("line %d: to-do has no name", lineNumber))
+			continue
+		}
+
+		todos = append(todos, newTodoFrom(todo))
+	}
+	if err := scanner.Err(); err != nil {
+		return 0, 0, fmt.Errorf("error reading to-dos: %v", err)
+	}
+
+	if strict && len(badLines) > 0 {
+		return 0, 0, fmt.Errorf("ERROR: %s", badLines[0])
+	}
+	for _, badLine := range badLines {
+		fmt.Fprintf(report, "Skipped %s\n", badLine)
+	}
+	skipped = len(badLines)
+
+	results, err := addTodosToList(ctx, listName, todos)
+	if err != nil {
+		return 0, skipped, err
+	}
+	for _, result := range results {
+		if !result.Success {
+			fmt.Fprintln(report, formatOperationResult(result))
+			skipped++
+			continue
+		}
+		imported++
+	}
+	return imported, skipped, nil
+}
+
+// newTodoFrom returns the fields of todo that can be set on a new to-do
+// Checklist items keep their names but not whether they're completed
+func newTodoFrom(todo Todo) NewTodo {
+	var checklist []string
+	for _, item := range todo.ChecklistItems {
+		checklist = append(checklist, item.Name)
+	}
+
+	return NewTodo{
+		Name:           todo.Name,
+		Tags:           todo.TagNames,
+		Notes:          todo.Notes,
+		ChecklistItems: checklist,
+		DueDate:        todo.DueDate.timePtr(),
+		ActivationDate: todo.ActivationDate,
+	}
+}
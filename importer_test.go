@@ -0,0 +1,125 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestImportTodos(t *testing.T) {
+	goodFile := `{"name":"Plain task","status":"open"}
+{"name":"Tagged task","status":"open","notes":"Some notes","tagNames":["Home","Errands"]}
+
+{"name":"Dated task","status":"open","dueDate":"2024-01-20T00:00:00Z","activationDate":"2024-01-18T00:00:00Z"}
+`
+	badLineFile := `{"name":"First","status":"open"}
+not json
+{"name":"Third","status":"open"}
+`
+
+	tests := []struct {
+		name           string
+		input          string
+		strict         bool
+		output         string
+		expectErr      bool
+		expectImported int
+		expectSkipped  int
+		expectCalls    int
+		expectReport   string
+	}{
+		{
+			name:           "good file",
+			input:          goodFile,
+			output:         `[{"success":true},{"success":true},{"success":true}]`,
+			expectImported: 3,
+			expectCalls:    1,
+		},
+		{
+			name:           "bad line in lenient mode",
+			input:          badLineFile,
+			output:         `[{"success":true},{"success":true}]`,
+			expectImported: 2,
+			expectSkipped:  1,
+			expectCalls:    1,
+			expectReport:   "Skipped line 2:",
+		},
+		{
+			name:        "bad line in strict mode",
+			input:       badLineFile,
+			strict:      true,
+			expectErr:   true,
+			expectCalls: 0,
+		},
+		{
+			name:           "failed add is counted as skipped",
+			input:          goodFile,
+			output:         `[{"success":true},{"success":false,"message":"ERROR: nope"},{"success":true}]`,
+			expectImported: 2,
+			expectSkipped:  1,
+			expectCalls:    1,
+			expectReport:   `ERROR: nope (to-do "Tagged task")`,
+		},
+		{
+			name:           "missing name",
+			input:          `{"status":"open"}`,
+			expectImported: 0,
+			expectSkipped:  1,
+			expectReport:   "line 1: to-do has no name",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cleanup := setupMockExecutor(tt.output, nil)
+			defer cleanup()
+
+			var report strings.Builder
+			imported, skipped, err := importTodos(context.Background(), strings.NewReader(tt.input), "Work", tt.strict, &report)
+
+			if tt.expectErr {
+				if err == nil || !strings.HasPrefix(err.Error(), "ERROR: line 2:") {
+					t.Errorf("expected line 2 error, got %v", err)
+				}
+			} else if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if imported != tt.expectImported {
+				t.Errorf("expected %d imported, got %d", tt.expectImported, imported)
+			}
+			if skipped != tt.expectSkipped {
+				t.Errorf("expected %d skipped, got %d", tt.expectSkipped, skipped)
+			}
+			if calls := len(executor.(*MockExecutor).calls); calls != tt.expectCalls {
+				t.Errorf("expected %d osascript calls, got %d", tt.expectCalls, calls)
+			}
+			if !strings.Contains(report.String(), tt.expectReport) {
+				t.Errorf("expected report containing %q, got %q", tt.expectReport, report.String())
+			}
+		})
+	}
+}
+
+func TestImportTodos_PreservesProperties(t *testing.T) {
+	cleanup := setupMockExecutor(`[{"success":true}]`, nil)
+	defer cleanup()
+
+	input := `{"name":"Dated task","notes":"Some notes","tagNames":["Home","Errands"],"dueDate":"2024-01-20T00:00:00Z","activationDate":"2024-01-18T00:00:00Z"}`
+	if _, _, err := importTodos(context.Background(), strings.NewReader(input), "Work", false, &strings.Builder{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	script := executor.(*MockExecutor).lastScript()
+	for _, expected := range []string{
+		`"name":"Dated task"`,
+		`"tags":["Home","Errands"]`,
+		`"notes":"Some notes"`,
+		`"dueDate":"2024-01-20T00:00:00Z"`,
+		`"activationDate":"2024-01-18T00:00:00Z"`,
+	} {
+		if !strings.Contains(script, expected) {
+			t.Errorf("expected script to contain %s", expected)
+		}
+	}
+}
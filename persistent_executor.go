@@ -0,0 +1,200 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+	"sync"
+)
+
+// persistentDelimiter ends each script sent to the persistent process and each result it sends back
+// It's the ASCII record separator on a line of its own, which no script or result should contain
+const persistentDelimiter = "\x1e"
+
+// jxaScriptServer is a JXA program that evaluates scripts read from stdin one at a time,
+// writing each script's completion value (as osascript -e would print it) followed by the delimiter
+const jxaScriptServer = `
+ObjC.import('Foundation');
+var stdin = $.NSFileHandle.fileHandleWithStandardInput;
+var stdout = $.NSFileHandle.fileHandleWithStandardOutput;
+var delimiter = '\n\x1e\n';
+var buffer = '';
+
+function write(text) {
+    stdout.writeData($(text).dataUsingEncoding($.NSUTF8StringEncoding));
+}
+
+while (true) {
+    var data = stdin.availableData;
+    if (data.length === 0) break;
+    buffer += $.NSString.alloc.initWithDataEncoding(data, $.NSUTF8StringEncoding).js;
+
+    var end;
+    while ((end = buffer.indexOf(delimiter)) !== -1) {
+        var script = buffer.slice(0, end);
+        buffer = buffer.slice(end + delimiter.length);
+        var result;
+        try {
+            result = eval(script);
+        } catch (e) {
+            result = 'ERROR: ' + e.message;
+        }
+        write((result === undefined ? '' : String(result)) + delimiter);
+    }
+}
+`
+
+// PersistentExecutor implements CommandExecutor by sending JXA scripts to one long-lived osascript process,
+// saving the startup cost of a new process per script
+// Other commands, and every command if the process can't be started, are run by the fallback executor
+type PersistentExecutor struct {
+	// command starts the process; it must read delimited scripts from stdin and answer each with a delimited result
+	command  []string
+	fallback CommandExecutor
+
+	mu     sync.Mutex
+	cmd    *exec.Cmd
+	stdin  io.WriteCloser
+	stdout *bufio.Reader
+	failed bool // the process couldn't be started, so the fallback is used from now on
+}
+
+// NewPersistentExecutor returns a PersistentExecutor running scripts through osascript, falling back to DefaultExecutor
+// The process is started on the first script; call Close when done to stop it
+func NewPersistentExecutor() *PersistentExecutor {
+	return &PersistentExecutor{
+		command:  []string{"osascript", "-l", "JavaScript", "-e", jxaScriptServer},
+		fallback: &DefaultExecutor{},
+	}
+}
+
+func (p *PersistentExecutor) Execute(name string, args ...string) ([]byte, error) {
+	return p.ExecuteContext(context.Background(), name, args...)
+}
+
+// ExecuteContext sends JXA scripts to the persistent process and runs anything else with the fallback executor,
+// as it does a script sent while the process is busy with another
+// If ctx ends while waiting for a result, the process is stopped and a new one is started for the next script
+func (p *PersistentExecutor) ExecuteContext(ctx context.Context, name string, args ...string) ([]byte, error) {
+	script, ok := persistentScript(name, args)
+	if !ok {
+		return p.fallback.ExecuteContext(ctx, name, args...)
+	}
+
+	// The process runs one script at a time, so concurrent scripts, such as search's list scans, don't wait for it
+	if !p.mu.TryLock() {
+		return p.fallback.ExecuteContext(ctx, name, args...)
+	}
+	defer p.mu.Unlock()
+
+	if err := p.start(); err != nil {
+		return p.fallback.ExecuteContext(ctx, name, args...)
+	}
+
+	type reply struct {
+		output []byte
+		err    error
+	}
+	// The pipes are captured so stopping the process can't pull them out from under the read
+	stdin, stdout := p.stdin, p.stdout
+	replies := make(chan reply, 1)
+	go func() {
+		output, err := roundTrip(stdin, stdout, script)
+		replies <- reply{output, err}
+	}()
+
+	select {
+	case r := <-replies:
+		if r.err != nil {
+			p.stop()
+		}
+		return r.output, r.err
+	case <-ctx.Done():
+		p.stop()
+		return nil, ctx.Err()
+	}
+}
+
+// Close stops the persistent process, if it's running
+func (p *PersistentExecutor) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.stop()
+	return nil
+}
+
+// persistentScript returns the script of an "osascript -l JavaScript -e <script>" command
+func persistentScript(name string, args []string) (string, bool) {
+	if name != "osascript" || len(args) != 4 || args[0] != "-l" || args[1] != "JavaScript" || args[2] != "-e" {
+		return "", false
+	}
+	return args[3], true
+}
+
+// start launches the process unless it's already running
+func (p *PersistentExecutor) start() error {
+	if p.cmd != nil {
+		return nil
+	}
+	if p.failed {
+		return fmt.Errorf("persistent process unavailable")
+	}
+
+	cmd := exec.Command(p.command[0], p.command[1:]...)
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		p.failed = true
+		return err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		p.failed = true
+		return err
+	}
+	if err := cmd.Start(); err != nil {
+		p.failed = true
+		return err
+	}
+
+	p.cmd = cmd
+	p.stdin = stdin
+	p.stdout = bufio.NewReader(stdout)
+	return nil
+}
+
+// roundTrip sends one script and reads its result, up to the delimiter line
+func roundTrip(stdin io.Writer, stdout *bufio.Reader, script string) ([]byte, error) {
+	if _, err := io.WriteString(stdin, script+"\n"+persistentDelimiter+"\n"); err != nil {
+		return nil, fmt.Errorf("error sending script: %v", err)
+	}
+
+	var output strings.Builder
+	for {
+		line, err := stdout.ReadString('\n')
+		if err != nil {
+			return nil, fmt.Errorf("error reading result: %v", err)
+		}
+		if strings.TrimSuffix(line, "\n") == persistentDelimiter {
+			break
+		}
+		output.WriteString(line)
+	}
+	// osascript ends its output with a newline, which the delimiter line makes part of the result
+	return []byte(output.String()), nil
+}
+
+// stop kills the process so the next script starts a fresh one
+func (p *PersistentExecutor) stop() {
+	if p.cmd == nil {
+		return
+	}
+	p.stdin.Close()
+	_ = p.cmd.Process.Kill()
+	_ = p.cmd.Wait()
+	p.cmd = nil
+	p.stdin = nil
+	p.stdout = nil
+}
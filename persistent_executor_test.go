@@ -0,0 +1,148 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"io"
+	"regexp"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// fakeScriptServer answers each delimited script with its process id, a call count, and the script itself
+const fakeScriptServer = `
+n=0
+script=""
+delimiter=$(printf '\036')
+while IFS= read -r line; do
+    if [ "$line" = "$delimiter" ]; then
+        n=$((n+1))
+        printf 'pid=%s call=%s script=%s\n%s\n' "$$" "$n" "$script" "$delimiter"
+        script=""
+    else
+        script="$script$line"
+    fi
+done
+`
+
+func newFakePersistentExecutor(command ...string) (*PersistentExecutor, *MockExecutor) {
+	fallback := &MockExecutor{outputs: [][]byte{[]byte("fallback")}, errors: []error{nil}}
+	return &PersistentExecutor{command: command, fallback: fallback}, fallback
+}
+
+func TestPersistentExecutor_ReusesOneProcess(t *testing.T) {
+	p, fallback := newFakePersistentExecutor("sh", "-c", fakeScriptServer)
+	defer p.Close()
+
+	resultPattern := regexp.MustCompile(`^pid=(\d+) call=(\d+) script=(.*)\n$`)
+	var pid string
+	for i, script := range []string{"first", "second", "third"} {
+		output, err := p.Execute("osascript", "-l", "JavaScript", "-e", script)
+		if err != nil {
+			t.Fatalf("call %d: unexpected error: %v", i+1, err)
+		}
+		match := resultPattern.FindStringSubmatch(string(output))
+		if match == nil {
+			t.Fatalf("call %d: unexpected output %q", i+1, output)
+		}
+		if i == 0 {
+			pid = match[1]
+		} else if match[1] != pid {
+			t.Errorf("call %d: expected process %s to be reused, got %s", i+1, pid, match[1])
+		}
+		if match[2] != strconv.Itoa(i+1) {
+			t.Errorf("call %d: expected call count %d, got %s", i+1, i+1, match[2])
+		}
+		if match[3] != script {
+			t.Errorf("call %d: expected result for %q, got %q", i+1, script, match[3])
+		}
+	}
+
+	if len(fallback.calls) != 0 {
+		t.Errorf("expected no fallback calls, got %v", fallback.calls)
+	}
+}
+
+func TestPersistentExecutor_Fallback(t *testing.T) {
+	t.Run("process can't start", func(t *testing.T) {
+		p, fallback := newFakePersistentExecutor("/nonexistent/osascript-server")
+		defer p.Close()
+
+		for range 2 {
+			output, err := p.Execute("osascript", "-l", "JavaScript", "-e", "script")
+			if err != nil || string(output) != "fallback" {
+				t.Errorf("expected fallback output, got %q, %v", output, err)
+			}
+		}
+		if len(fallback.calls) != 2 {
+			t.Errorf("expected 2 fallback calls, got %d", len(fallback.calls))
+		}
+	})
+
+	t.Run("other commands", func(t *testing.T) {
+		p, fallback := newFakePersistentExecutor("sh", "-c", fakeScriptServer)
+		defer p.Close()
+
+		output, err := p.Execute("open", "things:///show?id=abc")
+		if err != nil || string(output) != "fallback" {
+			t.Errorf("expected fallback output, got %q, %v", output, err)
+		}
+		if len(fallback.calls) != 1 || fallback.calls[0][0] != "open" {
+			t.Errorf("expected open to go to the fallback, got %v", fallback.calls)
+		}
+		if p.cmd != nil {
+			t.Error("expected the persistent process not to be started")
+		}
+	})
+
+	t.Run("process busy", func(t *testing.T) {
+		p, fallback := newFakePersistentExecutor("sh", "-c", fakeScriptServer)
+		defer p.Close()
+
+		p.mu.Lock()
+		output, err := p.Execute("osascript", "-l", "JavaScript", "-e", "script")
+		p.mu.Unlock()
+		if err != nil || string(output) != "fallback" {
+			t.Errorf("expected fallback output, got %q, %v", output, err)
+		}
+		if len(fallback.calls) != 1 {
+			t.Errorf("expected 1 fallback call, got %d", len(fallback.calls))
+		}
+	})
+}
+
+func TestPersistentExecutor_ClosedAfterCommand(t *testing.T) {
+	cleanup := setupMockExecutorIntegration("", nil)
+	defer cleanup()
+	p, fallback := newFakePersistentExecutor("sh", "-c", fakeScriptServer)
+	executor = p
+
+	// The fake server's answer isn't a list of to-dos, so only what happened to the process matters
+	app := createTestAppWithWriters(io.Discard, io.Discard)
+	_ = app.Run(context.Background(), []string{"things", "show", "--list", "Inbox"})
+
+	if len(fallback.calls) != 0 {
+		t.Errorf("expected the script to go to the persistent process, got fallback calls %v", fallback.calls)
+	}
+	if p.cmd != nil {
+		t.Error("expected the persistent process to be stopped after the command")
+	}
+}
+
+func TestPersistentExecutor_ContextCanceled(t *testing.T) {
+	// A process that never answers
+	p, _ := newFakePersistentExecutor("sh", "-c", "cat > /dev/null")
+	defer p.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err := p.ExecuteContext(ctx, "osascript", "-l", "JavaScript", "-e", "script")
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected deadline exceeded, got %v", err)
+	}
+	if p.cmd != nil {
+		t.Error("expected the stuck process to be stopped")
+	}
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+// setTickingNow replaces the package clock with one that moves forward by step each time it's read
+func setTickingNow(t *testing.T, step time.Duration) {
+	t.Helper()
+	current := time.Date(2026, 10, 15, 9, 0, 0, 0, time.UTC)
+	original := now
+	now = func() time.Time {
+		current = current.Add(step)
+		return current
+	}
+	t.Cleanup(func() { now = original })
+}
+
+func TestPhaseTimer(t *testing.T) {
+	setTickingNow(t, time.Millisecond)
+
+	timer := newPhaseTimer()
+	timer.lap(phaseBuild)
+	timer.lap(phaseExecute)
+	timer.lap(phaseBuild)
+	timer.lap(phaseExecute)
+	timer.lap(phaseParse)
+
+	expected := map[string]time.Duration{
+		phaseBuild:   2 * time.Millisecond,
+		phaseExecute: 2 * time.Millisecond,
+		phaseParse:   time.Millisecond,
+	}
+	if len(timer.durations) != len(expected) {
+		t.Fatalf("expected %d phases, got %v", len(expected), timer.durations)
+	}
+	for phase, duration := range expected {
+		if timer.durations[phase] != duration {
+			t.Errorf("expected %s to take %v, got %v", phase, duration, timer.durations[phase])
+		}
+	}
+	if got, want := timer.String(), "profile: build 2ms, execute 2ms, parse 1ms"; got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+}
+
+func TestPhaseTimer_Nil(t *testing.T) {
+	var timer *phaseTimer
+	timer.lap(phaseBuild) // must not panic
+}
+
+func TestProfileFlag(t *testing.T) {
+	setTickingNow(t, time.Millisecond)
+	cleanup := setupMockExecutorIntegration(`SUCCESS: 1 ["T1"]`, nil)
+	defer cleanup()
+
+	var stdout, stderr strings.Builder
+	app := createTestAppWithWriters(&stdout, &stderr)
+	if err := app.Run(context.Background(), []string{"things", "--profile", "add", "--name", "Task"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if strings.Contains(stdout.String(), "profile:") {
+		t.Errorf("expected no profile on stdout, got %q", stdout.String())
+	}
+	if !strings.HasPrefix(stderr.String(), "profile: build ") {
+		t.Errorf("expected the profile on stderr, got %q", stderr.String())
+	}
+	for _, phase := range []string{phaseBuild, phaseExecute, phaseParse} {
+		if !strings.Contains(stderr.String(), phase+" ") {
+			t.Errorf("expected %s in the profile, got %q", phase, stderr.String())
+		}
+	}
+	if profiler != nil {
+		t.Error("expected the profiler to be cleared after the command")
+	}
+}
+
+func TestProfileFlag_Off(t *testing.T) {
+	cleanup := setupMockExecutorIntegration(`SUCCESS: 1 ["T1"]`, nil)
+	defer cleanup()
+
+	var stderr strings.Builder
+	app := createTestAppWithWriters(&strings.Builder{}, &stderr)
+	if err := app.Run(context.Background(), []string{"things", "add", "--name", "Task"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if stderr.Len() != 0 {
+		t.Errorf("expected nothing on stderr, got %q", stderr.String())
+	}
+}
+
+// Run with -race: search scans lists from several goroutines, each lapping the profiler
+func TestProfileFlag_Search(t *testing.T) {
+	mock := &listMockExecutor{
+		lists:   []string{"Today", "Inbox", "Anytime", "Someday"},
+		outputs: map[string]string{"Today": `[{"id":"1","name":"Write report"}]`, "Inbox": `[]`, "Anytime": `[]`, "Someday": `[]`},
+	}
+	cleanup := setupMockExecutorIntegration("", nil)
+	defer cleanup()
+	executor = mock
+
+	var stdout, stderr strings.Builder
+	app := createTestAppWithWriters(&stdout, &stderr)
+	if err := app.Run(context.Background(), []string{"things", "--profile", "search", "--query", "report"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(stdout.String(), "Write report") {
+		t.Errorf("expected the match on stdout, got %q", stdout.String())
+	}
+	if !strings.HasPrefix(stderr.String(), "profile: build ") {
+		t.Errorf("expected the profile on stderr, got %q", stderr.String())
+	}
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// jxaProjectLookup returns JXA statements setting project to the project named projectName
+// They throw "Project not found" if there's no such project, since byName alone doesn't fail until the project is used
+func jxaProjectLookup(projectName string) string {
+	return fmt.Sprintf(`
+    var project = app.projects.byName('%s');
+    try {
+        project.name();
+    } catch (e) {
+        throw new Error('Project not found');
+    }`, jxaEscape(projectName))
+}
+
+// runProjectOrAreaScript runs a script that changes a project or area and reports 'SUCCESS: 1 ["<id>"]', returning a result with its id
+// A failed lookup becomes a failed result naming projectName or areaName, so callers only need to fill in the success message
+func runProjectOrAreaScript(ctx context.Context, jxaScript, projectName, areaName string) (OperationResult, error) {
+	output, err := runJXA(ctx, jxaScript)
+	if err != nil {
+		return OperationResult{}, err
+	}
+
+	outputStr := strings.TrimSpace(string(output))
+	if strings.HasPrefix(outputStr, "ERROR:") {
+		if err := thingsNotRunning(outputStr); err != nil {
+			return OperationResult{}, err
+		}
+		if strings.Contains(outputStr, "Project not found") {
+			return failedResult(&thingsError{kind: ErrProjectNotFound, message: fmt.Sprintf("ERROR: Project \"%s\" not found", projectName)}), nil
+		}
+		if strings.Contains(outputStr, "Area not found") {
+			return failedResult(&thingsError{kind: ErrAreaNotFound, message: fmt.Sprintf("ERROR: Area \"%s\" not found", areaName)}), nil
+		}
+		if strings.Contains(outputStr, "Area already exists") {
+			return OperationResult{Success: false, Message: fmt.Sprintf("ERROR: Area \"%s\" already exists", areaName)}, nil
+		}
+		return OperationResult{Success: false, Message: outputStr}, nil
+	}
+
+	_, ids := parseMatchResult(outputStr)
+	return OperationResult{Success: true, AffectedCount: 1, IDs: ids}, nil
+}
+
+// createProject creates a project named projectName, in the area named areaName if it's non-empty
+// The area is looked up first, so a missing area doesn't leave a new project behind
+func createProject(ctx context.Context, projectName, areaName string) (OperationResult, error) {
+	var areaLookup, areaAssignment string
+	if areaName != "" {
+		areaLookup = jxaAreaLookup(areaName)
+		areaAssignment = "\n    project.area = area;"
+	}
+
+	jxaScript := fmt.Sprintf(`
+try {
+    var app = Application('Things3');%s
+    var project = app.Project({name: '%s'});
+    app.projects.push(project);%s
+    'SUCCESS: 1 ' + JSON.stringify([project.id()]);
+} catch (e) {
+    'ERROR: ' + e.message;
+}
+`, areaLookup, jxaEscape(projectName), areaAssignment)
+
+	message := fmt.Sprintf("Project \"%s\" created!", projectName)
+	outcome := fmt.Sprintf("create project \"%s\"", projectName)
+	if areaName != "" {
+		message = fmt.Sprintf("Project \"%s\" created in area \"%s\"!", projectName, areaName)
+		outcome += fmt.Sprintf(" in area \"%s\"", areaName)
+	}
+	if dryRun {
+		return dryRunResult(jxaScript, outcome), nil
+	}
+
+	result, err := runProjectOrAreaScript(ctx, jxaScript, projectName, areaName)
+	if err != nil || !result.Success {
+		return result, err
+	}
+	result.Message = message
+	return result, nil
+}
+
+// renameProject renames the project named projectName to newName
+func renameProject(ctx context.Context, projectName, newName string) (OperationResult, error) {
+	jxaScript := fmt.Sprintf(`
+try {
+    var app = Application('Things3');%s
+    project.name = '%s';
+    'SUCCESS: 1 ' + JSON.stringify([project.id()]);
+} catch (e) {
+    'ERROR: ' + e.message;
+}
+`, jxaProjectLookup(projectName), jxaEscape(newName))
+
+	if dryRun {
+		return dryRunResult(jxaScript, fmt.Sprintf("rename project \"%s\" to \"%s\"", projectName, newName)), nil
+	}
+
+	result, err := runProjectOrAreaScript(ctx, jxaScript, projectName, "")
+	if err != nil || !result.Success {
+		return result, err
+	}
+	result.Message = fmt.Sprintf("Project \"%s\" renamed to \"%s\"!", projectName, newName)
+	return result, nil
+}
+
+// completeProject marks the project named projectName as completed
+func completeProject(ctx context.Context, projectName string) (OperationResult, error) {
+	jxaScript := fmt.Sprintf(`
+try {
+    var app = Application('Things3');%s
+    project.status = 'completed';
+    'SUCCESS: 1 ' + JSON.stringify([project.id()]);
+} catch (e) {
+    'ERROR: ' + e.message;
+}
+`, jxaProjectLookup(projectName))
+
+	if dryRun {
+		return dryRunResult(jxaScript, fmt.Sprintf("complete project \"%s\"", projectName)), nil
+	}
+
+	result, err := runProjectOrAreaScript(ctx, jxaScript, projectName, "")
+	if err != nil || !result.Success {
+		return result, err
+	}
+	result.Message = fmt.Sprintf("Project \"%s\" completed!", projectName)
+	return result, nil
+}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestCreateProject(t *testing.T) {
+	tests := []struct {
+		name           string
+		area           string
+		output         string
+		expectSuccess  bool
+		expectMessage  string
+		expectScript   []string
+		rejectScript   []string
+		expectNotFound error
+	}{
+		{
+			name:          "in an area",
+			area:          "Work",
+			output:        `SUCCESS: 1 ["P1"]`,
+			expectSuccess: true,
+			expectMessage: `Project "Q3 Launch" created in area "Work"!`,
+			expectScript:  []string{"app.areas.byName('Work')", "app.Project({name: 'Q3 Launch'})", "app.projects.push(project);", "project.area = area;"},
+		},
+		{
+			name:          "without an area",
+			output:        `SUCCESS: 1 ["P1"]`,
+			expectSuccess: true,
+			expectMessage: `Project "Q3 Launch" created!`,
+			rejectScript:  []string{"app.areas", "project.area"},
+		},
+		{
+			name:           "missing area",
+			area:           "Nowhere",
+			output:         "ERROR: Area not found",
+			expectMessage:  `ERROR: Area "Nowhere" not found`,
+			expectNotFound: ErrAreaNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cleanup := setupMockExecutor(tt.output, nil)
+			defer cleanup()
+
+			result, err := createProject(context.Background(), "Q3 Launch", tt.area)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result.Success != tt.expectSuccess || result.Message != tt.expectMessage {
+				t.Errorf("expected success %v with %q, got %+v", tt.expectSuccess, tt.expectMessage, result)
+			}
+			if tt.expectSuccess && (result.AffectedCount != 1 || len(result.IDs) != 1 || result.IDs[0] != "P1") {
+				t.Errorf("expected the new project's id, got %+v", result)
+			}
+			if tt.expectNotFound != nil && !errors.Is(result.Err, tt.expectNotFound) {
+				t.Errorf("expected %v, got %v", tt.expectNotFound, result.Err)
+			}
+
+			script := executor.(*MockExecutor).lastScript()
+			for _, want := range tt.expectScript {
+				if !strings.Contains(script, want) {
+					t.Errorf("expected script to contain %q:\n%s", want, script)
+				}
+			}
+			for _, unwanted := range tt.rejectScript {
+				if strings.Contains(script, unwanted) {
+					t.Errorf("expected script not to contain %q:\n%s", unwanted, script)
+				}
+			}
+		})
+	}
+}
+
+func TestRenameProject(t *testing.T) {
+	t.Run("renamed", func(t *testing.T) {
+		cleanup := setupMockExecutor(`SUCCESS: 1 ["P1"]`, nil)
+		defer cleanup()
+
+		result, err := renameProject(context.Background(), "Q3 Launch", "Q4 Launch")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !result.Success || result.Message != `Project "Q3 Launch" renamed to "Q4 Launch"!` {
+			t.Errorf("unexpected result: %+v", result)
+		}
+		if script := executor.(*MockExecutor).lastScript(); !strings.Contains(script, "project.name = 'Q4 Launch';") {
+			t.Errorf("expected the rename in the script:\n%s", script)
+		}
+	})
+
+	t.Run("missing project", func(t *testing.T) {
+		cleanup := setupMockExecutor("ERROR: Project not found", nil)
+		defer cleanup()
+
+		result, err := renameProject(context.Background(), "Nowhere", "Q4 Launch")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result.Success || result.Message != `ERROR: Project "Nowhere" not found` {
+			t.Errorf("unexpected result: %+v", result)
+		}
+		if !errors.Is(result.Err, ErrProjectNotFound) {
+			t.Errorf("expected ErrProjectNotFound, got %v", result.Err)
+		}
+	})
+}
+
+func TestCompleteProject(t *testing.T) {
+	cleanup := setupMockExecutor(`SUCCESS: 1 ["P1"]`, nil)
+	defer cleanup()
+
+	result, err := completeProject(context.Background(), "O'Brien's plan")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !result.Success || result.Message != `Project "O'Brien's plan" completed!` {
+		t.Errorf("unexpected result: %+v", result)
+	}
+	script := executor.(*MockExecutor).lastScript()
+	if !strings.Contains(script, `app.projects.byName('O\'Brien\'s plan')`) || !strings.Contains(script, "project.status = 'completed';") {
+		t.Errorf("unexpected script:\n%s", script)
+	}
+}
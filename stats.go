@@ -0,0 +1,92 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Stats summarizes a set of completed to-dos for a review
+// To-dos without an area or project are counted under "(none)"; a to-do counts once for each of its tags
+type Stats struct {
+	Completed int            `json:"completed"`
+	ByArea    map[string]int `json:"byArea"`
+	ByProject map[string]int `json:"byProject"`
+	ByTag     map[string]int `json:"byTag"`
+}
+
+// computeStats totals todos overall and by area, project, and tag
+func computeStats(todos []Todo) Stats {
+	stats := Stats{
+		Completed: len(todos),
+		ByArea:    make(map[string]int),
+		ByProject: make(map[string]int),
+		ByTag:     make(map[string]int),
+	}
+	for _, todo := range todos {
+		stats.ByArea[statsGroupName(todo.Area)]++
+		stats.ByProject[statsGroupName(todo.Project)]++
+		seen := make(map[string]bool)
+		for _, tag := range todo.TagNames {
+			if !seen[tag] {
+				seen[tag] = true
+				stats.ByTag[tag]++
+			}
+		}
+	}
+	return stats
+}
+
+// statsGroupName labels an empty area or project the way --group-by does
+func statsGroupName(name string) string {
+	if name == "" {
+		return "(none)"
+	}
+	return name
+}
+
+// formatStats formats stats for display, with each breakdown sorted by count and then name
+// Breakdowns with nothing in them are left out
+func formatStats(stats Stats) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Completed: %d", stats.Completed)
+	for _, section := range []struct {
+		title  string
+		counts map[string]int
+	}{
+		{"By area", stats.ByArea},
+		{"By project", stats.ByProject},
+		{"By tag", stats.ByTag},
+	} {
+		if len(section.counts) == 0 {
+			continue
+		}
+		names := make([]string, 0, len(section.counts))
+		width := 0
+		for name := range section.counts {
+			names = append(names, name)
+			width = max(width, len([]rune(name)))
+		}
+		sort.Slice(names, func(i, j int) bool {
+			if section.counts[names[i]] != section.counts[names[j]] {
+				return section.counts[names[i]] > section.counts[names[j]]
+			}
+			return names[i] < names[j]
+		})
+		fmt.Fprintf(&b, "\n\n%s:", section.title)
+		for _, name := range names {
+			fmt.Fprintf(&b, "\n  %s%s  %d", name, strings.Repeat(" ", width-len([]rune(name))), section.counts[name])
+		}
+	}
+	return b.String()
+}
+
+// formatStatsAsJSONL formats stats as a single JSON line
+func formatStatsAsJSONL(stats Stats) (string, error) {
+	jsonBytes, err := json.Marshal(stats)
+	if err != nil {
+		return "", fmt.Errorf("error marshaling stats: %v", err)
+	}
+	return string(jsonBytes), nil
+}
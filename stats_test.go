@@ -0,0 +1,77 @@
+package main
+
+import (
+	"maps"
+	"testing"
+)
+
+func statsTestTodos() []Todo {
+	return []Todo{
+		{Name: "Ship release", Status: "completed", Area: "Work", Project: "Launch", TagNames: []string{"urgent", "deploy"}},
+		{Name: "Write changelog", Status: "completed", Area: "Work", Project: "Launch", TagNames: []string{"deploy"}},
+		{Name: "Review PR", Status: "completed", Area: "Work", TagNames: []string{"urgent", "urgent"}},
+		{Name: "Buy milk", Status: "completed", Project: "Errands"},
+		{Name: "Call mom", Status: "completed"},
+		{Name: "Dropped idea", Status: "canceled", Area: "Work", Project: "Launch", TagNames: []string{"urgent"}},
+	}
+}
+
+func TestComputeStats(t *testing.T) {
+	stats := computeStats(filterTodosByStatus(statsTestTodos(), "completed"))
+
+	if stats.Completed != 5 {
+		t.Errorf("expected 5 completed, got %d", stats.Completed)
+	}
+	if want := map[string]int{"Work": 3, "(none)": 2}; !maps.Equal(stats.ByArea, want) {
+		t.Errorf("expected areas %v, got %v", want, stats.ByArea)
+	}
+	if want := map[string]int{"Launch": 2, "Errands": 1, "(none)": 2}; !maps.Equal(stats.ByProject, want) {
+		t.Errorf("expected projects %v, got %v", want, stats.ByProject)
+	}
+	// A tag repeated on one to-do only counts once for it
+	if want := map[string]int{"urgent": 2, "deploy": 2}; !maps.Equal(stats.ByTag, want) {
+		t.Errorf("expected tags %v, got %v", want, stats.ByTag)
+	}
+}
+
+func TestComputeStats_Empty(t *testing.T) {
+	stats := computeStats(nil)
+	if stats.Completed != 0 || len(stats.ByArea) != 0 || len(stats.ByProject) != 0 || len(stats.ByTag) != 0 {
+		t.Errorf("expected empty stats, got %+v", stats)
+	}
+	if got := formatStats(stats); got != "Completed: 0" {
+		t.Errorf("expected only the total, got %q", got)
+	}
+}
+
+func TestFormatStats(t *testing.T) {
+	expected := `Completed: 5
+
+By area:
+  Work    3
+  (none)  2
+
+By project:
+  (none)   2
+  Launch   2
+  Errands  1
+
+By tag:
+  deploy  2
+  urgent  2`
+
+	if got := formatStats(computeStats(filterTodosByStatus(statsTestTodos(), "completed"))); got != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, got)
+	}
+}
+
+func TestFormatStatsAsJSONL(t *testing.T) {
+	got, err := formatStatsAsJSONL(computeStats(filterTodosByStatus(statsTestTodos()[3:], "completed")))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := `{"completed":2,"byArea":{"(none)":2},"byProject":{"(none)":1,"Errands":1},"byTag":{}}`
+	if got != expected {
+		t.Errorf("expected %s, got %s", expected, got)
+	}
+}
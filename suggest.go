@@ -0,0 +1,54 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// closestList returns the candidate most similar to target and its Levenshtein distance, ignoring case
+// Ties go to the earlier candidate; with no candidates, the distance is -1
+func closestList(target string, candidates []string) (string, int) {
+	closest := ""
+	best := -1
+	for _, candidate := range candidates {
+		distance := levenshtein(strings.ToLower(target), strings.ToLower(candidate))
+		if best == -1 || distance < best {
+			closest = candidate
+			best = distance
+		}
+	}
+	return closest, best
+}
+
+// levenshtein returns the number of single-character insertions, deletions, and substitutions needed to turn a into b
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	previous := make([]int, len(rb)+1)
+	current := make([]int, len(rb)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		current[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(rb)]
+}
+
+// listSuggestion returns a "Did you mean" hint naming the list closest to listName, or "" if none is close enough
+// A suggestion is only made when at most half of listName would need to change
+func listSuggestion(lists []string, listName string) string {
+	closest, distance := closestList(listName, lists)
+	if distance < 0 || distance > len([]rune(listName))/2 {
+		return ""
+	}
+	return fmt.Sprintf("Did you mean \"%s\"?", closest)
+}
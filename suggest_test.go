@@ -0,0 +1,70 @@
+package main
+
+import "testing"
+
+func TestClosestList(t *testing.T) {
+	lists := []string{"Inbox", "Today", "Upcoming", "Anytime", "Someday", "Logbook"}
+
+	tests := []struct {
+		name           string
+		target         string
+		candidates     []string
+		expectClosest  string
+		expectDistance int
+	}{
+		{"transposed letters", "Todya", lists, "Today", 2},
+		{"missing letter", "Inbx", lists, "Inbox", 1},
+		{"different case", "someday", lists, "Someday", 0},
+		{"tie goes to the earlier candidate", "Tday", []string{"Today", "Tidy"}, "Today", 1},
+		{"no candidates", "Today", nil, "", -1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			closest, distance := closestList(tt.target, tt.candidates)
+			if closest != tt.expectClosest || distance != tt.expectDistance {
+				t.Errorf("expected (%q, %d), got (%q, %d)", tt.expectClosest, tt.expectDistance, closest, distance)
+			}
+		})
+	}
+}
+
+func TestLevenshtein(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		expected int
+	}{
+		{"", "", 0},
+		{"", "abc", 3},
+		{"kitten", "sitting", 3},
+		{"Today", "Today", 0},
+		{"café", "cafe", 1},
+	}
+
+	for _, tt := range tests {
+		if got := levenshtein(tt.a, tt.b); got != tt.expected {
+			t.Errorf("levenshtein(%q, %q) = %d, expected %d", tt.a, tt.b, got, tt.expected)
+		}
+	}
+}
+
+func TestListSuggestion(t *testing.T) {
+	lists := []string{"Inbox", "Today", "Upcoming"}
+
+	tests := []struct {
+		name     string
+		listName string
+		expected string
+	}{
+		{"near miss", "Todya", `Did you mean "Today"?`},
+		{"nothing close", "Groceries", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := listSuggestion(lists, tt.listName); got != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
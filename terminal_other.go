@@ -0,0 +1,10 @@
+//go:build !darwin && !linux
+
+package main
+
+import "os"
+
+// terminalColumns returns 0, as finding the terminal width isn't supported on this platform
+func terminalColumns(f *os.File) int {
+	return 0
+}
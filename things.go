@@ -1,263 +1,1769 @@
 package main
 
 import (
+	"bufio"
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"os"
+	"os/signal"
+	"regexp"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/urfave/cli/v3"
 )
 
-var version = "dev"
+// Global quiet switch - when set, commands that change to-dos don't report their success
+var quiet bool
+
+// defaultTimeout bounds how long a command waits on Things.app unless --timeout says otherwise
+const defaultTimeout = 30 * time.Second
+
+// defaultWatchInterval is how often show --watch fetches the list again unless --interval says otherwise
+const defaultWatchInterval = 5 * time.Second
 
 func main() {
+	// One osascript process runs every script, so a command that sends several only starts it once
+	executor = NewPersistentExecutor()
+	if err := newApp().Run(context.Background(), os.Args); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(exitFailure)
+	}
+}
+
+// newApp builds the root command with all subcommands
+// Output is written to the root command's Writer so tests can capture or discard it
+func newApp() *cli.Command {
+	// -v is taken by --verbose, so --version only gets its long form
+	cli.VersionFlag = &cli.BoolFlag{
+		Name:        "version",
+		Usage:       "print the version",
+		HideDefault: true,
+		Local:       true,
+	}
+
+	// A broken config file is reported once a command runs, so --help and --version still work
+	config, configErr := loadConfig()
+
 	var listName string
 	var todoName string
+	var nameContains string
+	var nameRegex string
+	var todoID string
+	var todoNames []string
+	var batch bool
+	var readStdin bool
+	var checklist string
+	var via string
+	var jsonResult bool
+	var jsonlResults bool
+	var inputJSON string
+	var someday bool
+	var importFile string
+	var strict bool
 	var fromList string
 	var toList string
+	var toProject string
+	var projectName string
+	var areaName string
+	var heading string
 	var tags string
+	var tagList []string
+	var tagSelector string
+	var addTags bool
+	var replaceTags bool
 	var newName string
+	var dueValue string
+	var whenValue string
+	var reminderValue string
+	var notesValue string
 	var dateFilter string
 	var areaFilter string
 	var projectFilter string
-	var jsonl bool
+	var output outputOptions
+	var ignoreCase bool
+	var all bool
+	var index int
+	var yes bool
+	var logNow bool
+	var noFlush bool
+	var query string
+	var status string
+	var filterTags []string
+	var limit int
+	var watch bool
+	var countOnly bool
+	var interval time.Duration
+	var concurrency int
+	var weekStartName string
+	var timezoneName string
+	var fromTime string
+	var toTime string
+	var overdue bool
+	var includeCanceled bool
+	var dueBefore string
+	var dueAfter string
+	var modifiedAfter string
+	var minAgeValue string
+	var maxAgeValue string
+	var groupBy string
+	var statsJSONL bool
+	var versionJSON bool
+	var timeout time.Duration
+	var verbose bool
+	var profile bool
+	var retries int
+	var unwrappedExecutor CommandExecutor
+	var outputPath string
+	var outputFile *os.File
+	var cancel context.CancelFunc
 
-	cmd := &cli.Command{
+	return &cli.Command{
 		Name:                  "things",
 		Version:               version,
 		Usage:                 "Interact with Things.app from the command line.",
+		Description:           "Exits with status 2 when a to-do, list, project, or area isn't found, and 1 for any other error.",
 		EnableShellCompletion: true,
+		Flags: []cli.Flag{
+			&cli.DurationFlag{
+				Name:        "timeout",
+				Usage:       "give up on Things.app after `DURATION` (0 for no timeout)",
+				Value:       defaultTimeout,
+				Destination: &timeout,
+			},
+			&cli.BoolFlag{
+				Name:        "verbose",
+				Aliases:     []string{"v"},
+				Usage:       "print each command sent to Things.app, including the full script, to stderr",
+				Destination: &verbose,
+			},
+			&cli.BoolFlag{
+				Name:        "profile",
+				Usage:       "print how long building the script, running it, and parsing its output took to stderr",
+				Destination: &profile,
+			},
+			&cli.StringFlag{
+				Name:        "output",
+				Aliases:     []string{"o"},
+				Usage:       "write output to `FILE` instead of stdout, replacing its contents",
+				Destination: &outputPath,
+			},
+			&cli.IntFlag{
+				Name:        "retry",
+				Usage:       "run a command sent to Things.app up to `N` more times if it fails to run, waiting longer each time",
+				Destination: &retries,
+			},
+			&cli.BoolFlag{
+				Name:        "quiet",
+				Usage:       "don't print success messages, only errors",
+				Destination: &quiet,
+			},
+			&cli.BoolFlag{
+				Name:        "dry-run",
+				Usage:       "print what add, delete, move, rename, and complete would do without changing anything",
+				Destination: &dryRun,
+			},
+		},
+		// Redirect output, and log or retry every osascript call made by a subcommand when asked,
+		// and bound those calls so a hung Things.app can't block forever
+		Before: func(ctx context.Context, cmd *cli.Command) (context.Context, error) {
+			if configErr != nil {
+				return ctx, cli.Exit(configErr.Error(), 1)
+			}
+			authToken = orDefault(os.Getenv("THINGS_AUTH_TOKEN"), config.AuthToken)
+			if retries < 0 {
+				return ctx, cli.Exit("ERROR: --retry can't be negative", 1)
+			}
+			if profile {
+				profiler = newPhaseTimer()
+			}
+			if outputPath != "" {
+				f, err := os.Create(outputPath)
+				if err != nil {
+					return ctx, cli.Exit(fmt.Sprintf("ERROR: can't write output: %v", err), 1)
+				}
+				outputFile = f
+				cmd.Root().Writer = f
+			}
+			if verbose || retries > 0 {
+				unwrappedExecutor = executor
+			}
+			if verbose {
+				executor = &loggingExecutor{next: executor, w: cmd.Root().ErrWriter}
+			}
+			// Retrying outside the logging shows every attempt
+			if retries > 0 {
+				executor = &retryingExecutor{next: executor, retries: retries}
+			}
+			if timeout <= 0 {
+				ctx, cancel = context.WithCancel(ctx)
+				return ctx, nil
+			}
+			ctx, cancel = context.WithTimeout(ctx, timeout)
+			return ctx, nil
+		},
+		After: func(ctx context.Context, cmd *cli.Command) error {
+			if cancel != nil {
+				cancel()
+			}
+			if profiler != nil {
+				profiler.lap(phaseParse)
+				fmt.Fprintln(cmd.Root().ErrWriter, profiler)
+				profiler = nil
+			}
+			if unwrappedExecutor != nil {
+				executor = unwrappedExecutor
+			}
+			// Stop the persistent osascript process, if there is one; the next command starts another
+			if closer, ok := executor.(io.Closer); ok {
+				_ = closer.Close()
+			}
+			if outputFile != nil {
+				if err := outputFile.Close(); err != nil {
+					return cli.Exit(fmt.Sprintf("ERROR: can't write output: %v", err), 1)
+				}
+			}
+			return nil
+		},
 		Commands: []*cli.Command{
 			{
 				Name:    "show",
 				Usage:   "Show to-dos from a specified list",
 				Aliases: []string{"s"},
-				Flags: []cli.Flag{
+				Flags: append([]cli.Flag{
 					&cli.StringFlag{
 						Name:        "list",
 						Aliases:     []string{"l"},
 						Usage:       "show to-dos from the specified `list`",
-						Required:    true,
+						Value:       config.List,
+						Required:    config.List == "",
 						Destination: &listName,
 					},
+					&cli.StringSliceFlag{
+						Name:        "tag",
+						Usage:       "only show to-dos carrying `TAG` (repeat to match any of several tags)",
+						Destination: &filterTags,
+					},
+					&cli.StringFlag{
+						Name:        "area",
+						Aliases:     []string{"a"},
+						Usage:       "only show to-dos in `AREA`",
+						Destination: &areaFilter,
+					},
+					&cli.StringFlag{
+						Name:        "project",
+						Aliases:     []string{"p"},
+						Usage:       "only show to-dos in `PROJECT`",
+						Destination: &projectFilter,
+					},
+					&cli.StringFlag{
+						Name:        "status",
+						Usage:       "only show to-dos with `STATUS` (open, completed, canceled, all)",
+						Value:       "all",
+						Destination: &status,
+					},
 					&cli.BoolFlag{
-						Name:        "jsonl",
-						Usage:       "output todos in JSONL format",
-						Destination: &jsonl,
+						Name:        "overdue",
+						Usage:       "only show open to-dos due before today",
+						Destination: &overdue,
+					},
+					&cli.StringFlag{
+						Name:        "due-before",
+						Usage:       "only show to-dos due before `DATE` (YYYY-MM-DD)",
+						Destination: &dueBefore,
+					},
+					&cli.StringFlag{
+						Name:        "due-after",
+						Usage:       "only show to-dos due after `DATE` (YYYY-MM-DD)",
+						Destination: &dueAfter,
+					},
+					&cli.StringFlag{
+						Name:        "modified-after",
+						Usage:       "only show to-dos modified after `TIME`, a date (YYYY-MM-DD, from the start of that day) or an RFC3339 timestamp; --long then shows when each was modified",
+						Destination: &modifiedAfter,
+					},
+					&cli.StringFlag{
+						Name:        "min-age",
+						Usage:       "only show to-dos created at least `AGE` ago, in days (30d) or as a duration (12h)",
+						Destination: &minAgeValue,
+					},
+					&cli.StringFlag{
+						Name:        "max-age",
+						Usage:       "only show to-dos created at most `AGE` ago, in days (7d) or as a duration (12h)",
+						Destination: &maxAgeValue,
+					},
+					&cli.StringFlag{
+						Name:        "timezone",
+						Usage:       "with --overdue or a --modified-after date, work out days in the IANA `ZONE` instead of the local timezone",
+						Value:       config.Timezone,
+						Destination: &timezoneName,
+					},
+					&cli.IntFlag{
+						Name:        "limit",
+						Usage:       "show at most `N` to-dos (0 for no limit)",
+						Destination: &limit,
 					},
+					&cli.BoolFlag{
+						Name:        "count-only",
+						Usage:       "print how many to-dos there are of each status instead of the to-dos",
+						Destination: &countOnly,
+					},
+					&cli.BoolFlag{
+						Name:        "watch",
+						Usage:       "keep showing the list, fetching it again every --interval until interrupted",
+						Destination: &watch,
+					},
+					&cli.DurationFlag{
+						Name:        "interval",
+						Usage:       "with --watch, wait `DURATION` between updates",
+						Value:       defaultWatchInterval,
+						Destination: &interval,
+					},
+				}, todoOutputFlags(&output, config)...),
+				Action: func(ctx context.Context, cmd *cli.Command) error {
+					if cmd.IsSet("interval") && !watch {
+						return cli.Exit("ERROR: --interval requires --watch", 1)
+					}
+					if interval <= 0 {
+						return cli.Exit("ERROR: --interval must be greater than 0", 1)
+					}
+					if countOnly && output.formatSelected() {
+						return cli.Exit("ERROR: --count-only can't be combined with an output format such as --jsonl", 1)
+					}
+					if status != "all" && !slices.Contains(todoStatuses, normalizeStatus(status)) {
+						return cli.Exit("ERROR: --status must be one of: open, completed, canceled, all", 1)
+					}
+					loc, err := parseTimezone(timezoneName)
+					if err != nil {
+						return cli.Exit(err.Error(), 1)
+					}
+					var before, after *time.Time
+					if dueBefore != "" {
+						day, err := parseDay(dueBefore, time.Local)
+						if err != nil {
+							return cli.Exit("ERROR: --due-before must be a date in YYYY-MM-DD format", 1)
+						}
+						before = &day
+					}
+					if dueAfter != "" {
+						day, err := parseDay(dueAfter, time.Local)
+						if err != nil {
+							return cli.Exit("ERROR: --due-after must be a date in YYYY-MM-DD format", 1)
+						}
+						after = &day
+					}
+					var modifiedCutoff time.Time
+					if modifiedAfter != "" {
+						modifiedCutoff, err = parseModifiedAfter(modifiedAfter, loc)
+						if err != nil {
+							return cli.Exit(err.Error(), 1)
+						}
+						output.showModified = true
+					}
+
+					var minAge, maxAge time.Duration
+					if minAgeValue != "" {
+						minAge, err = parseDayDuration(minAgeValue)
+						if err != nil {
+							return cli.Exit("ERROR: --min-age must be a number of days such as 30d or a duration such as 12h", 1)
+						}
+					}
+					if maxAgeValue != "" {
+						maxAge, err = parseDayDuration(maxAgeValue)
+						if err != nil {
+							return cli.Exit("ERROR: --max-age must be a number of days such as 7d or a duration such as 12h", 1)
+						}
+					}
+					if minAge > 0 && maxAge > 0 && minAge > maxAge {
+						return cli.Exit("ERROR: --min-age can't be greater than --max-age", 1)
+					}
+
+					if output.title {
+						output.header = listName
+					}
+
+					show := func(ctx context.Context) error {
+						todos, err := getTodosFromList(ctx, listName)
+						if err != nil {
+							if errors.Is(err, ErrListNotFound) {
+								// The suggestion is best effort, so failing to fetch the lists just leaves it out
+								if lists, listsErr := getLists(ctx); listsErr == nil {
+									if suggestion := listSuggestion(lists, listName); suggestion != "" {
+										return cli.Exit(err.Error()+"\n"+suggestion, exitNotFound)
+									}
+								}
+								return cli.Exit(err.Error()+"\nUse `things list` to see available lists.", exitNotFound)
+							}
+							if strings.HasPrefix(err.Error(), "ERROR:") {
+								return cli.Exit(err.Error(), 1)
+							}
+							return err
+						}
+						todos = filterTodosByTags(todos, filterTags)
+						todos = filterTodosByAreaProject(todos, areaFilter, projectFilter)
+						todos = filterTodosByStatus(todos, status)
+						if overdue {
+							todos = filterOverdue(todos, now().In(loc))
+						}
+						todos = filterByDueRange(todos, before, after)
+						if modifiedAfter != "" {
+							todos = filterByModifiedAfter(todos, modifiedCutoff)
+						}
+						todos = filterByAge(todos, now(), minAge, maxAge)
+						todos = limitTodos(todos, limit)
+						if countOnly {
+							fmt.Fprintln(cmd.Root().Writer, formatTodoCounts(listName, todos))
+							return nil
+						}
+						return writeTodos(cmd.Root().Writer, todos, output)
+					}
+					if !watch {
+						return show(ctx)
+					}
+
+					// --timeout bounds each update rather than the whole watch, which only ends on an interrupt
+					watchCtx, stop := signal.NotifyContext(context.WithoutCancel(ctx), os.Interrupt)
+					defer stop()
+					ticker := time.NewTicker(interval)
+					defer ticker.Stop()
+					return watchTodos(watchCtx, cmd.Root().Writer, ticker.C, func(ctx context.Context) error {
+						if timeout > 0 {
+							var cancelUpdate context.CancelFunc
+							ctx, cancelUpdate = context.WithTimeout(ctx, timeout)
+							defer cancelUpdate()
+						}
+						return show(ctx)
+					})
 				},
+			},
+			{
+				Name:    "today",
+				Usage:   "Show to-dos from the Today list",
+				Aliases: []string{"t"},
+				Flags:   todoOutputFlags(&output, config),
 				Action: func(ctx context.Context, cmd *cli.Command) error {
-					todos, err := getTodosFromList(listName)
+					todos, err := getTodosFromList(ctx, "Today")
 					if err != nil {
 						if strings.HasPrefix(err.Error(), "ERROR:") {
-							return cli.Exit(err.Error()+"\nUse `things list` to see available lists.", 1)
+							return cli.Exit(err.Error(), 1)
 						}
 						return err
 					}
 
-					if jsonl {
-						for _, todo := range todos {
-							jsonLine, err := formatTodoAsJSONL(todo)
-							if err != nil {
-								return err
-							}
-							fmt.Println(jsonLine)
+					output.header = "Today"
+					return writeTodos(cmd.Root().Writer, todos, output)
+				},
+			},
+			{
+				Name:    "upcoming",
+				Usage:   "Show scheduled to-dos grouped by date",
+				Aliases: []string{"u"},
+				Flags:   todoOutputFlags(&output, config),
+				Action: func(ctx context.Context, cmd *cli.Command) error {
+					todos, err := getTodosFromList(ctx, "Upcoming")
+					if err != nil {
+						if strings.HasPrefix(err.Error(), "ERROR:") {
+							return cli.Exit(err.Error(), 1)
 						}
-						return nil
+						return err
 					}
 
-					output := formatTodosForDisplay(todos)
-					fmt.Println(output)
+					// Other formats list to-dos as usual
+					if output.formatSelected() {
+						return writeTodos(cmd.Root().Writer, todos, output)
+					}
+					color, err := resolveColor(output.color, cmd.Root().Writer)
+					if err != nil {
+						return err
+					}
+					notesWidth, err := output.notesWidth()
+					if err != nil {
+						return err
+					}
+					width, err := resolveWidth(output.width, cmd.Root().Writer)
+					if err != nil {
+						return err
+					}
+					fmt.Fprintln(cmd.Root().Writer, formatGroupedByDate(todos, output.long || output.notes, color, output.ascii, notesWidth, width))
 					return nil
 				},
 			},
 			{
-				Name:    "add",
-				Usage:   "Add a new todo to a specified list",
-				Aliases: []string{"a"},
-				Flags: []cli.Flag{
+				Name:    "search",
+				Usage:   "Search to-dos by name across all lists",
+				Aliases: []string{"f"},
+				Flags: append([]cli.Flag{
 					&cli.StringFlag{
-						Name:        "list",
-						Aliases:     []string{"l"},
-						Usage:       "the `list` to add the to-do to",
-						Value:       "inbox",
-						Destination: &listName,
+						Name:        "query",
+						Aliases:     []string{"q"},
+						Usage:       "find to-dos whose name contains `TEXT` (case-insensitive)",
+						Destination: &query,
 					},
 					&cli.StringFlag{
-						Name:        "name",
-						Aliases:     []string{"n"},
-						Usage:       "the `to-do name` to add",
-						Required:    true,
-						Destination: &todoName,
+						Name:        "name-regex",
+						Usage:       "find to-dos whose name matches the regular expression `PATTERN`",
+						Destination: &nameRegex,
 					},
 					&cli.StringFlag{
-						Name:        "tags",
-						Aliases:     []string{"t"},
-						Usage:       "comma-separated `tags` to add to the to-do (e.g., \"Home, Work\")",
-						Destination: &tags,
+						Name:        "status",
+						Usage:       "only include to-dos with `STATUS` (open, completed, canceled)",
+						Destination: &status,
 					},
-				},
+					&cli.IntFlag{
+						Name:        "concurrency",
+						Usage:       "scan up to `N` lists at once",
+						Value:       defaultSearchConcurrency,
+						Destination: &concurrency,
+					},
+				}, todoOutputFlags(&output, config)...),
 				Action: func(ctx context.Context, cmd *cli.Command) error {
-					result, err := addTodoToList(listName, todoName, tags)
+					status = normalizeStatus(status)
+					if status != "" && !slices.Contains(todoStatuses, status) {
+						return cli.Exit("ERROR: --status must be one of: open, completed, canceled", 1)
+					}
+					if concurrency < 1 {
+						return cli.Exit("ERROR: --concurrency must be at least 1", 1)
+					}
+					if query == "" && nameRegex == "" {
+						return cli.Exit("ERROR: --query or --name-regex is required", 1)
+					}
+					re, err := compileNameRegex(nameRegex, "", false)
 					if err != nil {
 						return err
 					}
-					if !result.Success {
-						return cli.Exit(result.Message, 1)
+
+					todos, err := searchTodos(ctx, query, status, concurrency)
+					if err != nil {
+						if strings.HasPrefix(err.Error(), "ERROR:") {
+							return cli.Exit(err.Error(), 1)
+						}
+						return err
 					}
-					fmt.Println(formatOperationResult(result))
-					return nil
+					// Patterns are matched here so they never have to be carried into the search scripts
+					todos = filterByNameRegex(todos, re)
+
+					if output.title {
+						output.header = "Search results"
+					}
+					return writeTodos(cmd.Root().Writer, todos, output)
 				},
 			},
 			{
-				Name:    "delete",
-				Usage:   "Delete a todo by name from a specified list",
-				Aliases: []string{"d"},
+				Name:    "add",
+				Usage:   "Add a new todo to a specified list",
+				Aliases: []string{"a"},
+				// --name is repeated rather than comma-separated so to-do names can contain commas
+				DisableSliceFlagSeparator: true,
 				Flags: []cli.Flag{
 					&cli.StringFlag{
 						Name:        "list",
 						Aliases:     []string{"l"},
-						Usage:       "the `list` to search for the to-do in",
-						Required:    true,
+						Usage:       "the `list` to add the to-do to; without it, $THINGS_DEFAULT_LIST is used if set",
+						Value:       orDefault(config.List, "inbox"),
 						Destination: &listName,
 					},
 					&cli.StringFlag{
+						Name:        "project",
+						Aliases:     []string{"p"},
+						Usage:       "the `project` to add the to-do to, instead of a list",
+						Destination: &projectName,
+					},
+					&cli.StringFlag{
+						Name:        "heading",
+						Usage:       "the `heading` in --project to add the to-do under",
+						Destination: &heading,
+					},
+					&cli.StringSliceFlag{
 						Name:        "name",
 						Aliases:     []string{"n"},
-						Usage:       "the `name` of the to-do to delete",
-						Required:    true,
-						Destination: &todoName,
+						Usage:       "the `to-do name` to add (repeat with --batch to add several)",
+						Destination: &todoNames,
+					},
+					&cli.StringFlag{
+						Name:        "tags",
+						Aliases:     []string{"t"},
+						Usage:       "comma-separated `tags` to add to the to-do (e.g., \"Home, Work\")",
+						Destination: &tags,
+					},
+					&cli.StringSliceFlag{
+						Name:        "tag",
+						Usage:       "a `tag` to add to the to-do, kept whole even if it contains a comma (repeat for several)",
+						Destination: &tagList,
+					},
+					&cli.StringFlag{
+						Name:        "checklist",
+						Aliases:     []string{"c"},
+						Usage:       "checklist `items` for the to-do, separated by newlines or \"|\" (e.g., \"Eggs|Milk\")",
+						Destination: &checklist,
+					},
+					&cli.BoolFlag{
+						Name:        "batch",
+						Usage:       "add every --name, or each line of stdin if none are given, in one call",
+						Destination: &batch,
+					},
+					&cli.BoolFlag{
+						Name:        "stdin",
+						Usage:       "ignore --name and add one to-do per line of stdin, printing a summary",
+						Destination: &readStdin,
+					},
+					&cli.StringFlag{
+						Name:        "via",
+						Usage:       "add through `BACKEND`: script (JXA) or url (things:/// URL scheme)",
+						Value:       "script",
+						Destination: &via,
+					},
+					&cli.BoolFlag{
+						Name:        "json",
+						Usage:       "print the result as a JSON object, even when it fails",
+						Destination: &jsonResult,
+					},
+					&cli.BoolFlag{
+						Name:        "jsonl",
+						Usage:       "with --batch or --stdin, print each to-do's result as a JSON line, even when it fails",
+						Destination: &jsonlResults,
+					},
+					&cli.BoolFlag{
+						Name:        "someday",
+						Usage:       "add the to-do to Someday, instead of --list",
+						Destination: &someday,
+					},
+					&cli.StringFlag{
+						Name:        "input-json",
+						Usage:       "add the to-do described by `SPEC`, a JSON object in the --json format (name, notes, tagNames, dueDate, activationDate, checklistItems), or \"-\" to read it from stdin",
+						Destination: &inputJSON,
 					},
 				},
 				Action: func(ctx context.Context, cmd *cli.Command) error {
-					result, err := deleteTodoFromList(listName, todoName)
-					if err != nil {
-						return err
+					if via != "script" && via != "url" {
+						return cli.Exit("ERROR: --via must be one of: script, url", 1)
 					}
-					if !result.Success {
-						return cli.Exit(result.Message, 1)
+					// THINGS_DEFAULT_LIST only stands in for --list, so it beats the config file's list but not the flag
+					if envList := os.Getenv("THINGS_DEFAULT_LIST"); envList != "" && !cmd.IsSet("list") {
+						listName = envList
 					}
-					fmt.Println(formatOperationResult(result))
-					return nil
+					// Someday is a list, so --someday picks it the way --list would; add has no --when to schedule with
+					if someday {
+						if cmd.IsSet("list") || projectName != "" {
+							return cli.Exit("ERROR: --someday can't be combined with --list or --project", 1)
+						}
+						listName = "Someday"
+					}
+					if cmd.IsSet("input-json") {
+						if len(todoNames) > 0 || tags != "" || len(tagList) > 0 || checklist != "" {
+							return cli.Exit("ERROR: --input-json can't be combined with --name, --tags, --tag, or --checklist", 1)
+						}
+						if batch || readStdin || via == "url" || projectName != "" {
+							return cli.Exit("ERROR: --input-json can't be combined with --batch, --stdin, --via url, or --project", 1)
+						}
+						spec, err := readTodoSpec(inputJSON, cmd.Root().Reader)
+						if err != nil {
+							return cli.Exit(err.Error(), 1)
+						}
+						if someday && spec.ActivationDate != nil {
+							return cli.Exit("ERROR: --someday can't be combined with an activationDate in --input-json", 1)
+						}
+						results, err := addTodosToList(ctx, listName, []NewTodo{spec})
+						if err != nil {
+							if strings.HasPrefix(err.Error(), "ERROR:") {
+								return cli.Exit(err.Error(), 1)
+							}
+							return err
+						}
+						return finishOperation(cmd, results[0], nil, jsonResult)
+					}
+					todoTags := append(parseTags(tags), tagList...)
+					if via == "url" && (batch || readStdin) {
+						return cli.Exit("ERROR: --via url can't be combined with --batch or --stdin", 1)
+					}
+					if jsonResult && (batch || readStdin) {
+						return cli.Exit("ERROR: --json can't be combined with --batch or --stdin", 1)
+					}
+					if jsonlResults && !batch && !readStdin {
+						return cli.Exit("ERROR: --jsonl requires --batch or --stdin; use --json for a single to-do", 1)
+					}
+					if heading != "" && projectName == "" {
+						return cli.Exit("ERROR: --heading requires --project", 1)
+					}
+					if projectName != "" {
+						if cmd.IsSet("list") {
+							return cli.Exit("ERROR: --list and --project cannot be used together", 1)
+						}
+						if batch || readStdin || via == "url" {
+							return cli.Exit("ERROR: --project can't be combined with --batch, --stdin, or --via url", 1)
+						}
+					}
+
+					if readStdin {
+						names, err := readTodoNames(cmd.Root().Reader)
+						if err != nil {
+							return err
+						}
+						if len(names) == 0 {
+							return cli.Exit("ERROR: --stdin found no to-do names to add", 1)
+						}
+						return runBatchAdd(ctx, cmd, listName, names, todoTags, parseChecklist(checklist), true, jsonlResults)
+					}
+					if batch {
+						if len(todoNames) == 0 {
+							names, err := readTodoNames(cmd.Root().Reader)
+							if err != nil {
+								return err
+							}
+							todoNames = names
+						}
+						if len(todoNames) == 0 {
+							return cli.Exit("ERROR: --batch needs at least one --name or a name on each line of stdin", 1)
+						}
+						return runBatchAdd(ctx, cmd, listName, todoNames, todoTags, parseChecklist(checklist), false, jsonlResults)
+					}
+					if len(todoNames) == 0 {
+						return cli.Exit("ERROR: --name is required", 1)
+					}
+					if len(todoNames) > 1 {
+						return cli.Exit("ERROR: use --batch to add more than one --name", 1)
+					}
+
+					var result OperationResult
+					var err error
+					switch {
+					case projectName != "":
+						result, err = addTodoToProject(ctx, projectName, heading, todoNames[0], todoTags, parseChecklist(checklist))
+					case via == "url":
+						result, err = addTodoViaURL(ctx, listName, todoNames[0], todoTags, parseChecklist(checklist))
+					default:
+						result, err = addTodoToList(ctx, listName, todoNames[0], todoTags, parseChecklist(checklist))
+					}
+					return finishOperation(cmd, result, err, jsonResult)
 				},
 			},
 			{
-				Name:    "move",
-				Usage:   "Move a todo from one list to another",
-				Aliases: []string{"m"},
+				Name:  "import",
+				Usage: "Add to-dos from a JSONL file, such as one written by --jsonl",
 				Flags: []cli.Flag{
 					&cli.StringFlag{
-						Name:        "from",
-						Usage:       "the `list` to move the to-do from",
-						Required:    true,
-						Destination: &fromList,
+						Name:        "file",
+						Aliases:     []string{"f"},
+						Usage:       "read to-dos from `PATH` instead of stdin",
+						Destination: &importFile,
 					},
 					&cli.StringFlag{
-						Name:        "to",
-						Usage:       "the `list` to move the to-do to",
-						Required:    true,
-						Destination: &toList,
+						Name:        "list",
+						Aliases:     []string{"l"},
+						Usage:       "the `list` to add the to-dos to",
+						Value:       orDefault(config.List, "inbox"),
+						Destination: &listName,
 					},
-					&cli.StringFlag{
-						Name:        "name",
-						Aliases:     []string{"n"},
-						Usage:       "the `name` of the to-do to move",
-						Required:    true,
-						Destination: &todoName,
+					&cli.BoolFlag{
+						Name:        "strict",
+						Usage:       "add nothing if any line can't be parsed",
+						Destination: &strict,
 					},
 				},
 				Action: func(ctx context.Context, cmd *cli.Command) error {
-					result, err := moveTodoBetweenLists(fromList, toList, todoName)
+					r := cmd.Root().Reader
+					if importFile != "" {
+						f, err := os.Open(importFile)
+						if err != nil {
+							return cli.Exit(fmt.Sprintf("ERROR: %v", err), 1)
+						}
+						defer f.Close()
+						r = f
+					}
+
+					imported, skipped, err := importTodos(ctx, r, listName, strict, cmd.Root().ErrWriter)
 					if err != nil {
+						if strings.HasPrefix(err.Error(), "ERROR:") {
+							return cli.Exit(err.Error(), 1)
+						}
 						return err
 					}
-					if !result.Success {
-						return cli.Exit(result.Message, 1)
+					if quiet {
+						return nil
 					}
-					fmt.Println(formatOperationResult(result))
+					fmt.Fprintf(cmd.Root().Writer, "Imported %d to-dos to list \"%s\" (%d skipped)\n", imported, listName, skipped)
 					return nil
 				},
 			},
 			{
-				Name:    "rename",
-				Usage:   "Rename a todo in a specified list",
-				Aliases: []string{"r"},
+				Name:    "delete",
+				Usage:   "Delete a todo by name from a specified list",
+				Aliases: []string{"d"},
 				Flags: []cli.Flag{
 					&cli.StringFlag{
 						Name:        "list",
 						Aliases:     []string{"l"},
-						Usage:       "the `list` containing the to-do",
-						Required:    true,
+						Usage:       "the `list` to search for the to-do in",
 						Destination: &listName,
 					},
 					&cli.StringFlag{
 						Name:        "name",
 						Aliases:     []string{"n"},
-						Usage:       "the current `name` of the to-do",
-						Required:    true,
+						Usage:       "the `name` of the to-do to delete",
 						Destination: &todoName,
 					},
+					&cli.StringFlag{
+						Name:        "name-contains",
+						Usage:       "delete a to-do whose name contains `TEXT`, instead of matching --name exactly",
+						Destination: &nameContains,
+					},
+					&cli.StringFlag{
+						Name:        "name-regex",
+						Usage:       "delete to-dos whose name matches the regular expression `PATTERN`, instead of matching --name exactly",
+						Destination: &nameRegex,
+					},
+					&cli.StringFlag{
+						Name:        "id",
+						Usage:       "delete the to-do with this `ID` instead of matching by list and name",
+						Destination: &todoID,
+					},
+					&cli.BoolFlag{
+						Name:        "ignore-case",
+						Aliases:     []string{"i"},
+						Usage:       "match the to-do name case-insensitively",
+						Destination: &ignoreCase,
+					},
+					&cli.BoolFlag{
+						Name:        "all",
+						Usage:       "apply to every to-do matching the name, not just the first",
+						Destination: &all,
+					},
+					&cli.IntFlag{
+						Name:        "index",
+						Usage:       "act on the zero-based `N`th to-do matching the name",
+						Destination: &index,
+					},
+					&cli.BoolFlag{
+						Name:        "yes",
+						Aliases:     []string{"y"},
+						Usage:       "delete without asking for confirmation",
+						Destination: &yes,
+					},
+					&cli.BoolFlag{
+						Name:        "json",
+						Usage:       "print the result as a JSON object, even when it fails",
+						Destination: &jsonResult,
+					},
+				},
+				Action: func(ctx context.Context, cmd *cli.Command) error {
+					var opts MatchOptions
+					if todoID == "" {
+						var err error
+						opts, err = buildMatchOptions(cmd, ignoreCase, all, index)
+						if err != nil {
+							return err
+						}
+						todoName, err = applyNameContains(todoName, nameContains, &opts)
+						if err != nil {
+							return err
+						}
+						re, err := compileNameRegex(nameRegex, todoName, ignoreCase)
+						if err != nil {
+							return err
+						}
+						if re != nil {
+							targets, _, err := regexTargets(ctx, listName, re, opts)
+							if err != nil {
+								return err
+							}
+							if !yes && !dryRun {
+								prompt := fmt.Sprintf("Delete \"%s\"?", targets[0].Name)
+								if len(targets) > 1 {
+									prompt = fmt.Sprintf("Delete %d to-dos matching /%s/?", len(targets), re)
+								}
+								confirmed, err := confirm(cmd.Root().Reader, cmd.Root().Writer, prompt)
+								if err != nil {
+									return err
+								}
+								if !confirmed {
+									return cli.Exit("Aborted; nothing was deleted", exitFailure)
+								}
+							}
+							result, err := applyByID(ctx, targets, deleteTodoByID)
+							return finishOperation(cmd, result, err, jsonResult)
+						}
+						if err := requireListAndName(listName, todoName); err != nil {
+							return err
+						}
+					}
+					if !yes && !dryRun {
+						if err := requireSingleContainsMatch(ctx, listName, todoName, opts); err != nil {
+							return err
+						}
+						confirmed, err := confirmDelete(ctx, cmd.Root().Reader, cmd.Root().Writer, listName, todoName, todoID, opts)
+						if err != nil {
+							return err
+						}
+						if !confirmed {
+							return cli.Exit("Aborted; nothing was deleted", exitFailure)
+						}
+					}
+
+					var result OperationResult
+					var err error
+					if todoID != "" {
+						result, err = deleteTodoByID(ctx, todoID)
+					} else {
+						result, err = deleteTodoFromList(ctx, listName, todoName, opts)
+					}
+					return finishOperation(cmd, result, err, jsonResult)
+				},
+			},
+			{
+				Name:    "complete",
+				Usage:   "Mark a todo as completed by name in a specified list",
+				Aliases: []string{"c"},
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:        "list",
+						Aliases:     []string{"l"},
+						Usage:       "the `list` to search for the to-do in",
+						Destination: &listName,
+					},
+					&cli.StringFlag{
+						Name:        "name",
+						Aliases:     []string{"n"},
+						Usage:       "the `name` of the to-do to complete",
+						Destination: &todoName,
+					},
+					&cli.StringFlag{
+						Name:        "id",
+						Usage:       "complete the to-do with this `ID` instead of matching by list and name",
+						Destination: &todoID,
+					},
+					&cli.BoolFlag{
+						Name:        "ignore-case",
+						Aliases:     []string{"i"},
+						Usage:       "match the to-do name case-insensitively",
+						Destination: &ignoreCase,
+					},
+					&cli.BoolFlag{
+						Name:        "all",
+						Usage:       "apply to every to-do matching the name, not just the first; without --name, complete every open to-do in the list",
+						Destination: &all,
+					},
+					&cli.StringFlag{
+						Name:        "tag",
+						Usage:       "complete every open to-do in the list carrying `TAG`, instead of matching by name",
+						Destination: &tagSelector,
+					},
+					&cli.IntFlag{
+						Name:        "index",
+						Usage:       "act on the zero-based `N`th to-do matching the name",
+						Destination: &index,
+					},
+					&cli.BoolFlag{
+						Name:        "log",
+						Usage:       "move completed to-dos to the Logbook right away",
+						Destination: &logNow,
+					},
+					&cli.BoolFlag{
+						Name:        "yes",
+						Aliases:     []string{"y"},
+						Usage:       "complete a whole list with --all without asking for confirmation",
+						Destination: &yes,
+					},
+				},
+				Action: func(ctx context.Context, cmd *cli.Command) error {
+					var result OperationResult
+					var err error
+					if todoID != "" {
+						result, err = completeTodoByID(ctx, todoID)
+					} else if todoName == "" && (tagSelector != "" || all) {
+						if listName == "" {
+							return cli.Exit("ERROR: --list is required to complete to-dos in bulk", 1)
+						}
+						if tagSelector == "" && !yes && !dryRun {
+							confirmed, err := confirm(cmd.Root().Reader, cmd.Root().Writer, fmt.Sprintf("Complete every open to-do in list \"%s\"?", listName))
+							if err != nil {
+								return err
+							}
+							if !confirmed {
+								return cli.Exit("Aborted; nothing was completed", exitFailure)
+							}
+						}
+						result, err = completeTodosInList(ctx, listName, tagSelector)
+					} else {
+						if tagSelector != "" {
+							return cli.Exit("ERROR: --tag cannot be used with --name", 1)
+						}
+						if err := requireListAndName(listName, todoName); err != nil {
+							return err
+						}
+						opts, optsErr := buildMatchOptions(cmd, ignoreCase, all, index)
+						if optsErr != nil {
+							return optsErr
+						}
+						result, err = completeTodoInList(ctx, listName, todoName, opts)
+					}
+					if err != nil {
+						return err
+					}
+					if !result.Success {
+						return cli.Exit(result.Message, exitCode(result.Err))
+					}
+					printResult(cmd, result)
+
+					if !logNow {
+						return nil
+					}
+					if dryRun {
+						fmt.Fprintln(cmd.Root().Writer, "Dry run: would then move completed to-dos to the Logbook")
+						return nil
+					}
+					// The to-do is already completed, so say so rather than reporting the whole command as failed
+					if err := logCompletedNow(ctx); err != nil {
+						return cli.Exit(fmt.Sprintf("ERROR: To-do completed, but moving it to the Logbook failed: %s", strings.TrimPrefix(err.Error(), "ERROR: ")), 1)
+					}
+					if !quiet {
+						fmt.Fprintln(cmd.Root().Writer, "Completed to-dos moved to the Logbook!")
+					}
+					return nil
+				},
+			},
+			{
+				Name:    "move",
+				Usage:   "Move a todo from one list to another",
+				Aliases: []string{"m"},
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:        "from",
+						Usage:       "the `list` to move the to-do from",
+						Required:    true,
+						Destination: &fromList,
+					},
+					&cli.StringFlag{
+						Name:        "to",
+						Usage:       "the `list` to move the to-do to; Today, Anytime, and Someday schedule it instead, and Upcoming schedules it for tomorrow",
+						Destination: &toList,
+					},
+					&cli.StringFlag{
+						Name:        "to-project",
+						Usage:       "the `project` to move the to-do into, instead of a list",
+						Destination: &toProject,
+					},
+					&cli.StringFlag{
+						Name:        "name",
+						Aliases:     []string{"n"},
+						Usage:       "the `name` of the to-do to move",
+						Destination: &todoName,
+					},
+					&cli.StringFlag{
+						Name:        "name-regex",
+						Usage:       "move to-dos whose name matches the regular expression `PATTERN`, instead of matching --name exactly",
+						Destination: &nameRegex,
+					},
+					&cli.StringFlag{
+						Name:        "tag",
+						Usage:       "move every to-do carrying `TAG`, instead of matching by name",
+						Destination: &tagSelector,
+					},
+					&cli.BoolFlag{
+						Name:        "ignore-case",
+						Aliases:     []string{"i"},
+						Usage:       "match the to-do name case-insensitively",
+						Destination: &ignoreCase,
+					},
+					&cli.BoolFlag{
+						Name:        "all",
+						Usage:       "apply to every to-do matching the name, not just the first",
+						Destination: &all,
+					},
+					&cli.IntFlag{
+						Name:        "index",
+						Usage:       "act on the zero-based `N`th to-do matching the name",
+						Destination: &index,
+					},
+					&cli.BoolFlag{
+						Name:        "yes",
+						Aliases:     []string{"y"},
+						Usage:       "move without asking for confirmation when several to-dos match",
+						Destination: &yes,
+					},
+					&cli.BoolFlag{
+						Name:        "json",
+						Usage:       "print the result as a JSON object, even when it fails",
+						Destination: &jsonResult,
+					},
+				},
+				Action: func(ctx context.Context, cmd *cli.Command) error {
+					if toList != "" && toProject != "" {
+						return cli.Exit("ERROR: --to and --to-project cannot be used together", 1)
+					}
+					if toList == "" && toProject == "" {
+						return cli.Exit("ERROR: one of --to or --to-project is required", 1)
+					}
+					if tagSelector != "" {
+						if todoName != "" || nameRegex != "" {
+							return cli.Exit("ERROR: --tag cannot be used with --name or --name-regex", 1)
+						}
+						if toProject != "" {
+							return cli.Exit("ERROR: --tag can only move to-dos to a list given with --to", 1)
+						}
+						result, err := moveTodosByTag(ctx, fromList, toList, tagSelector)
+						return finishOperation(cmd, result, err, jsonResult)
+					}
+					opts, err := buildMatchOptions(cmd, ignoreCase, all, index)
+					if err != nil {
+						return err
+					}
+					re, err := compileNameRegex(nameRegex, todoName, ignoreCase)
+					if err != nil {
+						return err
+					}
+					dest := listDestination(toList)
+					if toProject != "" {
+						dest = projectDestination(toProject)
+					}
+					if re != nil {
+						targets, total, err := regexTargets(ctx, fromList, re, opts)
+						if err != nil {
+							return err
+						}
+						if !yes && !dryRun && total > 1 && !opts.All && opts.Index == 0 {
+							confirmed, err := confirm(cmd.Root().Reader, cmd.Root().Writer, fmt.Sprintf("Move \"%s\"? %d to-dos match; only the first will be moved", targets[0].Name, total))
+							if err != nil {
+								return err
+							}
+							if !confirmed {
+								return cli.Exit("Aborted; nothing was moved", exitFailure)
+							}
+						}
+						result, err := applyByID(ctx, targets, func(ctx context.Context, id string) (OperationResult, error) {
+							return moveTodoByID(ctx, id, dest)
+						})
+						return finishOperation(cmd, result, err, jsonResult)
+					}
+					if todoName == "" {
+						return cli.Exit("ERROR: one of --name, --name-regex, or --tag is required", 1)
+					}
+					if !yes && !dryRun {
+						confirmed, err := confirmAmbiguousMove(ctx, cmd.Root().Reader, cmd.Root().Writer, fromList, todoName, opts)
+						if err != nil {
+							return err
+						}
+						if !confirmed {
+							return cli.Exit("Aborted; nothing was moved", exitFailure)
+						}
+					}
+					result, err := moveTodo(ctx, fromList, todoName, opts, dest)
+					return finishOperation(cmd, result, err, jsonResult)
+				},
+			},
+			{
+				Name:    "rename",
+				Usage:   "Rename a todo in a specified list",
+				Aliases: []string{"r"},
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:        "list",
+						Aliases:     []string{"l"},
+						Usage:       "the `list` containing the to-do",
+						Destination: &listName,
+					},
+					&cli.StringFlag{
+						Name:        "name",
+						Aliases:     []string{"n"},
+						Usage:       "the current `name` of the to-do",
+						Destination: &todoName,
+					},
+					&cli.StringFlag{
+						Name:        "name-contains",
+						Usage:       "rename a to-do whose name contains `TEXT`, instead of matching --name exactly",
+						Destination: &nameContains,
+					},
+					&cli.StringFlag{
+						Name:        "name-regex",
+						Usage:       "rename to-dos whose name matches the regular expression `PATTERN`, instead of matching --name exactly",
+						Destination: &nameRegex,
+					},
+					&cli.StringFlag{
+						Name:        "id",
+						Usage:       "rename the to-do with this `ID` instead of matching by list and name",
+						Destination: &todoID,
+					},
 					&cli.StringFlag{
 						Name:        "new-name",
 						Usage:       "the `new name` for the to-do",
 						Required:    true,
 						Destination: &newName,
 					},
-				},
+					&cli.BoolFlag{
+						Name:        "ignore-case",
+						Aliases:     []string{"i"},
+						Usage:       "match the to-do name case-insensitively",
+						Destination: &ignoreCase,
+					},
+					&cli.BoolFlag{
+						Name:        "all",
+						Usage:       "apply to every to-do matching the name, not just the first",
+						Destination: &all,
+					},
+					&cli.IntFlag{
+						Name:        "index",
+						Usage:       "act on the zero-based `N`th to-do matching the name",
+						Destination: &index,
+					},
+					&cli.BoolFlag{
+						Name:        "yes",
+						Aliases:     []string{"y"},
+						Usage:       "rename even when --name-contains matches several to-dos",
+						Destination: &yes,
+					},
+					&cli.BoolFlag{
+						Name:        "json",
+						Usage:       "print the result as a JSON object, even when it fails",
+						Destination: &jsonResult,
+					},
+				},
+				Action: func(ctx context.Context, cmd *cli.Command) error {
+					var result OperationResult
+					var err error
+					if todoID != "" {
+						result, err = renameTodoByID(ctx, todoID, newName)
+					} else {
+						opts, optsErr := buildMatchOptions(cmd, ignoreCase, all, index)
+						if optsErr != nil {
+							return optsErr
+						}
+						name, nameErr := applyNameContains(todoName, nameContains, &opts)
+						if nameErr != nil {
+							return nameErr
+						}
+						re, reErr := compileNameRegex(nameRegex, name, ignoreCase)
+						if reErr != nil {
+							return reErr
+						}
+						if re != nil {
+							targets, _, err := regexTargets(ctx, listName, re, opts)
+							if err != nil {
+								return err
+							}
+							result, err := applyByID(ctx, targets, func(ctx context.Context, id string) (OperationResult, error) {
+								return renameTodoByID(ctx, id, newName)
+							})
+							return finishOperation(cmd, result, err, jsonResult)
+						}
+						if err := requireListAndName(listName, name); err != nil {
+							return err
+						}
+						if !yes && !dryRun {
+							if err := requireSingleContainsMatch(ctx, listName, name, opts); err != nil {
+								return err
+							}
+						}
+						result, err = renameTodoInList(ctx, listName, name, newName, opts)
+					}
+					return finishOperation(cmd, result, err, jsonResult)
+				},
+			},
+			{
+				Name:  "reopen",
+				Usage: "Mark a completed or canceled todo as open again",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:        "list",
+						Aliases:     []string{"l"},
+						Usage:       "the `list` to search for the to-do in",
+						Value:       "Logbook",
+						Destination: &listName,
+					},
+					&cli.StringFlag{
+						Name:        "name",
+						Aliases:     []string{"n"},
+						Usage:       "the `name` of the to-do to reopen",
+						Required:    true,
+						Destination: &todoName,
+					},
+				},
+				Action: func(ctx context.Context, cmd *cli.Command) error {
+					result, err := reopenTodo(ctx, listName, todoName)
+					return finishOperation(cmd, result, err, false)
+				},
+			},
+			{
+				Name:  "restore",
+				Usage: "Move a deleted todo from the Trash back to the Inbox",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:        "name",
+						Aliases:     []string{"n"},
+						Usage:       "the `name` of the trashed to-do; if several match, the most recently deleted is restored",
+						Destination: &todoName,
+					},
+					&cli.StringFlag{
+						Name:        "id",
+						Usage:       "restore the to-do with this `ID` instead of matching by name",
+						Destination: &todoID,
+					},
+				},
+				Action: func(ctx context.Context, cmd *cli.Command) error {
+					if (todoName == "") == (todoID == "") {
+						return cli.Exit("ERROR: give one of --name or --id", 1)
+					}
+					var result OperationResult
+					var err error
+					if todoID != "" {
+						result, err = restoreTodoByID(ctx, todoID)
+					} else {
+						result, err = restoreTodoFromTrash(ctx, todoName)
+					}
+					return finishOperation(cmd, result, err, false)
+				},
+			},
+			{
+				Name:  "retag",
+				Usage: "Add to or replace the tags of a todo in a specified list",
+				// --tag is repeated rather than comma-separated so tag names can contain commas
+				DisableSliceFlagSeparator: true,
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:        "list",
+						Aliases:     []string{"l"},
+						Usage:       "the `list` containing the to-do",
+						Required:    true,
+						Destination: &listName,
+					},
+					&cli.StringFlag{
+						Name:        "name",
+						Aliases:     []string{"n"},
+						Usage:       "the `name` of the to-do to tag",
+						Required:    true,
+						Destination: &todoName,
+					},
+					&cli.StringFlag{
+						Name:        "tags",
+						Aliases:     []string{"t"},
+						Usage:       "comma-separated `tags` to set (e.g., \"Home, Work\")",
+						Destination: &tags,
+					},
+					&cli.StringSliceFlag{
+						Name:        "tag",
+						Usage:       "a `tag` to set, kept whole even if it contains a comma (repeat for several)",
+						Destination: &tagList,
+					},
+					&cli.BoolFlag{
+						Name:        "add",
+						Usage:       "add the tags to the ones the to-do already has (the default)",
+						Destination: &addTags,
+					},
+					&cli.BoolFlag{
+						Name:        "replace",
+						Usage:       "replace the to-do's tags, clearing them if none are given",
+						Destination: &replaceTags,
+					},
+				},
+				Action: func(ctx context.Context, cmd *cli.Command) error {
+					if addTags && replaceTags {
+						return cli.Exit("ERROR: --add and --replace cannot be used together", 1)
+					}
+					todoTags := append(parseTags(tags), tagList...)
+					if len(todoTags) == 0 && !replaceTags {
+						return cli.Exit("ERROR: --tags or --tag is required unless --replace is given", 1)
+					}
+					result, err := setTodoTags(ctx, listName, todoName, todoTags, replaceTags)
+					if err != nil {
+						return err
+					}
+					if !result.Success {
+						return cli.Exit(result.Message, exitCode(result.Err))
+					}
+					printResult(cmd, result)
+					return nil
+				},
+			},
+			{
+				Name:  "untag",
+				Usage: "Remove tags from a todo in a specified list",
+				// --tag is repeated rather than comma-separated so tag names can contain commas
+				DisableSliceFlagSeparator: true,
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:        "list",
+						Aliases:     []string{"l"},
+						Usage:       "the `list` containing the to-do",
+						Required:    true,
+						Destination: &listName,
+					},
+					&cli.StringFlag{
+						Name:        "name",
+						Aliases:     []string{"n"},
+						Usage:       "the `name` of the to-do to untag",
+						Required:    true,
+						Destination: &todoName,
+					},
+					&cli.StringFlag{
+						Name:        "tags",
+						Aliases:     []string{"t"},
+						Usage:       "comma-separated `tags` to remove (e.g., \"Home, Work\")",
+						Destination: &tags,
+					},
+					&cli.StringSliceFlag{
+						Name:        "tag",
+						Usage:       "a `tag` to remove, kept whole even if it contains a comma (repeat for several)",
+						Destination: &tagList,
+					},
+				},
+				Action: func(ctx context.Context, cmd *cli.Command) error {
+					todoTags := append(parseTags(tags), tagList...)
+					if len(todoTags) == 0 {
+						return cli.Exit("ERROR: --tags or --tag is required", 1)
+					}
+					result, err := removeTodoTags(ctx, listName, todoName, todoTags)
+					if err != nil {
+						return err
+					}
+					if !result.Success {
+						return cli.Exit(result.Message, exitCode(result.Err))
+					}
+					printResult(cmd, result)
+					return nil
+				},
+			},
+			{
+				Name:  "edit",
+				Usage: "Change the name, due date, schedule, notes, or tags of a todo in one go",
+				// --tag is repeated rather than comma-separated so tag names can contain commas
+				DisableSliceFlagSeparator: true,
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:        "list",
+						Aliases:     []string{"l"},
+						Usage:       "the `list` containing the to-do",
+						Required:    true,
+						Destination: &listName,
+					},
+					&cli.StringFlag{
+						Name:        "name",
+						Aliases:     []string{"n"},
+						Usage:       "the `name` of the to-do to edit",
+						Required:    true,
+						Destination: &todoName,
+					},
+					&cli.StringFlag{
+						Name:        "new-name",
+						Usage:       "rename the to-do to `NAME`",
+						Destination: &newName,
+					},
+					&cli.StringFlag{
+						Name:        "due",
+						Usage:       "set the due date to `DATE` (YYYY-MM-DD), or clear it with \"\"",
+						Destination: &dueValue,
+					},
+					&cli.StringFlag{
+						Name:        "when",
+						Usage:       "schedule the to-do for `WHEN` (today, tomorrow, anytime, someday, or YYYY-MM-DD)",
+						Destination: &whenValue,
+					},
+					&cli.StringFlag{
+						Name:        "reminder",
+						Usage:       "with --when, remind at `TIME` (HH:MM) on the scheduled day",
+						Destination: &reminderValue,
+					},
+					&cli.StringFlag{
+						Name:        "notes",
+						Usage:       "replace the notes with `TEXT`, or clear them with \"\"",
+						Destination: &notesValue,
+					},
+					&cli.StringFlag{
+						Name:        "tags",
+						Aliases:     []string{"t"},
+						Usage:       "replace the tags with comma-separated `tags`, or clear them with \"\"",
+						Destination: &tags,
+					},
+					&cli.StringSliceFlag{
+						Name:        "tag",
+						Usage:       "a `tag` to replace the tags with, kept whole even if it contains a comma (repeat for several)",
+						Destination: &tagList,
+					},
+				},
+				Action: func(ctx context.Context, cmd *cli.Command) error {
+					// Flags that weren't given stay nil, so an empty value can still clear a field
+					var changes TodoChanges
+					if cmd.IsSet("new-name") {
+						if newName == "" {
+							return cli.Exit("ERROR: --new-name can't be empty", 1)
+						}
+						changes.Name = &newName
+					}
+					if cmd.IsSet("due") {
+						var due time.Time
+						if dueValue != "" {
+							day, err := parseDay(dueValue, time.Local)
+							if err != nil {
+								return cli.Exit("ERROR: --due must be a date in YYYY-MM-DD format", 1)
+							}
+							due = day
+						}
+						changes.Due = &due
+					}
+					if cmd.IsSet("when") {
+						when, err := parseWhen(whenValue)
+						if err != nil {
+							return cli.Exit(err.Error(), 1)
+						}
+						changes.When = &when
+					}
+					if cmd.IsSet("reminder") {
+						if _, _, err := parseReminder(reminderValue); err != nil {
+							return cli.Exit(err.Error(), 1)
+						}
+						changes.Reminder = &reminderValue
+					}
+					if err := checkReminder(changes.When, changes.Reminder); err != nil {
+						return cli.Exit(err.Error(), 1)
+					}
+					if cmd.IsSet("notes") {
+						changes.Notes = &notesValue
+					}
+					if cmd.IsSet("tags") || cmd.IsSet("tag") {
+						todoTags := append(parseTags(tags), tagList...)
+						changes.Tags = &todoTags
+					}
+					if changes == (TodoChanges{}) {
+						return cli.Exit("ERROR: give at least one of --new-name, --due, --when, --notes, --tags, or --tag", 1)
+					}
+
+					result, err := editTodo(ctx, listName, todoName, changes)
+					if err != nil {
+						return err
+					}
+					if !result.Success {
+						return cli.Exit(result.Message, exitCode(result.Err))
+					}
+					printResult(cmd, result)
+					return nil
+				},
+			},
+			{
+				Name:    "open",
+				Usage:   "Reveal a todo in Things.app",
+				Aliases: []string{"o"},
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:        "list",
+						Aliases:     []string{"l"},
+						Usage:       "the `list` containing the to-do",
+						Required:    true,
+						Destination: &listName,
+					},
+					&cli.StringFlag{
+						Name:        "name",
+						Aliases:     []string{"n"},
+						Usage:       "the `name` of the to-do to open",
+						Required:    true,
+						Destination: &todoName,
+					},
+				},
+				Action: func(ctx context.Context, cmd *cli.Command) error {
+					result, err := openTodoInThings(ctx, listName, todoName)
+					if err != nil {
+						return err
+					}
+					if !result.Success {
+						return cli.Exit(result.Message, exitCode(result.Err))
+					}
+					printResult(cmd, result)
+					return nil
+				},
+			},
+			{
+				Name:    "log",
+				Usage:   "Show completed to-dos from the Logbook",
+				Aliases: []string{"lg"},
+				Flags: append([]cli.Flag{
+					&cli.StringFlag{
+						Name:        "date",
+						Aliases:     []string{"d"},
+						Usage:       "show completed to-dos from `TIMEFRAME` (today, yesterday, this week, last week, this month, last month) a specific date (YYYY-MM-DD), or a range (YYYY-MM-DD..YYYY-MM-DD)",
+						Required:    true,
+						Destination: &dateFilter,
+					},
+					&cli.StringFlag{
+						Name:        "area",
+						Aliases:     []string{"a"},
+						Usage:       "filter by `AREA` name",
+						Destination: &areaFilter,
+					},
+					&cli.StringFlag{
+						Name:        "project",
+						Aliases:     []string{"p"},
+						Usage:       "filter by `PROJECT` name",
+						Destination: &projectFilter,
+					},
+					&cli.StringFlag{
+						Name:        "status",
+						Usage:       "only show to-dos with `STATUS` (completed, canceled, all)",
+						Value:       "completed",
+						Destination: &status,
+					},
+					&cli.BoolFlag{
+						Name:        "include-canceled",
+						Usage:       "show canceled to-dos alongside completed ones (same as --status all)",
+						Destination: &includeCanceled,
+					},
+					&cli.StringFlag{
+						Name:        "group-by",
+						Usage:       "group completed to-dos by `FIELD` (area, project)",
+						Destination: &groupBy,
+					},
+					&cli.StringFlag{
+						Name:        "week-start",
+						Usage:       "first `DAY` of the week for \"this week\" (sunday, monday)",
+						Value:       orDefault(config.WeekStart, "sunday"),
+						Destination: &weekStartName,
+					},
+					&cli.StringFlag{
+						Name:        "timezone",
+						Usage:       "match dates in the IANA `ZONE` (e.g., UTC, America/New_York) instead of the local timezone",
+						Value:       config.Timezone,
+						Destination: &timezoneName,
+					},
+					&cli.StringFlag{
+						Name:        "from-time",
+						Usage:       "only show to-dos completed at or after `TIME` (HH:MM) on each day",
+						Destination: &fromTime,
+					},
+					&cli.StringFlag{
+						Name:        "to-time",
+						Usage:       "only show to-dos completed at or before `TIME` (HH:MM) on each day",
+						Destination: &toTime,
+					},
+					&cli.IntFlag{
+						Name:        "limit",
+						Usage:       "show at most `N` to-dos (0 for no limit)",
+						Destination: &limit,
+					},
+					&cli.BoolFlag{
+						Name:        "show-age",
+						Usage:       "show how long each to-do was open before it was completed, in the --long view",
+						Destination: &output.showAge,
+					},
+					&cli.BoolFlag{
+						Name:        "no-flush",
+						Usage:       "don't move completed to-dos to the Logbook first, leaving out any not there yet",
+						Destination: &noFlush,
+					},
+				}, todoOutputFlags(&output, config)...),
 				Action: func(ctx context.Context, cmd *cli.Command) error {
-					result, err := renameTodoInList(listName, todoName, newName)
+					if groupBy != "" && groupBy != "area" && groupBy != "project" {
+						return cli.Exit("ERROR: --group-by must be one of: area, project", 1)
+					}
+					if status != "all" && status != "completed" && normalizeStatus(status) != "canceled" {
+						return cli.Exit("ERROR: --status must be one of: completed, canceled, all", 1)
+					}
+					if includeCanceled {
+						if cmd.IsSet("status") && status != "all" {
+							return cli.Exit("ERROR: --include-canceled and --status cannot be used together", 1)
+						}
+						status = "all"
+					}
+
+					weekStart, err := parseWeekStart(weekStartName)
+					if err != nil {
+						return cli.Exit(err.Error(), 1)
+					}
+					loc, err := parseTimezone(timezoneName)
+					if err != nil {
+						return cli.Exit(err.Error(), 1)
+					}
+
+					// Check the time window before asking Things.app for anything
+					if _, err := filterByTimeOfDay(nil, fromTime, toTime, loc); err != nil {
+						return cli.Exit(err.Error(), 1)
+					}
+
+					// Validate date filter - accept keywords, YYYY-MM-DD dates, or YYYY-MM-DD..YYYY-MM-DD ranges
+					if _, err := parseDateFilter(dateFilter, weekStart, loc); err != nil {
+						if strings.HasPrefix(err.Error(), "ERROR:") {
+							return cli.Exit(err.Error(), 1)
+						}
+						return cli.Exit("ERROR: --date must be one of: today, yesterday, this week, last week, this month, last month, a date in YYYY-MM-DD format, or a range in YYYY-MM-DD..YYYY-MM-DD format", 1)
+					}
+
+					todos, err := getCompletedTodosFiltered(ctx, dateFilter, areaFilter, projectFilter, weekStart, loc, !noFlush)
 					if err != nil {
+						if strings.HasPrefix(err.Error(), "ERROR:") {
+							return cli.Exit(err.Error(), 1)
+						}
 						return err
 					}
-					if !result.Success {
-						return cli.Exit(result.Message, 1)
+					todos, err = filterByTimeOfDay(todos, fromTime, toTime, loc)
+					if err != nil {
+						return cli.Exit(err.Error(), 1)
 					}
-					fmt.Println(formatOperationResult(result))
-					return nil
+					todos = filterTodosByStatus(todos, status)
+					todos = limitTodos(todos, limit)
+
+					// Grouping only applies to the default display; other formats list to-dos as usual
+					if groupBy != "" && !output.formatSelected() {
+						color, err := resolveColor(output.color, cmd.Root().Writer)
+						if err != nil {
+							return err
+						}
+						width, err := resolveWidth(output.width, cmd.Root().Writer)
+						if err != nil {
+							return err
+						}
+						fmt.Fprintln(cmd.Root().Writer, formatTodosGroupedBy(todos, groupBy, color, output.ascii, width))
+						return nil
+					}
+					if output.title {
+						output.header = "Logbook"
+					}
+					return writeTodos(cmd.Root().Writer, todos, output)
 				},
 			},
 			{
-				Name:    "log",
-				Usage:   "Show completed to-dos from the Logbook",
-				Aliases: []string{"lg"},
+				Name:  "stats",
+				Usage: "Summarize completed to-dos from the Logbook by area, project, and tag",
 				Flags: []cli.Flag{
 					&cli.StringFlag{
 						Name:        "date",
 						Aliases:     []string{"d"},
-						Usage:       "show completed to-dos from `TIMEFRAME` (today, this week, this month) or a specific date (YYYY-MM-DD)",
+						Usage:       "summarize to-dos completed in `TIMEFRAME` (today, yesterday, this week, last week, this month, last month) a specific date (YYYY-MM-DD), or a range (YYYY-MM-DD..YYYY-MM-DD)",
 						Required:    true,
 						Destination: &dateFilter,
 					},
 					&cli.StringFlag{
-						Name:        "area",
-						Aliases:     []string{"a"},
-						Usage:       "filter by `AREA` name",
-						Destination: &areaFilter,
+						Name:        "week-start",
+						Usage:       "first `DAY` of the week for \"this week\" (sunday, monday)",
+						Value:       orDefault(config.WeekStart, "sunday"),
+						Destination: &weekStartName,
 					},
 					&cli.StringFlag{
-						Name:        "project",
-						Aliases:     []string{"p"},
-						Usage:       "filter by `PROJECT` name",
-						Destination: &projectFilter,
+						Name:        "timezone",
+						Usage:       "match dates in the IANA `ZONE` (e.g., UTC, America/New_York) instead of the local timezone",
+						Value:       config.Timezone,
+						Destination: &timezoneName,
 					},
 					&cli.BoolFlag{
 						Name:        "jsonl",
-						Usage:       "output todos in JSONL format",
-						Destination: &jsonl,
+						Usage:       "output the summary as a single JSON line",
+						Destination: &statsJSONL,
 					},
 				},
 				Action: func(ctx context.Context, cmd *cli.Command) error {
-					// Validate date filter - accept keywords or YYYY-MM-DD format
-					if dateFilter != "today" && dateFilter != "this week" && dateFilter != "this month" {
-						// Try parsing as YYYY-MM-DD date
-						if _, err := time.Parse("2006-01-02", dateFilter); err != nil {
-							return cli.Exit("ERROR: --date must be one of: today, this week, this month, or a date in YYYY-MM-DD format", 1)
+					weekStart, err := parseWeekStart(weekStartName)
+					if err != nil {
+						return cli.Exit(err.Error(), 1)
+					}
+					loc, err := parseTimezone(timezoneName)
+					if err != nil {
+						return cli.Exit(err.Error(), 1)
+					}
+					if _, err := parseDateFilter(dateFilter, weekStart, loc); err != nil {
+						if strings.HasPrefix(err.Error(), "ERROR:") {
+							return cli.Exit(err.Error(), 1)
 						}
+						return cli.Exit("ERROR: --date must be one of: today, yesterday, this week, last week, this month, last month, a date in YYYY-MM-DD format, or a range in YYYY-MM-DD..YYYY-MM-DD format", 1)
 					}
 
-					todos, err := getCompletedTodosFiltered(dateFilter, areaFilter, projectFilter)
+					todos, err := getCompletedTodosFiltered(ctx, dateFilter, "", "", weekStart, loc, true)
 					if err != nil {
 						if strings.HasPrefix(err.Error(), "ERROR:") {
 							return cli.Exit(err.Error(), 1)
@@ -265,27 +1771,814 @@ func main() {
 						return err
 					}
 
-					if jsonl {
-						for _, todo := range todos {
-							jsonLine, err := formatTodoAsJSONL(todo)
-							if err != nil {
-								return err
+					// The Logbook holds canceled to-dos too; stats only counts completed ones
+					stats := computeStats(filterTodosByStatus(todos, "completed"))
+					if !statsJSONL {
+						fmt.Fprintln(cmd.Root().Writer, formatStats(stats))
+						return nil
+					}
+					line, err := formatStatsAsJSONL(stats)
+					if err != nil {
+						return err
+					}
+					fmt.Fprintln(cmd.Root().Writer, line)
+					return nil
+				},
+			},
+			{
+				Name:  "project",
+				Usage: "Create, rename, and complete projects",
+				Commands: []*cli.Command{
+					{
+						Name:  "create",
+						Usage: "Create a new project",
+						Flags: []cli.Flag{
+							&cli.StringFlag{
+								Name:        "name",
+								Aliases:     []string{"n"},
+								Usage:       "the `name` of the new project",
+								Required:    true,
+								Destination: &projectName,
+							},
+							&cli.StringFlag{
+								Name:        "area",
+								Aliases:     []string{"a"},
+								Usage:       "the `area` to create the project in",
+								Destination: &areaName,
+							},
+						},
+						Action: func(ctx context.Context, cmd *cli.Command) error {
+							result, err := createProject(ctx, projectName, areaName)
+							return finishOperation(cmd, result, err, false)
+						},
+					},
+					{
+						Name:  "rename",
+						Usage: "Rename a project",
+						Flags: []cli.Flag{
+							&cli.StringFlag{
+								Name:        "name",
+								Aliases:     []string{"n"},
+								Usage:       "the `name` of the project to rename",
+								Required:    true,
+								Destination: &projectName,
+							},
+							&cli.StringFlag{
+								Name:        "new-name",
+								Usage:       "the new `name` for the project",
+								Required:    true,
+								Destination: &newName,
+							},
+						},
+						Action: func(ctx context.Context, cmd *cli.Command) error {
+							if strings.TrimSpace(newName) == "" {
+								return cli.Exit("ERROR: --new-name can't be empty", 1)
 							}
-							fmt.Println(jsonLine)
-						}
+							result, err := renameProject(ctx, projectName, newName)
+							return finishOperation(cmd, result, err, false)
+						},
+					},
+					{
+						Name:  "complete",
+						Usage: "Mark a project as completed",
+						Flags: []cli.Flag{
+							&cli.StringFlag{
+								Name:        "name",
+								Aliases:     []string{"n"},
+								Usage:       "the `name` of the project to complete",
+								Required:    true,
+								Destination: &projectName,
+							},
+						},
+						Action: func(ctx context.Context, cmd *cli.Command) error {
+							result, err := completeProject(ctx, projectName)
+							return finishOperation(cmd, result, err, false)
+						},
+					},
+				},
+			},
+			{
+				Name:  "area",
+				Usage: "Create and rename areas",
+				Commands: []*cli.Command{
+					{
+						Name:  "create",
+						Usage: "Create a new area",
+						Flags: []cli.Flag{
+							&cli.StringFlag{
+								Name:        "name",
+								Aliases:     []string{"n"},
+								Usage:       "the `name` of the new area",
+								Required:    true,
+								Destination: &areaName,
+							},
+						},
+						Action: func(ctx context.Context, cmd *cli.Command) error {
+							result, err := createArea(ctx, areaName)
+							return finishOperation(cmd, result, err, false)
+						},
+					},
+					{
+						Name:  "rename",
+						Usage: "Rename an area",
+						Flags: []cli.Flag{
+							&cli.StringFlag{
+								Name:        "name",
+								Aliases:     []string{"n"},
+								Usage:       "the `name` of the area to rename",
+								Required:    true,
+								Destination: &areaName,
+							},
+							&cli.StringFlag{
+								Name:        "new-name",
+								Usage:       "the new `name` for the area",
+								Required:    true,
+								Destination: &newName,
+							},
+						},
+						Action: func(ctx context.Context, cmd *cli.Command) error {
+							if strings.TrimSpace(newName) == "" {
+								return cli.Exit("ERROR: --new-name can't be empty", 1)
+							}
+							result, err := renameArea(ctx, areaName, newName)
+							return finishOperation(cmd, result, err, false)
+						},
+					},
+				},
+			},
+			{
+				Name:  "version",
+				Usage: "Print the version, commit, and build date",
+				Flags: []cli.Flag{
+					&cli.BoolFlag{
+						Name:        "json",
+						Usage:       "output the version as a single JSON line",
+						Destination: &versionJSON,
+					},
+				},
+				Action: func(ctx context.Context, cmd *cli.Command) error {
+					info := currentVersionInfo()
+					if !versionJSON {
+						fmt.Fprintln(cmd.Root().Writer, formatVersionInfo(info))
 						return nil
 					}
-
-					output := formatTodosForDisplay(todos)
-					fmt.Println(output)
+					line, err := formatVersionInfoAsJSON(info)
+					if err != nil {
+						return err
+					}
+					fmt.Fprintln(cmd.Root().Writer, line)
+					return nil
+				},
+			},
+			{
+				Name:   "doctor",
+				Usage:  "Check that tricky to-do names are escaped safely in scripts, without running them",
+				Hidden: true,
+				Action: func(ctx context.Context, cmd *cli.Command) error {
+					checks := runEscapeChecks(ctx)
+					failed := 0
+					for _, check := range checks {
+						if check.Err != nil {
+							failed++
+							fmt.Fprintf(cmd.Root().Writer, "FAIL  %-6s  %q: %v\n", check.Action, check.Name, check.Err)
+						} else {
+							fmt.Fprintf(cmd.Root().Writer, "ok    %-6s  %q\n", check.Action, check.Name)
+						}
+					}
+					if failed > 0 {
+						return cli.Exit(fmt.Sprintf("ERROR: %d of %d escape checks failed", failed, len(checks)), 1)
+					}
+					fmt.Fprintf(cmd.Root().Writer, "All %d escape checks passed\n", len(checks))
 					return nil
 				},
 			},
 		},
 	}
+}
 
-	if err := cmd.Run(context.Background(), os.Args); err != nil {
-		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-		os.Exit(1)
+// readTodoNames returns each non-blank line of r with surrounding whitespace trimmed
+func readTodoNames(r io.Reader) ([]string, error) {
+	var names []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		if name := strings.TrimSpace(scanner.Text()); name != "" {
+			names = append(names, name)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading stdin: %v", err)
+	}
+	return names, nil
+}
+
+// readTodoSpec parses a to-do described as a JSON object in the --json format, reading it from r if spec is "-"
+// The to-do must have a name; fields a new to-do can't have, such as its id or status, are ignored
+func readTodoSpec(spec string, r io.Reader) (NewTodo, error) {
+	data := []byte(spec)
+	if spec == "-" {
+		var err error
+		if data, err = io.ReadAll(r); err != nil {
+			return NewTodo{}, fmt.Errorf("ERROR: reading stdin: %v", err)
+		}
+	}
+
+	var todo Todo
+	if err := json.Unmarshal(data, &todo); err != nil {
+		return NewTodo{}, fmt.Errorf("ERROR: invalid --input-json: %v", err)
+	}
+	if strings.TrimSpace(todo.Name) == "" {
+		return NewTodo{}, fmt.Errorf("ERROR: --input-json needs a non-empty \"name\"")
+	}
+	return newTodoFrom(todo), nil
+}
+
+// parseChecklist splits a --checklist value on newlines and "|" into trimmed, non-empty item names
+func parseChecklist(value string) []string {
+	var items []string
+	for _, item := range strings.FieldsFunc(value, func(r rune) bool { return r == '\n' || r == '|' }) {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// parseTags splits a --tags value on commas into trimmed, non-empty tag names
+func parseTags(value string) []string {
+	var tags []string
+	for _, tag := range strings.Split(value, ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+// printResult writes a successful operation's message, unless --quiet is set
+// Dry runs are printed either way, as showing what would happen is all they do
+func printResult(cmd *cli.Command, result OperationResult) {
+	if quiet && !dryRun {
+		return
+	}
+	fmt.Fprintln(cmd.Root().Writer, formatOperationResult(result))
+}
+
+// finishOperation reports the outcome of a command that changed to-dos
+// A success prints its message, subject to --quiet, and a failure becomes an exit error
+// With asJSON, the result is printed as JSON either way, errors included, and a failure still exits non-zero
+func finishOperation(cmd *cli.Command, result OperationResult, err error, asJSON bool) error {
+	if !asJSON {
+		if err != nil {
+			return err
+		}
+		if !result.Success {
+			return cli.Exit(result.Message, exitCode(result.Err))
+		}
+		printResult(cmd, result)
+		return nil
+	}
+
+	if err != nil {
+		result = OperationResult{Success: false, Message: err.Error()}
+	}
+	jsonOutput, jsonErr := formatOperationResultJSON(result)
+	if jsonErr != nil {
+		return jsonErr
+	}
+	fmt.Fprintln(cmd.Root().Writer, jsonOutput)
+	if !result.Success {
+		return cli.Exit("", exitCode(result.Err))
+	}
+	return nil
+}
+
+// runBatchAdd adds the given names to a list in one call
+// Each result is printed, unless summarize is set, in which case only failures and a final count are
+// asJSONL replaces both with a JSON line per name, successful or not, on stdout
+func runBatchAdd(ctx context.Context, cmd *cli.Command, listName string, names, tags, checklist []string, summarize, asJSONL bool) error {
+	todos := make([]NewTodo, len(names))
+	for i, name := range names {
+		todos[i] = NewTodo{Name: name, Tags: tags, ChecklistItems: checklist}
+	}
+
+	results, err := addTodosToList(ctx, listName, todos)
+	if err != nil {
+		if strings.HasPrefix(err.Error(), "ERROR:") {
+			return cli.Exit(err.Error(), 1)
+		}
+		return err
+	}
+
+	if asJSONL {
+		failed := 0
+		for i, result := range results {
+			if !result.Success {
+				failed++
+			}
+			line, err := formatAddResultJSON(names[i], result)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintln(cmd.Root().Writer, line)
+		}
+		if failed > 0 {
+			return cli.Exit("", exitFailure)
+		}
+		return nil
+	}
+
+	failed := 0
+	for _, result := range results {
+		if !result.Success {
+			failed++
+			fmt.Fprintln(cmd.Root().ErrWriter, formatOperationResult(result))
+			continue
+		}
+		// A dry run has nothing to summarize, so always show what each add would do
+		if !summarize || dryRun {
+			printResult(cmd, result)
+		}
+	}
+	if summarize && !dryRun && !quiet {
+		fmt.Fprintf(cmd.Root().Writer, "Added %d to-dos to list \"%s\"\n", len(results)-failed, listName)
+	}
+	if failed > 0 {
+		return cli.Exit(fmt.Sprintf("ERROR: %d of %d to-dos could not be added", failed, len(results)), 1)
+	}
+	return nil
+}
+
+// confirm writes prompt to out and reads the answer from in, reporting whether it was yes
+// Anything other than y or yes, including no answer at all, counts as no
+// When in isn't a terminal, as in a script, nobody can answer, so it fails with a hint to pass --yes instead
+func confirm(in io.Reader, out io.Writer, prompt string) (bool, error) {
+	if !isInteractive(in) {
+		return false, cli.Exit("ERROR: can't ask for confirmation when stdin isn't a terminal; pass --yes to go ahead", exitFailure)
+	}
+	fmt.Fprintf(out, "%s [y/N] ", prompt)
+	answer, err := bufio.NewReader(in).ReadString('\n')
+	if err != nil && err != io.EOF {
+		return false, fmt.Errorf("error reading answer: %v", err)
+	}
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return true, nil
+	}
+	return false, nil
+}
+
+// confirmDelete shows the to-do that delete would remove and asks whether to go ahead
+// The to-do is identified by id when it's set, otherwise by listName, todoName, and opts
+func confirmDelete(ctx context.Context, in io.Reader, out io.Writer, listName, todoName, id string, opts MatchOptions) (bool, error) {
+	var prompt string
+	if id != "" {
+		name, found, err := todoNameByID(ctx, id)
+		if err != nil {
+			return false, err
+		}
+		if !found {
+			return false, cli.Exit(todoIDNotFound(id).Message, exitNotFound)
+		}
+		prompt = fmt.Sprintf("Delete \"%s\"?", name)
+	} else {
+		matches, err := findTodoMatches(ctx, listName, todoName, opts)
+		if err != nil {
+			if strings.HasPrefix(err.Error(), "ERROR:") {
+				return false, cli.Exit(err.Error(), exitCode(err))
+			}
+			return false, err
+		}
+		if len(matches.Selected) == 1 {
+			prompt = fmt.Sprintf("Delete \"%s\"?", matches.Selected[0])
+		} else {
+			prompt = fmt.Sprintf("Delete %d to-dos named \"%s\"?", len(matches.Selected), todoName)
+		}
+	}
+	return confirm(in, out, prompt)
+}
+
+// confirmAmbiguousMove asks whether to go ahead when several to-dos share the name and only the first would be moved
+// Nothing is asked when the name is unique or --all or --index picked the to-dos explicitly
+func confirmAmbiguousMove(ctx context.Context, in io.Reader, out io.Writer, fromList, todoName string, opts MatchOptions) (bool, error) {
+	if opts.All || opts.Index != 0 {
+		return true, nil
+	}
+	matches, err := findTodoMatches(ctx, fromList, todoName, opts)
+	if err != nil {
+		if strings.HasPrefix(err.Error(), "ERROR:") {
+			return false, cli.Exit(err.Error(), exitCode(err))
+		}
+		return false, err
+	}
+	if matches.Total <= 1 {
+		return true, nil
+	}
+	return confirm(in, out, fmt.Sprintf("Move \"%s\"? %d to-dos match; only the first will be moved", matches.Selected[0], matches.Total))
+}
+
+// requireListAndName checks that a to-do was identified by --list and --name when --id wasn't given
+func requireListAndName(listName, todoName string) error {
+	if normalizeListName(listName) == "" || todoName == "" {
+		return cli.Exit("ERROR: --list and --name are required unless --id is given", 1)
+	}
+	return nil
+}
+
+// applyNameContains returns the text to match a to-do by, taking --name-contains over --name
+// With --name-contains, opts is switched to substring matching
+func applyNameContains(todoName, nameContains string, opts *MatchOptions) (string, error) {
+	if nameContains == "" {
+		return todoName, nil
+	}
+	if todoName != "" {
+		return "", cli.Exit("ERROR: --name and --name-contains cannot be used together", 1)
+	}
+	opts.Contains = true
+	return nameContains, nil
+}
+
+// compileNameRegex compiles a --name-regex pattern, returning nil when none was given
+// The pattern replaces the exact name, so todoName must be empty; with ignoreCase it matches case-insensitively
+func compileNameRegex(pattern, todoName string, ignoreCase bool) (*regexp.Regexp, error) {
+	if pattern == "" {
+		return nil, nil
+	}
+	if todoName != "" {
+		return nil, cli.Exit("ERROR: --name-regex cannot be used with --name or --name-contains", 1)
+	}
+	if ignoreCase {
+		pattern = "(?i)" + pattern
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, cli.Exit(fmt.Sprintf("ERROR: invalid --name-regex: %v", err), 1)
+	}
+	return re, nil
+}
+
+// regexTargets looks up the to-dos in listName that a --name-regex operation with opts acts on, and how many matched
+func regexTargets(ctx context.Context, listName string, re *regexp.Regexp, opts MatchOptions) ([]Todo, int, error) {
+	if listName == "" {
+		return nil, 0, cli.Exit("ERROR: --list is required with --name-regex", 1)
+	}
+	targets, total, err := findTodosByRegex(ctx, listName, re, opts)
+	if err != nil {
+		if strings.HasPrefix(err.Error(), "ERROR:") {
+			return nil, 0, cli.Exit(err.Error(), exitCode(err))
+		}
+		return nil, 0, err
+	}
+	return targets, total, nil
+}
+
+// requireSingleContainsMatch fails when a --name-contains substring matches more than one to-do in listName
+// Callers skip it when --yes says to go ahead anyway
+func requireSingleContainsMatch(ctx context.Context, listName, text string, opts MatchOptions) error {
+	if !opts.Contains {
+		return nil
+	}
+	matches, err := findTodoMatches(ctx, listName, text, opts)
+	if err != nil {
+		if strings.HasPrefix(err.Error(), "ERROR:") {
+			return cli.Exit(err.Error(), exitCode(err))
+		}
+		return err
+	}
+	if matches.Total > 1 {
+		return cli.Exit(fmt.Sprintf("ERROR: %d to-dos in list \"%s\" contain \"%s\"; pass --yes to go ahead anyway", matches.Total, listName, text), 1)
+	}
+	return nil
+}
+
+// buildMatchOptions validates the to-do selection flags shared by delete, move, and rename
+func buildMatchOptions(cmd *cli.Command, ignoreCase, all bool, index int) (MatchOptions, error) {
+	if all && cmd.IsSet("index") {
+		return MatchOptions{}, cli.Exit("ERROR: --all and --index cannot be used together", 1)
+	}
+	if index < 0 {
+		return MatchOptions{}, cli.Exit("ERROR: --index must be zero or greater", 1)
+	}
+	return MatchOptions{IgnoreCase: ignoreCase, All: all, Index: index}, nil
+}
+
+// outputOptions holds the display flags shared by commands that list to-dos
+type outputOptions struct {
+	jsonl     bool
+	json      bool
+	pretty    bool // indent JSON output, implying json
+	csv       bool
+	markdown  bool
+	table     bool
+	long      bool
+	notes     bool   // show notes beneath each to-do, implying long
+	notesWrap int    // column to wrap notes at
+	width     string // column to wrap names at in the plain view: a number, 0 for none, or auto
+	color     string
+	ascii     bool   // use ASCII status symbols
+	fields    string // comma-separated fields to keep in JSONL output
+	header    string // title shown above human-readable output, with the to-do count
+	title     bool   // show a header above human-readable output even where the command has none by default
+	noHeader  bool   // leave out CSV and table header rows and the title above human-readable output
+	format    string // Go template each to-do is printed with
+	withURL   bool   // add a thingsURL link to each JSONL record
+	showAge   bool   // show how long each completed to-do was open, implying long
+	// show when each to-do was last modified in the detailed view; show sets it along with --modified-after
+	showModified bool
+}
+
+// notesWidth returns the column to wrap notes at in the detailed view, or 0 if notes aren't shown
+func (o outputOptions) notesWidth() (int, error) {
+	if o.notesWrap < 1 {
+		return 0, cli.Exit("ERROR: --notes-width must be at least 1", 1)
+	}
+	if !o.notes {
+		return 0, nil
+	}
+	return o.notesWrap, nil
+}
+
+// formatSelected reports whether a format flag other than the default display was given
+func (o outputOptions) formatSelected() bool {
+	return o.jsonl || o.json || o.pretty || o.csv || o.markdown || o.table || o.format != ""
+}
+
+// todoOutputFlags returns the display flags shared by commands that list to-dos, with --color defaulting to config
+func todoOutputFlags(output *outputOptions, config Config) []cli.Flag {
+	return []cli.Flag{
+		&cli.BoolFlag{
+			Name:        "jsonl",
+			Usage:       "output todos in JSONL format",
+			Destination: &output.jsonl,
+		},
+		&cli.StringFlag{
+			Name:        "fields",
+			Usage:       "with --jsonl, only output these comma-separated `FIELDS` (e.g., \"name,due,tags\")",
+			Destination: &output.fields,
+		},
+		&cli.BoolFlag{
+			Name:        "with-url",
+			Usage:       "with --jsonl, add a thingsURL field to each record with a things:/// link that opens the to-do",
+			Destination: &output.withURL,
+		},
+		&cli.BoolFlag{
+			Name:        "json",
+			Usage:       "output todos as a single JSON array",
+			Destination: &output.json,
+		},
+		&cli.BoolFlag{
+			Name:        "pretty",
+			Usage:       "output todos as an indented JSON array (implies --json)",
+			Destination: &output.pretty,
+		},
+		&cli.StringFlag{
+			Name:        "format",
+			Usage:       "output each todo with the Go `TEMPLATE` (e.g., \"{{.Name}} ({{.Area}})\"; {{date \"2006-01-02\" .DueDate}} formats dates)",
+			Destination: &output.format,
+			// Check the template before asking Things.app for anything
+			Action: func(ctx context.Context, cmd *cli.Command, value string) error {
+				if _, err := parseTodoTemplate(value); err != nil {
+					return cli.Exit(err.Error(), 1)
+				}
+				return nil
+			},
+		},
+		&cli.BoolFlag{
+			Name:        "csv",
+			Usage:       "output todos in CSV format",
+			Destination: &output.csv,
+		},
+		&cli.BoolFlag{
+			Name:        "markdown",
+			Usage:       "output todos as a Markdown checklist",
+			Destination: &output.markdown,
+		},
+		&cli.BoolFlag{
+			Name:        "table",
+			Usage:       "output todos in aligned columns",
+			Destination: &output.table,
+		},
+		&cli.BoolFlag{
+			Name:        "header",
+			Usage:       "show a title with the to-do count above the list",
+			Destination: &output.title,
+		},
+		&cli.BoolFlag{
+			Name:        "no-header",
+			Usage:       "leave out the header row of --csv and --table output and the title above the list",
+			Destination: &output.noHeader,
+		},
+		&cli.BoolFlag{
+			Name:        "long",
+			Aliases:     []string{"L"},
+			Usage:       "show due dates, completion times, and tags alongside each to-do",
+			Destination: &output.long,
+		},
+		&cli.BoolFlag{
+			Name:        "show-notes",
+			Usage:       "show notes beneath each to-do (implies --long)",
+			Destination: &output.notes,
+		},
+		&cli.IntFlag{
+			Name:        "notes-width",
+			Usage:       "with --show-notes, wrap notes at `COLUMNS`",
+			Value:       defaultNotesWidth,
+			Destination: &output.notesWrap,
+		},
+		&cli.StringFlag{
+			Name:        "width",
+			Usage:       "wrap to-do names at `COLUMNS`, 0 to not wrap, or auto to use the terminal width",
+			Value:       "auto",
+			Destination: &output.width,
+		},
+		&cli.StringFlag{
+			Name:        "color",
+			Usage:       "colorize status symbols: `WHEN` (auto, always, never)",
+			Value:       orDefault(config.Color, "auto"),
+			Destination: &output.color,
+		},
+		&cli.BoolFlag{
+			Name:        "ascii",
+			Usage:       "use ASCII status symbols ([ ], [x], [-]) for terminals that can't show Unicode",
+			Destination: &output.ascii,
+		},
+	}
+}
+
+// watchTodos clears the screen and runs show, then does so again on every tick until ctx is done
+// A failed update ends the watch with its error, unless it failed because ctx was done
+func watchTodos(ctx context.Context, w io.Writer, ticks <-chan time.Time, show func(context.Context) error) error {
+	for {
+		fmt.Fprint(w, ansiClearScreen)
+		if err := show(ctx); err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticks:
+		}
+	}
+}
+
+// writeTodos writes todos to w in the format selected by the output flags
+func writeTodos(w io.Writer, todos []Todo, output outputOptions) error {
+	if output.fields != "" && !output.jsonl {
+		return cli.Exit("ERROR: --fields can only be used with --jsonl", 1)
+	}
+	if output.withURL && !output.jsonl {
+		return cli.Exit("ERROR: --with-url can only be used with --jsonl", 1)
+	}
+	if output.title && output.noHeader {
+		return cli.Exit("ERROR: --header and --no-header cannot be used together", 1)
+	}
+
+	if output.jsonl {
+		fields, err := parseFields(output.fields)
+		if err != nil {
+			return cli.Exit(err.Error(), 1)
+		}
+		for _, todo := range todos {
+			var jsonLine string
+			switch {
+			case output.withURL:
+				jsonLine, err = formatTodoAsJSONLWithURL(todo, fields)
+			case len(fields) > 0:
+				jsonLine, err = formatProjectedTodoAsJSONL(todo, fields)
+			default:
+				jsonLine, err = formatTodoAsJSONL(todo)
+			}
+			if err != nil {
+				if strings.HasPrefix(err.Error(), "ERROR:") {
+					return cli.Exit(err.Error(), 1)
+				}
+				return err
+			}
+			fmt.Fprintln(w, jsonLine)
+		}
+		return nil
+	}
+
+	if output.json || output.pretty {
+		jsonOutput, err := formatTodosAsJSON(todos, output.pretty)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(w, jsonOutput)
+		return nil
+	}
+
+	if output.format != "" {
+		formatted, err := formatTodosWithTemplate(todos, output.format)
+		if err != nil {
+			return cli.Exit(err.Error(), 1)
+		}
+		if formatted != "" {
+			fmt.Fprintln(w, formatted)
+		}
+		return nil
+	}
+
+	if output.csv {
+		csvOutput, err := formatTodosAsCSV(todos, !output.noHeader)
+		if err != nil {
+			return err
+		}
+		fmt.Fprint(w, csvOutput)
+		return nil
+	}
+
+	if output.markdown {
+		fmt.Fprintln(w, formatTodosAsMarkdown(todos))
+		return nil
+	}
+
+	if output.table {
+		if table := formatTodosAsTable(todos, !output.noHeader); table != "" {
+			fmt.Fprintln(w, table)
+		}
+		return nil
+	}
+
+	color, err := resolveColor(output.color, w)
+	if err != nil {
+		return err
+	}
+
+	if output.header != "" && !output.noHeader {
+		fmt.Fprintln(w, formatTodosWithHeader(output.header, todos))
+		if len(todos) == 0 {
+			return nil
+		}
+	}
+
+	notesWidth, err := output.notesWidth()
+	if err != nil {
+		return err
+	}
+	if output.long || output.notes || output.showAge {
+		fmt.Fprintln(w, formatTodosDetailed(todos, color, output.ascii, notesWidth, output.showAge, output.showModified))
+		return nil
+	}
+
+	width, err := resolveWidth(output.width, w)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(w, formatTodosForDisplay(todos, color, output.ascii, width))
+	return nil
+}
+
+// resolveColor decides whether to colorize output for the given --color mode
+// In auto mode, color is only used when w is a terminal
+func resolveColor(mode string, w io.Writer) (bool, error) {
+	switch mode {
+	case "always":
+		return true, nil
+	case "never":
+		return false, nil
+	case "auto", "":
+		return isTerminal(w), nil
+	default:
+		return false, cli.Exit("ERROR: --color must be one of: auto, always, never", 1)
+	}
+}
+
+// resolveWidth decides the column to wrap to-do names at for the given --width value, with 0 meaning no wrapping
+// In auto mode, names are wrapped at the terminal's width when w is a terminal
+func resolveWidth(value string, w io.Writer) (int, error) {
+	if value == "auto" || value == "" {
+		f, ok := w.(*os.File)
+		if !ok || !isTerminal(w) {
+			return 0, nil
+		}
+		return terminalColumns(f), nil
+	}
+	width, err := strconv.Atoi(value)
+	if err != nil || width < 0 {
+		return 0, cli.Exit("ERROR: --width must be a number of columns, 0, or auto", 1)
+	}
+	return width, nil
+}
+
+// isInteractive reports whether in is someone at a terminal who can answer a prompt
+// Readers other than files, such as the canned answers tests give, count as interactive
+func isInteractive(in io.Reader) bool {
+	f, ok := in.(*os.File)
+	return !ok || isTerminal(f)
+}
+
+// isTerminal reports whether w is a character device such as a TTY
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := f.Stat()
+	if err != nil {
+		return false
 	}
+	return info.Mode()&os.ModeCharDevice != 0
 }
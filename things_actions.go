@@ -1,32 +1,169 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"net/url"
 	"os/exec"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 )
 
 // CommandExecutor interface allows mocking exec.Command in tests
 type CommandExecutor interface {
 	Execute(name string, args ...string) ([]byte, error)
+	ExecuteContext(ctx context.Context, name string, args ...string) ([]byte, error)
 }
 
 // DefaultExecutor implements CommandExecutor using real exec.Command
 type DefaultExecutor struct{}
 
 func (e *DefaultExecutor) Execute(name string, args ...string) ([]byte, error) {
-	return exec.Command(name, args...).Output()
+	return e.ExecuteContext(context.Background(), name, args...)
+}
+
+// ExecuteContext runs the command, killing it if ctx is canceled or its deadline passes
+func (e *DefaultExecutor) ExecuteContext(ctx context.Context, name string, args ...string) ([]byte, error) {
+	output, err := exec.CommandContext(ctx, name, args...).Output()
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		// Report the cancellation rather than the "signal: killed" from the aborted process
+		return output, ctxErr
+	}
+	return output, err
+}
+
+// loggingExecutor wraps another CommandExecutor, writing each command and its arguments to w before running it
+// The arguments are written verbatim, so the JXA passed to osascript shows up exactly as it runs
+type loggingExecutor struct {
+	next CommandExecutor
+	w    io.Writer
+}
+
+func (e *loggingExecutor) Execute(name string, args ...string) ([]byte, error) {
+	e.log(name, args)
+	return e.next.Execute(name, args...)
+}
+
+func (e *loggingExecutor) ExecuteContext(ctx context.Context, name string, args ...string) ([]byte, error) {
+	e.log(name, args)
+	return e.next.ExecuteContext(ctx, name, args...)
+}
+
+func (e *loggingExecutor) log(name string, args []string) {
+	fmt.Fprintln(e.w, strings.Join(append([]string{name}, args...), " "))
+}
+
+// retryingExecutor wraps another CommandExecutor, running a command again when it fails to run at all
+// Failures a script reports in its output, such as "ERROR: ...", aren't errors here, so they're never retried
+// The wait between attempts starts at retryBaseDelay and doubles each time
+type retryingExecutor struct {
+	next    CommandExecutor
+	retries int
+}
+
+// Global retry delay - can be replaced in tests to avoid waiting
+var retryBaseDelay = 250 * time.Millisecond
+
+func (e *retryingExecutor) Execute(name string, args ...string) ([]byte, error) {
+	return e.ExecuteContext(context.Background(), name, args...)
+}
+
+// ExecuteContext stops retrying once ctx is done, returning the context's error
+func (e *retryingExecutor) ExecuteContext(ctx context.Context, name string, args ...string) ([]byte, error) {
+	delay := retryBaseDelay
+	for attempt := 0; ; attempt++ {
+		output, err := e.next.ExecuteContext(ctx, name, args...)
+		if err == nil || attempt >= e.retries || ctx.Err() != nil {
+			return output, err
+		}
+		select {
+		case <-ctx.Done():
+			return output, ctx.Err()
+		case <-time.After(delay):
+		}
+		delay *= 2
+	}
 }
 
 // Global executor - can be replaced in tests
 var executor CommandExecutor = &DefaultExecutor{}
 
+// thingsAvailable records that ensureThingsAvailable has passed, so the check only runs once per process
+var thingsAvailable bool
+
+// ensureThingsAvailable checks that Things.app can be scripted before the first script is run against it
+// osascript's own errors for a missing app are cryptic, so they're replaced with a friendlier message
+func ensureThingsAvailable(ctx context.Context) error {
+	if thingsAvailable {
+		return nil
+	}
+	output, err := executor.ExecuteContext(ctx, "osascript", "-l", "JavaScript", "-e", "Application('Things3').name();")
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return ctxErr
+	}
+	outputStr := string(output)
+	if err != nil || strings.Contains(outputStr, "can't be found") || strings.Contains(outputStr, "Can't get application") {
+		return &thingsError{kind: ErrThingsNotRunning, message: "ERROR: Things 3 does not appear to be installed or running"}
+	}
+	thingsAvailable = true
+	return nil
+}
+
+// runJXA runs a JXA script with osascript, checking that Things.app is available first
+// With --profile, the time until it's called counts as building the script, and the time it takes as executing it
+func runJXA(ctx context.Context, jxaScript string) ([]byte, error) {
+	profiler.lap(phaseBuild)
+	defer profiler.lap(phaseExecute)
+	if err := ensureThingsAvailable(ctx); err != nil {
+		return nil, err
+	}
+	output, err := executor.ExecuteContext(ctx, "osascript", "-l", "JavaScript", "-e", jxaScript)
+	if err != nil {
+		return output, fmt.Errorf("error running JXA script: %v", err)
+	}
+	return output, nil
+}
+
+// Global clock - can be replaced in tests to pin the current time
+var now = time.Now
+
+// Global dry-run switch - when set, mutating actions report the script they would run instead of running it
+var dryRun bool
+
+// dryRunResult returns the result reported instead of running script, describing its intended outcome
+func dryRunResult(script, outcome string) OperationResult {
+	return OperationResult{
+		Success: true,
+		Message: strings.TrimSpace(script) + "\n\nDry run: would " + outcome,
+	}
+}
+
+// dryRunTarget describes which of the to-dos named todoName an operation would act on
+func dryRunTarget(todoName string, opts MatchOptions) string {
+	switch {
+	case opts.All:
+		return fmt.Sprintf("every to-do named \"%s\"", todoName)
+	case opts.Index > 0:
+		// --index counts from zero, but the message counts from one, so --index 1 is the second to-do
+		return fmt.Sprintf("to-do %d of those named \"%s\"", opts.Index+1, todoName)
+	default:
+		return fmt.Sprintf("the to-do named \"%s\"", todoName)
+	}
+}
+
 // JXA code snippet for building a todo item object
 // This is the common logic extracted to avoid duplication
 const jxaTodoObjectBuilder = `
         var item = {
+            id: todo.id(),
             name: todo.name(),
             status: todo.status()
         };
@@ -38,18 +175,31 @@ const jxaTodoObjectBuilder = `
         if (todo.creationDate()) item.creationDate = todo.creationDate().toISOString();
         if (todo.modificationDate()) item.modificationDate = todo.modificationDate().toISOString();
         if (todo.dueDate()) item.dueDate = todo.dueDate().toISOString();
+        if (todo.activationDate()) item.activationDate = todo.activationDate().toISOString();
         if (completionDate) item.completionDate = completionDate.toISOString();
         if (todo.cancellationDate()) item.cancellationDate = todo.cancellationDate().toISOString();
 
-        // Add tag names (convert string to array if needed)
-        var tags = todo.tagNames();
-        if (tags) {
-            if (typeof tags === 'string') {
-                item.tagNames = tags.split(',').map(function(t) { return t.trim(); }).filter(function(t) { return t.length > 0; });
-            } else if (tags.length > 0) {
-                item.tagNames = tags;
-            }
+        // Add tag names from the tag objects, since the tagNames string is comma-separated
+        // and would split tags whose names contain a comma
+        var tags;
+        try {
+            tags = todo.tags().map(function(t) { return t.name(); });
+        } catch (e) {
+            // Without tag objects, a tagNames string can't be split safely, so it's kept as one tag
+            tags = todo.tagNames();
+            if (typeof tags === 'string') tags = tags ? [tags] : [];
         }
+        if (tags && tags.length > 0) item.tagNames = tags;
+
+        // Add checklist items (skipped if this version of Things doesn't expose them)
+        try {
+            var checklist = todo.checklistItems();
+            if (checklist && checklist.length > 0) {
+                item.checklistItems = checklist.map(function(c) {
+                    return {name: c.name(), completed: c.status() === 'completed'};
+                });
+            }
+        } catch (e) {}
 
         // Add parent references
         if (todo.area && todo.area()) item.area = todo.area().name();
@@ -60,6 +210,7 @@ const jxaTodoObjectBuilder = `
 // Todo represents a Things.app todo item with all available properties
 type Todo struct {
 	// Basic properties
+	ID     string `json:"id,omitempty"`
 	Name   string `json:"name"`
 	Notes  string `json:"notes,omitempty"`
 	Status string `json:"status"` // "open", "completed", "canceled"
@@ -67,28 +218,177 @@ type Todo struct {
 	// Date properties
 	CreationDate     *time.Time `json:"creationDate,omitempty"`
 	ModificationDate *time.Time `json:"modificationDate,omitempty"`
-	DueDate          *time.Time `json:"dueDate,omitempty"`
+	DueDate          *Date      `json:"dueDate,omitempty"`
+	ActivationDate   *time.Time `json:"activationDate,omitempty"` // the date the to-do is scheduled for
 	CompletionDate   *time.Time `json:"completionDate,omitempty"`
 	CancellationDate *time.Time `json:"cancellationDate,omitempty"`
 
 	// Tags
 	TagNames []string `json:"tagNames,omitempty"`
 
+	// Checklist
+	ChecklistItems []ChecklistItem `json:"checklistItems,omitempty"`
+
 	// Parent references
 	Area    string `json:"area,omitempty"`
 	Project string `json:"project,omitempty"`
 }
 
+// Date is a whole day, such as a to-do's due date, encoded in JSON as YYYY-MM-DD rather than a timestamp
+// Things keeps it as midnight in the local timezone, so that's the day it stands for
+type Date struct {
+	time.Time
+}
+
+// MarshalJSON encodes the date as its local YYYY-MM-DD day
+func (d Date) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.In(time.Local).Format("2006-01-02"))
+}
+
+// UnmarshalJSON decodes a YYYY-MM-DD day as local midnight, or an RFC3339 timestamp as Things and older output give
+func (d *Date) UnmarshalJSON(data []byte) error {
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	if t, err := time.ParseInLocation("2006-01-02", value, time.Local); err == nil {
+		d.Time = t
+		return nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return fmt.Errorf("invalid date %q: expected YYYY-MM-DD", value)
+	}
+	d.Time = t
+	return nil
+}
+
+// timePtr returns the date as a *time.Time, or nil if d is nil
+func (d *Date) timePtr() *time.Time {
+	if d == nil {
+		return nil
+	}
+	t := d.Time
+	return &t
+}
+
+// ChecklistItem represents a single checklist entry within a Things.app todo
+type ChecklistItem struct {
+	Name      string `json:"name"`
+	Completed bool   `json:"completed"`
+}
+
 // OperationResult represents the result of a Things.app operation
 type OperationResult struct {
-	Success bool
-	Message string
+	Success       bool
+	Message       string
+	AffectedCount int
+	IDs           []string // ids of the to-dos changed, when the action reports them
+	Err           error    // why the operation failed, when it's one of the errors below, such as ErrTodoNotFound
+}
+
+// MatchOptions controls how name-based operations select to-dos
+type MatchOptions struct {
+	IgnoreCase bool // compare names case-insensitively
+	Contains   bool // match names containing the given text instead of equal to it
+	All        bool // act on every matching to-do instead of only the first
+	Index      int  // zero-based position among matching to-dos to act on when All is false
+}
+
+// jxaMatch returns a JXA condition deciding whether the to-do name expression matches an escaped name or substring
+func (o MatchOptions) jxaMatch(nameExpr, escapedName string) string {
+	if !o.Contains {
+		return jxaNameMatch(nameExpr, escapedName, o.IgnoreCase)
+	}
+	if o.IgnoreCase {
+		return fmt.Sprintf("%s.toLowerCase().indexOf('%s'.toLowerCase()) !== -1", nameExpr, escapedName)
+	}
+	return fmt.Sprintf("%s.indexOf('%s') !== -1", nameExpr, escapedName)
+}
+
+// jxaSelect returns a JXA condition, evaluated for each matching to-do, deciding whether to act on it
+func (o MatchOptions) jxaSelect() string {
+	if o.All {
+		return "true"
+	}
+	return fmt.Sprintf("matchCount === %d", o.Index)
+}
+
+// jxaMinMatches returns the number of matches below which the selected to-do doesn't exist
+func (o MatchOptions) jxaMinMatches() int {
+	if o.All {
+		return 0
+	}
+	return o.Index
+}
+
+// Errors reported by Things.app, distinguishable with errors.Is
+var (
+	ErrAreaNotFound     = errors.New("area not found")
+	ErrListNotFound     = errors.New("list not found")
+	ErrProjectNotFound  = errors.New("project not found")
+	ErrThingsNotRunning = errors.New("Things.app isn't running")
+	ErrTodoNotFound     = errors.New("to-do not found")
+)
+
+// thingsError is a failure reported by a JXA script, with a user-facing message and one of the errors above as its kind
+type thingsError struct {
+	kind    error
+	message string
+}
+
+func (e *thingsError) Error() string { return e.message }
+
+func (e *thingsError) Unwrap() error { return e.kind }
+
+// ExitCode lets commands return a thingsError directly and exit with its message, as with cli.Exit
+func (e *thingsError) ExitCode() int { return exitCode(e.kind) }
+
+// Exit codes for failed commands, so scripts can tell a missing to-do or list apart from other failures
+const (
+	exitFailure  = 1
+	exitNotFound = 2
+)
+
+// exitCode returns exitNotFound if err is one of the not-found errors above, and exitFailure for anything else
+func exitCode(err error) int {
+	if errors.Is(err, ErrTodoNotFound) || errors.Is(err, ErrListNotFound) || errors.Is(err, ErrProjectNotFound) || errors.Is(err, ErrAreaNotFound) {
+		return exitNotFound
+	}
+	return exitFailure
+}
+
+// failedResult returns the OperationResult for a failure reported as a thingsError, keeping its kind
+func failedResult(failure error) OperationResult {
+	return OperationResult{Success: false, Message: failure.Error(), Err: failure}
+}
+
+// thingsNotRunning returns ErrThingsNotRunning if an "ERROR: ..." script result says Things.app isn't running, otherwise nil
+// osascript reports this as "Application isn't running." with error number -600
+func thingsNotRunning(outputStr string) error {
+	if strings.Contains(outputStr, "isn't running") || strings.Contains(outputStr, "(-600)") {
+		return &thingsError{kind: ErrThingsNotRunning, message: "ERROR: Things.app isn't running"}
+	}
+	return nil
+}
+
+// classifyJXAError turns an "ERROR: ..." result from a script that looks up todoName in listName into a thingsError
+// Failures other than Things.app not running or the to-do not being found are blamed on the list
+func classifyJXAError(outputStr, listName, todoName string) error {
+	if err := thingsNotRunning(outputStr); err != nil {
+		return err
+	}
+	if strings.Contains(outputStr, "not found in list") {
+		return &thingsError{kind: ErrTodoNotFound, message: fmt.Sprintf("ERROR: To-do \"%s\" not found in list \"%s\"", todoName, listName)}
+	}
+	return &thingsError{kind: ErrListNotFound, message: fmt.Sprintf("ERROR: List \"%s\" not found", listName)}
 }
 
 // getTodosFromListWithFilter retrieves todos from a list, optionally filtered by completion date
 // If filterDateISO is empty, all todos are returned; otherwise, only todos completed after the filter date
-func getTodosFromListWithFilter(listName, filterDateISO string) ([]Todo, error) {
-	escapedListName := strings.ReplaceAll(listName, "'", "\\'")
+func getTodosFromListWithFilter(ctx context.Context, listName, filterDateISO string) ([]Todo, error) {
+	listName = normalizeListName(listName)
+	escapedListName := jxaEscape(listName)
 
 	var filterSetup, filterCheck string
 	if filterDateISO != "" {
@@ -116,18 +416,18 @@ try {
     }
     JSON.stringify(result);
 } catch (e) {
-    'ERROR: List "%s" not found';
+    'ERROR: ' + e.message;
 }
-`, escapedListName, filterSetup, filterCheck, jxaTodoObjectBuilder, escapedListName)
+`, escapedListName, filterSetup, filterCheck, jxaTodoObjectBuilder)
 
-	output, err := executor.Execute("osascript", "-l", "JavaScript", "-e", jxaScript)
+	output, err := runJXA(ctx, jxaScript)
 	if err != nil {
-		return nil, fmt.Errorf("error running JXA script: %v", err)
+		return nil, err
 	}
 
 	outputStr := strings.TrimSpace(string(output))
 	if strings.HasPrefix(outputStr, "ERROR:") {
-		return nil, fmt.Errorf("%s", outputStr)
+		return nil, classifyJXAError(outputStr, listName, "")
 	}
 
 	var todos []Todo
@@ -139,142 +439,243 @@ try {
 }
 
 // getTodosFromList retrieves all todos from the specified list in Things.app as structured data
-func getTodosFromList(listName string) ([]Todo, error) {
-	return getTodosFromListWithFilter(listName, "")
+func getTodosFromList(ctx context.Context, listName string) ([]Todo, error) {
+	return getTodosFromListWithFilter(ctx, listName, "")
 }
 
-// addTodoToList adds a new todo to the specified list in Things.app
-func addTodoToList(listName, text, tags string) (OperationResult, error) {
-	escapedListName := strings.ReplaceAll(listName, "'", "\\'")
-	escapedText := strings.ReplaceAll(text, "'", "\\'")
-	escapedTags := strings.ReplaceAll(tags, "'", "\\'")
-
-	var todoProperties string
-	if tags == "" {
-		todoProperties = fmt.Sprintf("{name: '%s'}", escapedText)
-	} else {
-		todoProperties = fmt.Sprintf("{name: '%s', tagNames: '%s'}", escapedText, escapedTags)
-	}
-
-	jxaScript := fmt.Sprintf(`
+// getLists retrieves the names of all lists in Things.app
+func getLists(ctx context.Context) ([]string, error) {
+	jxaScript := `
 try {
     var app = Application('Things3');
-    var list = app.lists.byName('%s');
-    var todo = app.ToDo(%s);
-    list.toDos.unshift(todo);
-    'SUCCESS';
+    JSON.stringify(app.lists().map(function (list) { return list.name(); }));
 } catch (e) {
     'ERROR: ' + e.message;
 }
-`, escapedListName, todoProperties)
+`
 
-	output, err := executor.Execute("osascript", "-l", "JavaScript", "-e", jxaScript)
+	output, err := runJXA(ctx, jxaScript)
 	if err != nil {
-		return OperationResult{}, fmt.Errorf("error running JXA script: %v", err)
+		return nil, err
 	}
 
 	outputStr := strings.TrimSpace(string(output))
 	if strings.HasPrefix(outputStr, "ERROR:") {
-		return OperationResult{
-			Success: false,
-			Message: outputStr,
-		}, nil
+		if err := thingsNotRunning(outputStr); err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("%s", outputStr)
 	}
 
-	return OperationResult{
-		Success: true,
-		Message: fmt.Sprintf("To-do added successfully to list \"%s\"!", listName),
-	}, nil
+	var lists []string
+	if err := json.Unmarshal([]byte(outputStr), &lists); err != nil {
+		return nil, fmt.Errorf("error parsing JSON: %v", err)
+	}
+	return lists, nil
 }
 
-// deleteTodoFromList deletes a todo by name from a specific list in Things.app
-func deleteTodoFromList(listName, todoName string) (OperationResult, error) {
-	escapedListName := strings.ReplaceAll(listName, "'", "\\'")
-	escapedTodoName := strings.ReplaceAll(todoName, "'", "\\'")
+// defaultSearchConcurrency is how many lists search scans at once unless --concurrency says otherwise
+const defaultSearchConcurrency = 4
+
+// searchTodos retrieves todos from every list whose name contains the query (case-insensitive)
+// If status is non-empty, only todos with that status are returned
+// Lists are scanned by up to concurrency scripts at once, and results are ordered by list name, then by their order in the list
+func searchTodos(ctx context.Context, query, status string, concurrency int) ([]Todo, error) {
+	lists, err := getLists(ctx)
+	if err != nil {
+		return nil, err
+	}
+	concurrency = max(1, min(concurrency, len(lists)))
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make([][]Todo, len(lists))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	var errOnce sync.Once
+	var firstErr error
+	for range concurrency {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				todos, err := searchList(ctx, lists[i], query, status)
+				if err != nil {
+					// The first failure cancels the remaining scans, whose errors are then only noise
+					errOnce.Do(func() {
+						firstErr = err
+						cancel()
+					})
+					continue
+				}
+				results[i] = todos
+			}
+		}()
+	}
+	for i := range lists {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	return mergeSearchResults(lists, results), nil
+}
+
+// mergeSearchResults joins each list's results, ordered by list name and then by their order in the list
+// The same to-do can appear in several lists (e.g. Today and Anytime), so only its first appearance is kept
+func mergeSearchResults(lists []string, results [][]Todo) []Todo {
+	order := make([]int, len(lists))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool { return lists[order[a]] < lists[order[b]] })
+
+	seen := make(map[string]bool)
+	merged := []Todo{}
+	for _, i := range order {
+		for _, todo := range results[i] {
+			if todo.ID != "" {
+				if seen[todo.ID] {
+					continue
+				}
+				seen[todo.ID] = true
+			}
+			merged = append(merged, todo)
+		}
+	}
+	return merged
+}
+
+// searchList retrieves the todos in one list whose name contains the query (case-insensitive)
+// If status is non-empty, only todos with that status are returned
+func searchList(ctx context.Context, listName, query, status string) ([]Todo, error) {
+	escapedListName := jxaEscape(listName)
+	escapedQuery := jxaEscape(query)
+
 	jxaScript := fmt.Sprintf(`
 try {
     var app = Application('Things3');
-    var list = app.lists.byName('%s');
-    var todos = list.toDos();
-    var todoFound = false;
+    var todos = app.lists.byName('%s').toDos();
+    var query = '%s'.toLowerCase();
+    var result = [];
 
     for (var i = 0; i < todos.length; i++) {
-        if (todos[i].name() === '%s') {
-            app.delete(todos[i]);
-            todoFound = true;
-            break;
-        }
-    }
+        var todo = todos[i];
+        if (todo.name().toLowerCase().indexOf(query) === -1) continue;
 
-    if (todoFound) {
-        'SUCCESS';
-    } else {
-        'ERROR: To-do not found in list';
+        var completionDate = todo.completionDate();
+%s
     }
+    JSON.stringify(result);
 } catch (e) {
-    'ERROR: List not found';
+    'ERROR: ' + e.message;
 }
-`, escapedListName, escapedTodoName)
+`, escapedListName, escapedQuery, jxaTodoObjectBuilder)
 
-	output, err := executor.Execute("osascript", "-l", "JavaScript", "-e", jxaScript)
+	output, err := runJXA(ctx, jxaScript)
 	if err != nil {
-		return OperationResult{}, fmt.Errorf("error running JXA script: %v", err)
+		return nil, err
 	}
 
 	outputStr := strings.TrimSpace(string(output))
 	if strings.HasPrefix(outputStr, "ERROR:") {
-		if strings.Contains(outputStr, "not found in list") {
-			return OperationResult{
-				Success: false,
-				Message: fmt.Sprintf("ERROR: To-do \"%s\" not found in list \"%s\"", todoName, listName),
-			}, nil
+		return nil, classifyJXAError(outputStr, listName, "")
+	}
+
+	var todos []Todo
+	if err := json.Unmarshal([]byte(outputStr), &todos); err != nil {
+		return nil, fmt.Errorf("error parsing JSON: %v", err)
+	}
+
+	// Statuses are compared here rather than in the script so variant spellings still match
+	return filterTodosByStatus(todos, status), nil
+}
+
+// splitCommaTags separates the tags Things' comma-separated tagNames property can carry from those containing a comma
+func splitCommaTags(tags []string) (plain, withComma []string) {
+	for _, tag := range tags {
+		if strings.Contains(tag, ",") {
+			withComma = append(withComma, tag)
+		} else {
+			plain = append(plain, tag)
 		}
-		return OperationResult{
-			Success: false,
-			Message: fmt.Sprintf("ERROR: List \"%s\" not found", listName),
-		}, nil
 	}
+	return plain, withComma
+}
 
-	return OperationResult{
-		Success: true,
-		Message: fmt.Sprintf("To-do \"%s\" deleted successfully from list \"%s\"!", todoName, listName),
-	}, nil
+// jxaTodoProperties returns a JXA object literal with the properties for a new to-do
+// Tags containing a comma are left out, since tagNames would split them; see jxaAttachTags
+func jxaTodoProperties(text string, tags, checklist []string) string {
+	escapedText := jxaEscape(text)
+	properties := []string{fmt.Sprintf("name: '%s'", escapedText)}
+
+	if plain, _ := splitCommaTags(tags); len(plain) > 0 {
+		escapedTags := make([]string, len(plain))
+		for i, tag := range plain {
+			escapedTags[i] = jxaEscape(tag)
+		}
+		properties = append(properties, fmt.Sprintf("tagNames: '%s'", strings.Join(escapedTags, ", ")))
+	}
+
+	if len(checklist) > 0 {
+		escapedItems := make([]string, len(checklist))
+		for i, item := range checklist {
+			escapedItems[i] = fmt.Sprintf("'%s'", jxaEscape(item))
+		}
+		properties = append(properties, fmt.Sprintf("checklistItems: [%s]", strings.Join(escapedItems, ", ")))
+	}
+
+	return "{" + strings.Join(properties, ", ") + "}"
 }
 
-// moveTodoBetweenLists moves a todo from one list to another in Things.app
-func moveTodoBetweenLists(fromList, toList, todoName string) (OperationResult, error) {
-	escapedFromList := strings.ReplaceAll(fromList, "\"", "\\\"")
-	escapedToList := strings.ReplaceAll(toList, "\"", "\\\"")
-	escapedTodoName := strings.ReplaceAll(todoName, "\"", "\\\"")
-
-	applescript := fmt.Sprintf(`
-try
-    tell application "Things3"
-        set todoItem to first to do of list "%s" whose name is "%s"
-        move todoItem to list "%s"
-        return "SUCCESS"
-    end tell
-on error errMsg
-    if errMsg contains "Can't get" then
-        return "ERROR: To-do not found"
-    else
-        return "ERROR: " & errMsg
-    end if
-end try
-`, escapedFromList, escapedTodoName, escapedToList)
-
-	output, err := executor.Execute("osascript", "-e", applescript)
-	if err != nil {
-		return OperationResult{}, fmt.Errorf("error running AppleScript: %v", err)
+// jxaAttachTags returns JXA statements attaching the tags containing a comma to todo as tag objects
+// Unlike tagNames, this needs the tags to exist in Things already
+func jxaAttachTags(tags []string) string {
+	_, withComma := splitCommaTags(tags)
+	var statements []string
+	for _, tag := range withComma {
+		escapedTag := jxaEscape(tag)
+		statements = append(statements, fmt.Sprintf("\n    todo.tags.push(app.tags.byName('%s'));", escapedTag))
+	}
+	return strings.Join(statements, "")
+}
+
+// addTodoToList adds a new todo to the specified list in Things.app
+// Checklist items are optional and are created in the given order
+func addTodoToList(ctx context.Context, listName, text string, tags, checklist []string) (OperationResult, error) {
+	listName = normalizeListName(listName)
+	escapedListName := jxaEscape(listName)
+	todoProperties := jxaTodoProperties(text, tags, checklist)
+
+	jxaScript := fmt.Sprintf(`
+try {
+    var app = Application('Things3');
+    var list = app.lists.byName('%s');
+    var todo = app.ToDo(%s);
+    list.toDos.unshift(todo);%s
+    'SUCCESS: 1 ' + JSON.stringify([todo.id()]);
+} catch (e) {
+    'ERROR: ' + e.message;
+}
+`, escapedListName, todoProperties, jxaAttachTags(tags))
+
+	if dryRun {
+		return dryRunResult(jxaScript, fmt.Sprintf("add to-do \"%s\" to list \"%s\"", text, listName)), nil
+	}
+
+	output, err := runJXA(ctx, jxaScript)
+	if err != nil {
+		return OperationResult{}, err
 	}
 
 	outputStr := strings.TrimSpace(string(output))
 	if strings.HasPrefix(outputStr, "ERROR:") {
-		if strings.Contains(outputStr, "not found") {
-			return OperationResult{
-				Success: false,
-				Message: fmt.Sprintf("ERROR: To-do \"%s\" not found in list \"%s\"", todoName, fromList),
-			}, nil
+		if err := thingsNotRunning(outputStr); err != nil {
+			return OperationResult{}, err
 		}
 		return OperationResult{
 			Success: false,
@@ -282,193 +683,1954 @@ end try
 		}, nil
 	}
 
+	_, ids := parseMatchResult(outputStr)
 	return OperationResult{
-		Success: true,
-		Message: fmt.Sprintf("To-do \"%s\" moved successfully from list \"%s\" to list \"%s\"!", todoName, fromList, toList),
+		Success:       true,
+		Message:       fmt.Sprintf("To-do added successfully to list \"%s\"!", listName),
+		AffectedCount: 1,
+		IDs:           ids,
 	}, nil
 }
 
-// renameTodoInList renames a todo by name in a specific list in Things.app
-func renameTodoInList(listName, oldName, newName string) (OperationResult, error) {
-	escapedListName := strings.ReplaceAll(listName, "'", "\\'")
-	escapedOldName := strings.ReplaceAll(oldName, "'", "\\'")
-	escapedNewName := strings.ReplaceAll(newName, "'", "\\'")
+// addTodoToProject adds a new todo to the specified project in Things.app, under heading if it's non-empty
+// Checklist items are optional and are created in the given order
+func addTodoToProject(ctx context.Context, projectName, heading, text string, tags, checklist []string) (OperationResult, error) {
+	todoProperties := jxaTodoProperties(text, tags, checklist)
+
+	// The to-do goes into the heading's to-dos, so a missing heading fails rather than leaving it loose in the project
+	container := "project"
+	var headingLookup string
+	if heading != "" {
+		container = "heading"
+		headingLookup = fmt.Sprintf(`
+    var heading = project.headings.byName('%s');
+    try {
+        heading.name();
+    } catch (e) {
+        throw new Error('Heading not found');
+    }`, jxaEscape(heading))
+	}
+
 	jxaScript := fmt.Sprintf(`
 try {
-    var app = Application('Things3');
-    var list = app.lists.byName('%s');
-    var todos = list.toDos();
-    var todoFound = false;
-
-    for (var i = 0; i < todos.length; i++) {
-        if (todos[i].name() === '%s') {
-            todos[i].name = '%s';
-            todoFound = true;
-            break;
-        }
-    }
-
-    if (todoFound) {
-        'SUCCESS';
-    } else {
-        'ERROR: To-do not found in list';
-    }
+    var app = Application('Things3');%s%s
+    var todo = app.ToDo(%s);
+    %s.toDos.push(todo);%s
+    'SUCCESS: 1 ' + JSON.stringify([todo.id()]);
 } catch (e) {
-    'ERROR: List not found';
+    'ERROR: ' + e.message;
 }
-`, escapedListName, escapedOldName, escapedNewName)
+`, jxaProjectLookup(projectName), headingLookup, todoProperties, container, jxaAttachTags(tags))
+
+	destination := fmt.Sprintf("project \"%s\"", projectName)
+	if heading != "" {
+		destination = fmt.Sprintf("heading \"%s\" in project \"%s\"", heading, projectName)
+	}
+
+	if dryRun {
+		return dryRunResult(jxaScript, fmt.Sprintf("add to-do \"%s\" to %s", text, destination)), nil
+	}
 
-	output, err := executor.Execute("osascript", "-l", "JavaScript", "-e", jxaScript)
+	output, err := runJXA(ctx, jxaScript)
 	if err != nil {
-		return OperationResult{}, fmt.Errorf("error running JXA script: %v", err)
+		return OperationResult{}, err
 	}
 
 	outputStr := strings.TrimSpace(string(output))
 	if strings.HasPrefix(outputStr, "ERROR:") {
-		if strings.Contains(outputStr, "not found in list") {
+		if err := thingsNotRunning(outputStr); err != nil {
+			return OperationResult{}, err
+		}
+		if strings.Contains(outputStr, "Project not found") {
+			return failedResult(&thingsError{kind: ErrProjectNotFound, message: fmt.Sprintf("ERROR: Project \"%s\" not found", projectName)}), nil
+		}
+		if strings.Contains(outputStr, "Heading not found") {
 			return OperationResult{
 				Success: false,
-				Message: fmt.Sprintf("ERROR: To-do \"%s\" not found in list \"%s\"", oldName, listName),
+				Message: fmt.Sprintf("ERROR: Heading \"%s\" not found in project \"%s\"", heading, projectName),
 			}, nil
 		}
 		return OperationResult{
 			Success: false,
-			Message: fmt.Sprintf("ERROR: List \"%s\" not found", listName),
+			Message: outputStr,
 		}, nil
 	}
 
+	_, ids := parseMatchResult(outputStr)
 	return OperationResult{
-		Success: true,
-		Message: fmt.Sprintf("To-do \"%s\" renamed to \"%s\" in list \"%s\"!", oldName, newName, listName),
+		Success:       true,
+		Message:       fmt.Sprintf("To-do added successfully to %s!", destination),
+		AffectedCount: 1,
+		IDs:           ids,
 	}, nil
 }
 
-// logCompletedNow tells Things.app to move completed todos to the Logbook
-func logCompletedNow() error {
-	jxaScript := `
-try {
-    var app = Application('Things3');
-    app.logCompletedNow();
-    'SUCCESS';
-} catch (e) {
-    'ERROR: ' + e.message;
+// thingsWhenLists maps built-in list names to the "when" value the Things URL scheme uses for them
+var thingsWhenLists = map[string]string{
+	"today":    "today",
+	"tomorrow": "tomorrow",
+	"evening":  "evening",
+	"anytime":  "anytime",
+	"someday":  "someday",
 }
-`
-	output, err := executor.Execute("osascript", "-l", "JavaScript", "-e", jxaScript)
-	if err != nil {
-		return fmt.Errorf("error running JXA script: %v", err)
-	}
 
-	outputStr := strings.TrimSpace(string(output))
-	if strings.HasPrefix(outputStr, "ERROR:") {
-		return fmt.Errorf("%s", outputStr)
+// buildAddURL builds a things:///add URL that creates a to-do in the given list
+// The Inbox is the URL scheme's default, so it needs no parameter
+// Things splits the tags parameter on commas, so tags containing one can't be sent this way; addTodoViaURL refuses them
+func buildAddURL(name, list string, tags []string, notes string) string {
+	params := []string{"title=" + urlEncode(name)}
+	if notes != "" {
+		params = append(params, "notes="+urlEncode(notes))
+	}
+	if when, ok := thingsWhenLists[strings.ToLower(list)]; ok {
+		params = append(params, "when="+when)
+	} else if list != "" && !strings.EqualFold(list, "inbox") {
+		params = append(params, "list="+urlEncode(list))
 	}
+	if len(tags) > 0 {
+		params = append(params, "tags="+urlEncode(strings.Join(tags, ",")))
+	}
+	return "things:///add?" + strings.Join(params, "&")
+}
 
-	return nil
+// urlEncode percent-encodes a URL query value
+// Spaces become %20 rather than "+", which Things doesn't decode
+func urlEncode(value string) string {
+	return strings.ReplaceAll(url.QueryEscape(value), "+", "%20")
 }
 
-// calculateStartDate returns the start date based on the filter
-func calculateStartDate(filter string) time.Time {
-	now := time.Now()
+// addTodoViaURL adds a new todo by opening a things:///add URL instead of running JXA
+// This works where AppleScript automation is blocked, but Things.app can't report whether it succeeded
+func addTodoViaURL(ctx context.Context, listName, text string, tags, checklist []string) (OperationResult, error) {
+	listName = normalizeListName(listName)
+	// buildAddURL would send such a tag as two, so it's refused rather than split
+	if _, withComma := splitCommaTags(tags); len(withComma) > 0 {
+		return OperationResult{
+			Success: false,
+			Message: fmt.Sprintf("ERROR: tag \"%s\" contains a comma, which the Things URL scheme can't send; add the to-do without --via url", withComma[0]),
+		}, nil
+	}
+	addURL := buildAddURL(text, listName, tags, "")
+	if len(checklist) > 0 {
+		addURL += "&checklist-items=" + urlEncode(strings.Join(checklist, "\n"))
+	}
+	if dryRun {
+		return dryRunResult("open "+addURL, fmt.Sprintf("add to-do \"%s\" to list \"%s\"", text, listName)), nil
+	}
+	if err := ensureThingsAvailable(ctx); err != nil {
+		return OperationResult{}, err
+	}
+	if _, err := executor.ExecuteContext(ctx, "open", addURL); err != nil {
+		return OperationResult{}, fmt.Errorf("error opening Things URL: %v", err)
+	}
+
+	return OperationResult{
+		Success:       true,
+		Message:       fmt.Sprintf("To-do sent to list \"%s\" via the Things URL scheme!", listName),
+		AffectedCount: 1,
+	}, nil
+}
+
+// NewTodo describes a to-do to create with addTodosToList
+type NewTodo struct {
+	Name           string     `json:"name"`
+	Tags           []string   `json:"tags,omitempty"`
+	Notes          string     `json:"notes,omitempty"`
+	ChecklistItems []string   `json:"checklistItems,omitempty"`
+	DueDate        *time.Time `json:"dueDate,omitempty"`
+	ActivationDate *time.Time `json:"activationDate,omitempty"` // schedule the to-do for this date
+}
+
+// addTodosToList adds several todos to the specified list in a single osascript invocation
+// The returned results line up with todos, so one failed item doesn't hide the others
+func addTodosToList(ctx context.Context, listName string, todos []NewTodo) ([]OperationResult, error) {
+	listName = normalizeListName(listName)
+	if len(todos) == 0 {
+		return nil, nil
+	}
+
+	escapedListName := jxaEscape(listName)
+	// JSON is valid JavaScript, so the items can be embedded without further escaping
+	items, err := json.Marshal(todos)
+	if err != nil {
+		return nil, fmt.Errorf("error encoding to-dos: %v", err)
+	}
+
+	jxaScript := fmt.Sprintf(`
+try {
+    var app = Application('Things3');
+    var list = app.lists.byName('%s');
+    list.name(); // Fail up front if the list doesn't exist
+    var items = %s;
+    var results = [];
+
+    for (var i = 0; i < items.length; i++) {
+        try {
+            var properties = {name: items[i].name};
+            var tags = items[i].tags || [];
+            // tagNames is split on commas, so tags containing one are attached as tag objects below
+            var plainTags = tags.filter(function (t) { return t.indexOf(',') === -1; });
+            if (plainTags.length > 0) properties.tagNames = plainTags.join(', ');
+            if (items[i].notes) properties.notes = items[i].notes;
+            if (items[i].checklistItems) properties.checklistItems = items[i].checklistItems;
+            if (items[i].dueDate) properties.dueDate = new Date(items[i].dueDate);
+            var todo = app.ToDo(properties);
+            list.toDos.unshift(todo);
+            tags.filter(function (t) { return t.indexOf(',') !== -1; }).forEach(function (t) {
+                todo.tags.push(app.tags.byName(t));
+            });
+            if (items[i].activationDate) app.schedule(todo, {for: new Date(items[i].activationDate)});
+            results.push({success: true, id: todo.id()});
+        } catch (e) {
+            results.push({success: false, message: 'ERROR: ' + e.message});
+        }
+    }
+    JSON.stringify(results);
+} catch (e) {
+    'ERROR: ' + e.message;
+}
+`, escapedListName, items)
+
+	if dryRun {
+		results := make([]OperationResult, len(todos))
+		for i, todo := range todos {
+			results[i] = OperationResult{
+				Success: true,
+				Message: fmt.Sprintf("Dry run: would add to-do \"%s\" to list \"%s\"", todo.Name, listName),
+			}
+		}
+		// Every to-do is added by the same script, so only show it once
+		results[0] = dryRunResult(jxaScript, fmt.Sprintf("add to-do \"%s\" to list \"%s\"", todos[0].Name, listName))
+		return results, nil
+	}
+
+	output, err := runJXA(ctx, jxaScript)
+	if err != nil {
+		return nil, err
+	}
+
+	outputStr := strings.TrimSpace(string(output))
+	if strings.HasPrefix(outputStr, "ERROR:") {
+		if err := thingsNotRunning(outputStr); err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("%s", outputStr)
+	}
+
+	var itemResults []struct {
+		Success bool   `json:"success"`
+		Message string `json:"message"`
+		ID      string `json:"id"`
+	}
+	if err := json.Unmarshal([]byte(outputStr), &itemResults); err != nil {
+		return nil, fmt.Errorf("error parsing JSON: %v", err)
+	}
+	if len(itemResults) != len(todos) {
+		return nil, fmt.Errorf("expected %d results, got %d", len(todos), len(itemResults))
+	}
+
+	results := make([]OperationResult, len(todos))
+	for i, item := range itemResults {
+		if !item.Success {
+			results[i] = OperationResult{
+				Success: false,
+				Message: fmt.Sprintf("%s (to-do \"%s\")", item.Message, todos[i].Name),
+			}
+			continue
+		}
+		results[i] = OperationResult{
+			Success:       true,
+			Message:       fmt.Sprintf("To-do \"%s\" added successfully to list \"%s\"!", todos[i].Name, listName),
+			AffectedCount: 1,
+		}
+		if item.ID != "" {
+			results[i].IDs = []string{item.ID}
+		}
+	}
+	return results, nil
+}
+
+// jxaEscaper escapes text for a single-quoted JavaScript string literal
+// Backslashes go first so the escapes added for the other characters aren't doubled
+var jxaEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	`'`, `\'`,
+	"\n", `\n`,
+	"\r", `\r`,
+	"\u2028", `\u2028`,
+	"\u2029", `\u2029`,
+)
+
+// jxaEscape escapes text for embedding between single quotes in a JXA script
+// Line terminators are escaped too, since a literal one would end the string early
+func jxaEscape(text string) string {
+	return jxaEscaper.Replace(text)
+}
+
+// jxaNameMatch returns a JXA condition comparing a to-do name expression against an escaped name
+// With ignoreCase, both sides are lowercased before comparing
+func jxaNameMatch(nameExpr, escapedName string, ignoreCase bool) string {
+	if ignoreCase {
+		return fmt.Sprintf("%s.toLowerCase() === '%s'.toLowerCase()", nameExpr, escapedName)
+	}
+	return fmt.Sprintf("%s === '%s'", nameExpr, escapedName)
+}
+
+// parseMatchResult extracts the match count and changed to-dos' ids from a "SUCCESS: <n> <JSON array of ids>" script result
+// The ids are optional, and any other successful output is treated as a single match with no ids
+func parseMatchResult(outputStr string) (int, []string) {
+	rest, found := strings.CutPrefix(outputStr, "SUCCESS:")
+	if !found {
+		return 1, nil
+	}
+	countStr, idsJSON, _ := strings.Cut(strings.TrimSpace(rest), " ")
+	count, err := strconv.Atoi(countStr)
+	if err != nil || count < 1 {
+		count = 1
+	}
+	var ids []string
+	if err := json.Unmarshal([]byte(idsJSON), &ids); err != nil {
+		ids = nil
+	}
+	return count, ids
+}
+
+// indexOutOfRangeMessage returns an error message if the script reported that --index exceeded the matches
+func indexOutOfRangeMessage(outputStr string, opts MatchOptions, todoName, listName string) (string, bool) {
+	countStr, found := strings.CutPrefix(outputStr, "ERROR: Index out of range:")
+	if !found {
+		return "", false
+	}
+	count, _ := strconv.Atoi(strings.TrimSpace(countStr))
+	return fmt.Sprintf("ERROR: Index %d is out of range; only %d to-dos named \"%s\" found in list \"%s\"", opts.Index, count, todoName, listName), true
+}
+
+// matchCountNote returns a message suffix noting how many to-dos matched when more than one did
+func matchCountNote(count int, opts MatchOptions) string {
+	if count <= 1 || opts.All || opts.Index != 0 {
+		return ""
+	}
+	return fmt.Sprintf(" (%d to-dos matched; only the first was changed)", count)
+}
+
+// TodoMatches describes the to-dos a name-based operation would act on
+type TodoMatches struct {
+	Selected []string `json:"selected"` // Names of the to-dos that would be changed
+	Total    int      `json:"total"`    // Number of to-dos whose name matched
+}
+
+// findTodoMatches looks up which to-dos named todoName in listName an operation with opts would act on, without changing them
+// Errors starting with "ERROR:" mean the list or the selected to-do doesn't exist
+func findTodoMatches(ctx context.Context, listName, todoName string, opts MatchOptions) (TodoMatches, error) {
+	listName = normalizeListName(listName)
+	escapedListName := jxaEscape(listName)
+	escapedTodoName := jxaEscape(todoName)
+	jxaScript := fmt.Sprintf(`
+try {
+    var app = Application('Things3');
+    var list = app.lists.byName('%s');
+    var todos = list.toDos();
+    var matchCount = 0;
+    var selected = [];
+
+    for (var i = 0; i < todos.length; i++) {
+        if (%s) {
+            if (%s) {
+                selected.push(todos[i].name());
+            }
+            matchCount++;
+        }
+    }
+
+    if (matchCount > %d) {
+        JSON.stringify({selected: selected, total: matchCount});
+    } else if (matchCount > 0) {
+        'ERROR: Index out of range: ' + matchCount;
+    } else {
+        'ERROR: To-do not found in list';
+    }
+} catch (e) {
+    'ERROR: List not found: ' + e.message;
+}
+`, escapedListName, opts.jxaMatch("todos[i].name()", escapedTodoName), opts.jxaSelect(), opts.jxaMinMatches())
+
+	output, err := runJXA(ctx, jxaScript)
+	if err != nil {
+		return TodoMatches{}, err
+	}
+
+	outputStr := strings.TrimSpace(string(output))
+	if strings.HasPrefix(outputStr, "ERROR:") {
+		if message, ok := indexOutOfRangeMessage(outputStr, opts, todoName, listName); ok {
+			return TodoMatches{}, errors.New(message)
+		}
+		return TodoMatches{}, classifyJXAError(outputStr, listName, todoName)
+	}
+
+	var matches TodoMatches
+	if err := json.Unmarshal([]byte(outputStr), &matches); err != nil {
+		return TodoMatches{}, fmt.Errorf("error parsing JSON: %v", err)
+	}
+	return matches, nil
+}
+
+// deleteTodoFromList deletes a todo by name from a specific list in Things.app
+func deleteTodoFromList(ctx context.Context, listName, todoName string, opts MatchOptions) (OperationResult, error) {
+	listName = normalizeListName(listName)
+	escapedListName := jxaEscape(listName)
+	escapedTodoName := jxaEscape(todoName)
+	jxaScript := fmt.Sprintf(`
+try {
+    var app = Application('Things3');
+    var list = app.lists.byName('%s');
+    var todos = list.toDos();
+    var matchCount = 0;
+    var ids = [];
+
+    for (var i = 0; i < todos.length; i++) {
+        if (%s) {
+            if (%s) {
+                ids.push(todos[i].id());
+                app.delete(todos[i]);
+            }
+            matchCount++;
+        }
+    }
+
+    if (matchCount > %d) {
+        'SUCCESS: ' + matchCount + ' ' + JSON.stringify(ids);
+    } else if (matchCount > 0) {
+        'ERROR: Index out of range: ' + matchCount;
+    } else {
+        'ERROR: To-do not found in list';
+    }
+} catch (e) {
+    'ERROR: List not found: ' + e.message;
+}
+`, escapedListName, opts.jxaMatch("todos[i].name()", escapedTodoName), opts.jxaSelect(), opts.jxaMinMatches())
+
+	if dryRun {
+		return dryRunResult(jxaScript, fmt.Sprintf("delete %s from list \"%s\"", dryRunTarget(todoName, opts), listName)), nil
+	}
+
+	output, err := runJXA(ctx, jxaScript)
+	if err != nil {
+		return OperationResult{}, err
+	}
+
+	outputStr := strings.TrimSpace(string(output))
+	if strings.HasPrefix(outputStr, "ERROR:") {
+		if message, ok := indexOutOfRangeMessage(outputStr, opts, todoName, listName); ok {
+			return OperationResult{Success: false, Message: message}, nil
+		}
+		failure := classifyJXAError(outputStr, listName, todoName)
+		if errors.Is(failure, ErrThingsNotRunning) {
+			return OperationResult{}, failure
+		}
+		return failedResult(failure), nil
+	}
+
+	matchCount, ids := parseMatchResult(outputStr)
+	if opts.All {
+		return OperationResult{
+			Success:       true,
+			Message:       fmt.Sprintf("Deleted %d to-dos named \"%s\" from list \"%s\"!", matchCount, todoName, listName),
+			AffectedCount: matchCount,
+			IDs:           ids,
+		}, nil
+	}
+
+	return OperationResult{
+		Success:       true,
+		Message:       fmt.Sprintf("To-do \"%s\" deleted successfully from list \"%s\"!", todoName, listName) + matchCountNote(matchCount, opts),
+		AffectedCount: 1,
+		IDs:           ids,
+	}, nil
+}
+
+// moveDestination describes where moveTodo puts the to-dos it matches
+type moveDestination struct {
+	kind   string // "list" or "project", used in messages
+	name   string
+	lookup string // JXA expression resolving the destination
+	move   string // JXA statement moving the to-do expression %s to destination
+	verify bool   // fail if the destination doesn't exist, since the move wouldn't report it
+
+	needsAuthToken bool // the move goes through the URL scheme, so it needs authToken
+}
+
+// smartListIDs are the ids of Things' built-in smart lists
+// Their to-dos are picked by start date rather than stored in the list, so they're looked up by id, which also finds them under localized names
+var smartListIDs = map[string]string{
+	"today":    "TMTodayListSource",
+	"upcoming": "TMCalendarListSource",
+	"anytime":  "TMNextListSource",
+	"someday":  "TMSomedayListSource",
+}
+
+// normalizeListName trims the whitespace around a list name, so " Work " finds the list Work
+// Spaces inside the name are kept, since they're part of it
+func normalizeListName(listName string) string {
+	return strings.TrimSpace(listName)
+}
+
+// jxaListLookup returns a JXA expression resolving the list named listName, by id for a built-in smart list
+func jxaListLookup(listName string) string {
+	if id, ok := smartListIDs[strings.ToLower(listName)]; ok {
+		return fmt.Sprintf("app.lists.byId('%s')", id)
+	}
+	return fmt.Sprintf("app.lists.byName('%s')", jxaEscape(listName))
+}
+
+// scheduledLists are the lists whose to-dos are picked by their start date, so moving there schedules instead
+// Each is a JXA statement scheduling the to-do expression %s
+// Upcoming has no day of its own, so a to-do moved there is scheduled for tomorrow
+var scheduledLists = map[string]string{
+	"today":    "app.schedule(%s, {for: new Date()});",
+	"upcoming": "var when = new Date(); when.setDate(when.getDate() + 1); app.schedule(%s, {for: when});",
+}
+
+// Global Things URL scheme auth token - set from $THINGS_AUTH_TOKEN or the config file
+// Updating a to-do through the URL scheme needs it; Things shows it under Settings > General > Enable Things URLs
+var authToken string
+
+// errNoAuthToken explains how to provide the auth token when an update needs it
+const errNoAuthToken = "ERROR: this goes through the Things URL scheme, which needs an auth token: set THINGS_AUTH_TOKEN or \"auth-token\" in the config file"
+
+// jxaWhenUpdate returns a JXA statement setting when the to-do expression %s starts through the URL scheme's update command
+// when is anything the URL scheme's when takes: anytime, someday, or a YYYY-MM-DD@HH:MM time that also sets a reminder
+// Scripts can't put a to-do in Anytime or Someday, or set a reminder, themselves, so this is how Things does it
+func jxaWhenUpdate(when string) string {
+	// The statement is a format string for the to-do expression, so percent signs in the values are doubled
+	escape := func(value string) string { return strings.ReplaceAll(jxaEscape(value), "%", "%%") }
+	return "var currentApp = Application.currentApplication(); currentApp.includeStandardAdditions = true; " +
+		"currentApp.openLocation('things:///update?auth-token=' + encodeURIComponent('" + escape(authToken) + "')" +
+		" + '&id=' + encodeURIComponent(%s.id()) + '&when=' + encodeURIComponent('" + escape(when) + "'));"
+}
+
+// jxaScheduleOnList returns the JXA statement putting the to-do expression %s on the smart list named list,
+// and whether list is one whose to-dos are picked by their start date rather than moved there
+func jxaScheduleOnList(list string) (string, bool) {
+	key := strings.ToLower(list)
+	if schedule, ok := scheduledLists[key]; ok {
+		return schedule, true
+	}
+	if key == "anytime" || key == "someday" {
+		return jxaWhenUpdate(key), true
+	}
+	return "", false
+}
+
+// listDestination returns the destination for moving to-dos to a list
+// Today, Upcoming, Anytime, and Someday can't be moved to, so to-dos are scheduled for them instead
+func listDestination(toList string) moveDestination {
+	toList = normalizeListName(toList)
+	dest := moveDestination{
+		kind:   "list",
+		name:   toList,
+		lookup: fmt.Sprintf("app.lists.byName('%s')", jxaEscape(toList)),
+		move:   "app.move(%s, {to: destination});",
+	}
+	if schedule, ok := jxaScheduleOnList(toList); ok {
+		dest.move = schedule
+		key := strings.ToLower(toList)
+		dest.needsAuthToken = key == "anytime" || key == "someday"
+	}
+	return dest
+}
+
+// authTokenMissing returns a failed result if moving to d goes through the URL scheme and there's no auth token to do it with
+func (d moveDestination) authTokenMissing() (OperationResult, bool) {
+	if d.needsAuthToken && authToken == "" {
+		return OperationResult{Success: false, Message: errNoAuthToken}, true
+	}
+	return OperationResult{}, false
+}
+
+// projectDestination returns the destination for moving to-dos into a project
+// Projects can't be targeted by move, so the to-do's project is set instead
+func projectDestination(projectName string) moveDestination {
+	return moveDestination{
+		kind:   "project",
+		name:   projectName,
+		lookup: fmt.Sprintf("app.projects.byName('%s')", jxaEscape(projectName)),
+		move:   "%s.project = destination;",
+		verify: true,
+	}
+}
+
+// jxaVerify returns JXA code throwing "Destination not found" if the destination doesn't exist, when the move wouldn't report it
+func (d moveDestination) jxaVerify() string {
+	if !d.verify {
+		return ""
+	}
+	return `
+    try {
+        destination.name();
+    } catch (e) {
+        throw new Error('Destination not found');
+    }`
+}
+
+// notFoundMessage returns the error message for a destination that doesn't exist
+func (d moveDestination) notFoundMessage() string {
+	return fmt.Sprintf("ERROR: %s \"%s\" not found", strings.ToUpper(d.kind[:1])+d.kind[1:], d.name)
+}
+
+// moveTodoBetweenLists moves a todo from one list to another in Things.app
+func moveTodoBetweenLists(ctx context.Context, fromList, toList, todoName string, opts MatchOptions) (OperationResult, error) {
+	return moveTodo(ctx, fromList, todoName, opts, listDestination(toList))
+}
+
+// moveTodoToProject moves a todo from a list into a project in Things.app
+func moveTodoToProject(ctx context.Context, fromList, projectName, todoName string, opts MatchOptions) (OperationResult, error) {
+	return moveTodo(ctx, fromList, todoName, opts, projectDestination(projectName))
+}
+
+// moveTodo moves a todo by name from a list to the given destination in Things.app
+func moveTodo(ctx context.Context, fromList, todoName string, opts MatchOptions, dest moveDestination) (OperationResult, error) {
+	fromList = normalizeListName(fromList)
+	if result, missing := dest.authTokenMissing(); missing {
+		return result, nil
+	}
+	escapedTodoName := jxaEscape(todoName)
+
+	jxaScript := fmt.Sprintf(`
+try {
+    var app = Application('Things3');
+    var fromList = %s;
+    var destination = %s;%s
+    var todos = fromList.toDos();
+    var matchCount = 0;
+    var ids = [];
+
+    for (var i = 0; i < todos.length; i++) {
+        if (%s) {
+            if (%s) {
+                ids.push(todos[i].id());
+                %s
+            }
+            matchCount++;
+        }
+    }
+
+    if (matchCount > %d) {
+        'SUCCESS: ' + matchCount + ' ' + JSON.stringify(ids);
+    } else if (matchCount > 0) {
+        'ERROR: Index out of range: ' + matchCount;
+    } else {
+        'ERROR: To-do not found';
+    }
+} catch (e) {
+    'ERROR: ' + e.message;
+}
+`, jxaListLookup(fromList), dest.lookup, dest.jxaVerify(), opts.jxaMatch("todos[i].name()", escapedTodoName), opts.jxaSelect(), fmt.Sprintf(dest.move, "todos[i]"), opts.jxaMinMatches())
+
+	if dryRun {
+		return dryRunResult(jxaScript, fmt.Sprintf("move %s from list \"%s\" to %s \"%s\"", dryRunTarget(todoName, opts), fromList, dest.kind, dest.name)), nil
+	}
+
+	output, err := runJXA(ctx, jxaScript)
+	if err != nil {
+		return OperationResult{}, err
+	}
+
+	outputStr := strings.TrimSpace(string(output))
+	if strings.HasPrefix(outputStr, "ERROR:") {
+		if err := thingsNotRunning(outputStr); err != nil {
+			return OperationResult{}, err
+		}
+		if message, ok := indexOutOfRangeMessage(outputStr, opts, todoName, fromList); ok {
+			return OperationResult{Success: false, Message: message}, nil
+		}
+		if strings.Contains(outputStr, "Destination not found") {
+			return OperationResult{
+				Success: false,
+				Message: dest.notFoundMessage(),
+			}, nil
+		}
+		if strings.Contains(outputStr, "not found") {
+			return failedResult(&thingsError{kind: ErrTodoNotFound, message: fmt.Sprintf("ERROR: To-do \"%s\" not found in list \"%s\"", todoName, fromList)}), nil
+		}
+		return OperationResult{
+			Success: false,
+			Message: outputStr,
+		}, nil
+	}
+
+	matchCount, ids := parseMatchResult(outputStr)
+	if opts.All {
+		return OperationResult{
+			Success:       true,
+			Message:       fmt.Sprintf("Moved %d to-dos named \"%s\" from list \"%s\" to %s \"%s\"!", matchCount, todoName, fromList, dest.kind, dest.name),
+			AffectedCount: matchCount,
+			IDs:           ids,
+		}, nil
+	}
+
+	return OperationResult{
+		Success:       true,
+		Message:       fmt.Sprintf("To-do \"%s\" moved successfully from list \"%s\" to %s \"%s\"!", todoName, fromList, dest.kind, dest.name) + matchCountNote(matchCount, opts),
+		AffectedCount: 1,
+		IDs:           ids,
+	}, nil
+}
+
+// moveTodosByTag moves every to-do in fromList carrying tag to toList in Things.app
+func moveTodosByTag(ctx context.Context, fromList, toList, tag string) (OperationResult, error) {
+	fromList = normalizeListName(fromList)
+	toList = normalizeListName(toList)
+	dest := listDestination(toList)
+	if result, missing := dest.authTokenMissing(); missing {
+		return result, nil
+	}
+	// When no to-do is tagged nothing is moved, which would hide a missing destination, so it's always checked
+	dest.verify = true
+	jxaScript := fmt.Sprintf(`
+try {
+    var app = Application('Things3');
+    var fromList = %s;
+    var destination = %s;%s
+    var todos;
+    try {
+        todos = fromList.toDos();
+    } catch (e) {
+        throw new Error('List not found: ' + e.message);
+    }
+    var ids = [];
+
+    for (var i = 0; i < todos.length; i++) {
+        var tagged = todos[i].tags().some(function(t) { return t.name() === '%s'; });
+        if (tagged) {
+            ids.push(todos[i].id());
+            %s
+        }
+    }
+
+    'SUCCESS: ' + ids.length + ' ' + JSON.stringify(ids);
+} catch (e) {
+    'ERROR: ' + e.message;
+}
+`, jxaListLookup(fromList), dest.lookup, dest.jxaVerify(), jxaEscape(tag), fmt.Sprintf(dest.move, "todos[i]"))
+
+	if dryRun {
+		return dryRunResult(jxaScript, fmt.Sprintf("move every to-do tagged \"%s\" from list \"%s\" to list \"%s\"", tag, fromList, toList)), nil
+	}
+
+	output, err := runJXA(ctx, jxaScript)
+	if err != nil {
+		return OperationResult{}, err
+	}
+
+	outputStr := strings.TrimSpace(string(output))
+	if strings.HasPrefix(outputStr, "ERROR:") {
+		if err := thingsNotRunning(outputStr); err != nil {
+			return OperationResult{}, err
+		}
+		if strings.Contains(outputStr, "Destination not found") {
+			return OperationResult{Success: false, Message: dest.notFoundMessage()}, nil
+		}
+		if strings.Contains(outputStr, "List not found") {
+			return failedResult(classifyJXAError(outputStr, fromList, "")), nil
+		}
+		return OperationResult{Success: false, Message: outputStr}, nil
+	}
+
+	// Every moved to-do's id is reported, so they give the count even when nothing was moved
+	_, ids := parseMatchResult(outputStr)
+	if len(ids) == 0 {
+		return failedResult(&thingsError{kind: ErrTodoNotFound, message: fmt.Sprintf("ERROR: No to-dos tagged \"%s\" found in list \"%s\"", tag, fromList)}), nil
+	}
+	return OperationResult{
+		Success:       true,
+		Message:       fmt.Sprintf("Moved %d to-dos tagged \"%s\" from list \"%s\" to list \"%s\"!", len(ids), tag, fromList, toList),
+		AffectedCount: len(ids),
+		IDs:           ids,
+	}, nil
+}
+
+// renameTodoInList renames a todo by name in a specific list in Things.app
+func renameTodoInList(ctx context.Context, listName, oldName, newName string, opts MatchOptions) (OperationResult, error) {
+	listName = normalizeListName(listName)
+	escapedListName := jxaEscape(listName)
+	escapedOldName := jxaEscape(oldName)
+	escapedNewName := jxaEscape(newName)
+	jxaScript := fmt.Sprintf(`
+try {
+    var app = Application('Things3');
+    var list = app.lists.byName('%s');
+    var todos = list.toDos();
+    var matchCount = 0;
+    var ids = [];
+
+    for (var i = 0; i < todos.length; i++) {
+        if (%s) {
+            if (%s) {
+                ids.push(todos[i].id());
+                todos[i].name = '%s';
+            }
+            matchCount++;
+        }
+    }
+
+    if (matchCount > %d) {
+        'SUCCESS: ' + matchCount + ' ' + JSON.stringify(ids);
+    } else if (matchCount > 0) {
+        'ERROR: Index out of range: ' + matchCount;
+    } else {
+        'ERROR: To-do not found in list';
+    }
+} catch (e) {
+    'ERROR: List not found: ' + e.message;
+}
+`, escapedListName, opts.jxaMatch("todos[i].name()", escapedOldName), opts.jxaSelect(), escapedNewName, opts.jxaMinMatches())
+
+	if dryRun {
+		return dryRunResult(jxaScript, fmt.Sprintf("rename %s in list \"%s\" to \"%s\"", dryRunTarget(oldName, opts), listName, newName)), nil
+	}
+
+	output, err := runJXA(ctx, jxaScript)
+	if err != nil {
+		return OperationResult{}, err
+	}
+
+	outputStr := strings.TrimSpace(string(output))
+	if strings.HasPrefix(outputStr, "ERROR:") {
+		if message, ok := indexOutOfRangeMessage(outputStr, opts, oldName, listName); ok {
+			return OperationResult{Success: false, Message: message}, nil
+		}
+		failure := classifyJXAError(outputStr, listName, oldName)
+		if errors.Is(failure, ErrThingsNotRunning) {
+			return OperationResult{}, failure
+		}
+		return failedResult(failure), nil
+	}
+
+	matchCount, ids := parseMatchResult(outputStr)
+	if opts.All {
+		return OperationResult{
+			Success:       true,
+			Message:       fmt.Sprintf("Renamed %d to-dos named \"%s\" to \"%s\" in list \"%s\"!", matchCount, oldName, newName, listName),
+			AffectedCount: matchCount,
+			IDs:           ids,
+		}, nil
+	}
+
+	return OperationResult{
+		Success:       true,
+		Message:       fmt.Sprintf("To-do \"%s\" renamed to \"%s\" in list \"%s\"!", oldName, newName, listName) + matchCountNote(matchCount, opts),
+		AffectedCount: 1,
+		IDs:           ids,
+	}, nil
+}
+
+// completeTodoInList marks a todo by name in a specific list as completed in Things.app
+func completeTodoInList(ctx context.Context, listName, todoName string, opts MatchOptions) (OperationResult, error) {
+	listName = normalizeListName(listName)
+	escapedListName := jxaEscape(listName)
+	escapedTodoName := jxaEscape(todoName)
+	jxaScript := fmt.Sprintf(`
+try {
+    var app = Application('Things3');
+    var list = app.lists.byName('%s');
+    var todos = list.toDos();
+    var matchCount = 0;
+    var ids = [];
+
+    for (var i = 0; i < todos.length; i++) {
+        if (%s) {
+            if (%s) {
+                ids.push(todos[i].id());
+                todos[i].status = 'completed';
+            }
+            matchCount++;
+        }
+    }
+
+    if (matchCount > %d) {
+        'SUCCESS: ' + matchCount + ' ' + JSON.stringify(ids);
+    } else if (matchCount > 0) {
+        'ERROR: Index out of range: ' + matchCount;
+    } else {
+        'ERROR: To-do not found in list';
+    }
+} catch (e) {
+    'ERROR: List not found: ' + e.message;
+}
+`, escapedListName, opts.jxaMatch("todos[i].name()", escapedTodoName), opts.jxaSelect(), opts.jxaMinMatches())
+
+	if dryRun {
+		return dryRunResult(jxaScript, fmt.Sprintf("complete %s in list \"%s\"", dryRunTarget(todoName, opts), listName)), nil
+	}
+
+	output, err := runJXA(ctx, jxaScript)
+	if err != nil {
+		return OperationResult{}, err
+	}
+
+	outputStr := strings.TrimSpace(string(output))
+	if strings.HasPrefix(outputStr, "ERROR:") {
+		if message, ok := indexOutOfRangeMessage(outputStr, opts, todoName, listName); ok {
+			return OperationResult{Success: false, Message: message}, nil
+		}
+		failure := classifyJXAError(outputStr, listName, todoName)
+		if errors.Is(failure, ErrThingsNotRunning) {
+			return OperationResult{}, failure
+		}
+		return failedResult(failure), nil
+	}
+
+	matchCount, ids := parseMatchResult(outputStr)
+	if opts.All {
+		return OperationResult{
+			Success:       true,
+			Message:       fmt.Sprintf("Completed %d to-dos named \"%s\" in list \"%s\"!", matchCount, todoName, listName),
+			AffectedCount: matchCount,
+			IDs:           ids,
+		}, nil
+	}
+
+	return OperationResult{
+		Success:       true,
+		Message:       fmt.Sprintf("To-do \"%s\" completed in list \"%s\"!", todoName, listName) + matchCountNote(matchCount, opts),
+		AffectedCount: 1,
+		IDs:           ids,
+	}, nil
+}
+
+// completeTodosInList marks every open to-do in listName as completed in Things.app, or only those carrying tag when it's set
+// To-dos that are already completed or canceled are skipped, so they keep their original completion dates
+func completeTodosInList(ctx context.Context, listName, tag string) (OperationResult, error) {
+	listName = normalizeListName(listName)
+	selects := "true"
+	target := "every open to-do"
+	if tag != "" {
+		selects = fmt.Sprintf("todos[i].tags().some(function(t) { return t.name() === '%s'; })", jxaEscape(tag))
+		target = fmt.Sprintf("every open to-do tagged \"%s\"", tag)
+	}
+	jxaScript := fmt.Sprintf(`
+try {
+    var app = Application('Things3');
+    var todos = app.lists.byName('%s').toDos();
+    var ids = [];
+
+    for (var i = 0; i < todos.length; i++) {
+        if (todos[i].status() === 'open' && %s) {
+            ids.push(todos[i].id());
+            todos[i].status = 'completed';
+        }
+    }
+
+    'SUCCESS: ' + ids.length + ' ' + JSON.stringify(ids);
+} catch (e) {
+    'ERROR: List not found: ' + e.message;
+}
+`, jxaEscape(listName), selects)
+
+	if dryRun {
+		return dryRunResult(jxaScript, fmt.Sprintf("complete %s in list \"%s\"", target, listName)), nil
+	}
+
+	output, err := runJXA(ctx, jxaScript)
+	if err != nil {
+		return OperationResult{}, err
+	}
+
+	outputStr := strings.TrimSpace(string(output))
+	if strings.HasPrefix(outputStr, "ERROR:") {
+		failure := classifyJXAError(outputStr, listName, "")
+		if errors.Is(failure, ErrThingsNotRunning) {
+			return OperationResult{}, failure
+		}
+		return failedResult(failure), nil
+	}
+
+	// Completing nothing isn't a failure: the list may simply be done already
+	_, ids := parseMatchResult(outputStr)
+	return OperationResult{
+		Success:       true,
+		Message:       fmt.Sprintf("Completed %d to-dos in list \"%s\"!", len(ids), listName),
+		AffectedCount: len(ids),
+		IDs:           ids,
+	}, nil
+}
+
+// reopenTodo marks the first completed or canceled to-do named todoName in listName as open again in Things.app
+// Open to-dos with the name are skipped, so a duplicate still in progress doesn't hide the one to reopen
+func reopenTodo(ctx context.Context, listName, todoName string) (OperationResult, error) {
+	listName = normalizeListName(listName)
+	escapedListName := jxaEscape(listName)
+	escapedTodoName := jxaEscape(todoName)
+	jxaScript := fmt.Sprintf(`
+try {
+    var app = Application('Things3');
+    var list = app.lists.byName('%s');
+    var todos = list.toDos();
+    var todo = null;
+
+    for (var i = 0; i < todos.length; i++) {
+        if (%s && todos[i].status() !== 'open') {
+            todo = todos[i];
+            break;
+        }
+    }
+
+    if (todo) {
+        var id = todo.id();
+        todo.status = 'open';
+        'SUCCESS: 1 ' + JSON.stringify([id]);
+    } else {
+        'ERROR: To-do not found in list';
+    }
+} catch (e) {
+    'ERROR: List not found: ' + e.message;
+}
+`, escapedListName, jxaNameMatch("todos[i].name()", escapedTodoName, false))
+
+	if dryRun {
+		return dryRunResult(jxaScript, fmt.Sprintf("reopen the to-do named \"%s\" in list \"%s\"", todoName, listName)), nil
+	}
+
+	output, err := runJXA(ctx, jxaScript)
+	if err != nil {
+		return OperationResult{}, err
+	}
+
+	outputStr := strings.TrimSpace(string(output))
+	if strings.HasPrefix(outputStr, "ERROR:") {
+		failure := classifyJXAError(outputStr, listName, todoName)
+		if errors.Is(failure, ErrThingsNotRunning) {
+			return OperationResult{}, failure
+		}
+		return failedResult(failure), nil
+	}
+
+	_, ids := parseMatchResult(outputStr)
+	return OperationResult{
+		Success:       true,
+		Message:       fmt.Sprintf("To-do \"%s\" reopened!", todoName),
+		AffectedCount: 1,
+		IDs:           ids,
+	}, nil
+}
+
+// JXA helper that looks up a to-do directly by its id
+// byId returns a lazy reference, so the name is read to fail fast when the id doesn't exist
+const jxaFindTodoByID = `
+function findTodoById(app, id) {
+    var todo = app.toDos.byId(id);
+    todo.name();
+    return todo;
+}`
+
+// todoByIDScript returns a JXA script running action code against the to-do with the given id, available to it as todo
+func todoByIDScript(id, action string) string {
+	escapedID := jxaEscape(id)
+	return fmt.Sprintf(`%s
+
+try {
+    var app = Application('Things3');
+    var todo = findTodoById(app, '%s');
+    var name = todo.name();
+    %s
+    'SUCCESS: ' + name;
+} catch (e) {
+    'ERROR: To-do not found: ' + e.message;
+}
+`, jxaFindTodoByID, escapedID, action)
+}
+
+// runTodoByIDScript runs a script built by todoByIDScript
+// Returns the to-do's name as it was before the action, and false if no to-do has that id
+func runTodoByIDScript(ctx context.Context, jxaScript string) (string, bool, error) {
+	output, err := runJXA(ctx, jxaScript)
+	if err != nil {
+		return "", false, err
+	}
+
+	outputStr := strings.TrimSpace(string(output))
+	if err := thingsNotRunning(outputStr); err != nil {
+		return "", false, err
+	}
+	name, found := strings.CutPrefix(outputStr, "SUCCESS: ")
+	if !found {
+		return "", false, nil
+	}
+	return name, true, nil
+}
+
+// todoIDNotFound returns the failed result for an id that doesn't match any to-do
+func todoIDNotFound(id string) OperationResult {
+	return failedResult(&thingsError{kind: ErrTodoNotFound, message: fmt.Sprintf("ERROR: No to-do found with id \"%s\"", id)})
+}
+
+// todoNameByID returns the name of the to-do with the given id, and false if there is none
+func todoNameByID(ctx context.Context, id string) (string, bool, error) {
+	return runTodoByIDScript(ctx, todoByIDScript(id, ""))
+}
+
+// deleteTodoByID deletes the todo with the given id in Things.app
+func deleteTodoByID(ctx context.Context, id string) (OperationResult, error) {
+	jxaScript := todoByIDScript(id, "app.delete(todo);")
+	if dryRun {
+		return dryRunResult(jxaScript, fmt.Sprintf("delete the to-do with id \"%s\"", id)), nil
+	}
+	name, found, err := runTodoByIDScript(ctx, jxaScript)
+	if err != nil {
+		return OperationResult{}, err
+	}
+	if !found {
+		return todoIDNotFound(id), nil
+	}
+	return OperationResult{
+		Success:       true,
+		Message:       fmt.Sprintf("To-do \"%s\" deleted successfully!", name),
+		AffectedCount: 1,
+		IDs:           []string{id},
+	}, nil
+}
+
+// completeTodoByID marks the todo with the given id as completed in Things.app
+func completeTodoByID(ctx context.Context, id string) (OperationResult, error) {
+	jxaScript := todoByIDScript(id, "todo.status = 'completed';")
+	if dryRun {
+		return dryRunResult(jxaScript, fmt.Sprintf("complete the to-do with id \"%s\"", id)), nil
+	}
+	name, found, err := runTodoByIDScript(ctx, jxaScript)
+	if err != nil {
+		return OperationResult{}, err
+	}
+	if !found {
+		return todoIDNotFound(id), nil
+	}
+	return OperationResult{
+		Success:       true,
+		Message:       fmt.Sprintf("To-do \"%s\" completed!", name),
+		AffectedCount: 1,
+		IDs:           []string{id},
+	}, nil
+}
+
+// renameTodoByID renames the todo with the given id in Things.app
+func renameTodoByID(ctx context.Context, id, newName string) (OperationResult, error) {
+	escapedNewName := jxaEscape(newName)
+	jxaScript := todoByIDScript(id, fmt.Sprintf("todo.name = '%s';", escapedNewName))
+	if dryRun {
+		return dryRunResult(jxaScript, fmt.Sprintf("rename the to-do with id \"%s\" to \"%s\"", id, newName)), nil
+	}
+	name, found, err := runTodoByIDScript(ctx, jxaScript)
+	if err != nil {
+		return OperationResult{}, err
+	}
+	if !found {
+		return todoIDNotFound(id), nil
+	}
+	return OperationResult{
+		Success:       true,
+		Message:       fmt.Sprintf("To-do \"%s\" renamed to \"%s\"!", name, newName),
+		AffectedCount: 1,
+		IDs:           []string{id},
+	}, nil
+}
+
+// moveTodoByID moves the todo with the given id to the destination in Things.app
+func moveTodoByID(ctx context.Context, id string, dest moveDestination) (OperationResult, error) {
+	if result, missing := dest.authTokenMissing(); missing {
+		return result, nil
+	}
+	jxaScript := todoByIDScript(id, fmt.Sprintf("var destination = %s;%s\n    %s", dest.lookup, dest.jxaVerify(), fmt.Sprintf(dest.move, "todo")))
+	if dryRun {
+		return dryRunResult(jxaScript, fmt.Sprintf("move the to-do with id \"%s\" to %s \"%s\"", id, dest.kind, dest.name)), nil
+	}
+
+	output, err := runJXA(ctx, jxaScript)
+	if err != nil {
+		return OperationResult{}, err
+	}
+
+	outputStr := strings.TrimSpace(string(output))
+	if err := thingsNotRunning(outputStr); err != nil {
+		return OperationResult{}, err
+	}
+	if strings.Contains(outputStr, "Destination not found") {
+		return OperationResult{Success: false, Message: dest.notFoundMessage()}, nil
+	}
+	name, found := strings.CutPrefix(outputStr, "SUCCESS: ")
+	if !found {
+		return todoIDNotFound(id), nil
+	}
+	return OperationResult{
+		Success:       true,
+		Message:       fmt.Sprintf("To-do \"%s\" moved to %s \"%s\"!", name, dest.kind, dest.name),
+		AffectedCount: 1,
+		IDs:           []string{id},
+	}, nil
+}
+
+// findTodosByRegex returns the to-dos in listName whose names match re that an operation with opts would act on, and how many matched
+// Names are matched here rather than in the script, so patterns never have to be carried into JavaScript
+func findTodosByRegex(ctx context.Context, listName string, re *regexp.Regexp, opts MatchOptions) ([]Todo, int, error) {
+	listName = normalizeListName(listName)
+	todos, err := getTodosFromList(ctx, listName)
+	if err != nil {
+		return nil, 0, err
+	}
+	matched := filterByNameRegex(todos, re)
+	switch {
+	case len(matched) == 0:
+		return nil, 0, &thingsError{kind: ErrTodoNotFound, message: fmt.Sprintf("ERROR: No to-do matching /%s/ found in list \"%s\"", re, listName)}
+	case opts.All:
+		return matched, len(matched), nil
+	case opts.Index >= len(matched):
+		return nil, 0, fmt.Errorf("ERROR: Index %d is out of range; only %d to-dos matching /%s/ found in list \"%s\"", opts.Index, len(matched), re, listName)
+	}
+	return matched[opts.Index : opts.Index+1], len(matched), nil
+}
+
+// applyByID runs an id-based action on each to-do in turn and combines the results
+// It stops at the first failure, whose result reports the to-dos already changed
+func applyByID(ctx context.Context, todos []Todo, action func(context.Context, string) (OperationResult, error)) (OperationResult, error) {
+	var combined OperationResult
+	var messages []string
+	for _, todo := range todos {
+		result, err := action(ctx, todo.ID)
+		if err != nil {
+			return OperationResult{}, err
+		}
+		if !result.Success {
+			result.AffectedCount = combined.AffectedCount
+			result.IDs = combined.IDs
+			return result, nil
+		}
+		messages = append(messages, result.Message)
+		combined.AffectedCount += result.AffectedCount
+		combined.IDs = append(combined.IDs, result.IDs...)
+	}
+	combined.Success = true
+	combined.Message = strings.Join(messages, "\n")
+	return combined, nil
+}
+
+// restoreTodoFromTrash moves a to-do by name from the Trash back to the Inbox in Things.app
+// If several trashed to-dos share the name, the most recently modified one, normally the last deleted, is restored
+func restoreTodoFromTrash(ctx context.Context, todoName string) (OperationResult, error) {
+	escapedTodoName := jxaEscape(todoName)
+	jxaScript := fmt.Sprintf(`
+try {
+    var app = Application('Things3');
+    var todos = app.lists.byName('Trash').toDos();
+    var todo = null;
+    var matchCount = 0;
+
+    for (var i = 0; i < todos.length; i++) {
+        if (%s) {
+            if (!todo || todos[i].modificationDate() > todo.modificationDate()) {
+                todo = todos[i];
+            }
+            matchCount++;
+        }
+    }
+
+    if (todo) {
+        var id = todo.id();
+        app.move(todo, {to: app.lists.byName('Inbox')});
+        'SUCCESS: ' + matchCount + ' ' + JSON.stringify([id]);
+    } else {
+        'ERROR: To-do not found in list';
+    }
+} catch (e) {
+    'ERROR: List not found: ' + e.message;
+}
+`, jxaNameMatch("todos[i].name()", escapedTodoName, false))
+
+	if dryRun {
+		return dryRunResult(jxaScript, fmt.Sprintf("restore the to-do named \"%s\" from the Trash to the Inbox", todoName)), nil
+	}
+
+	output, err := runJXA(ctx, jxaScript)
+	if err != nil {
+		return OperationResult{}, err
+	}
+
+	outputStr := strings.TrimSpace(string(output))
+	if strings.HasPrefix(outputStr, "ERROR:") {
+		failure := classifyJXAError(outputStr, "Trash", todoName)
+		if errors.Is(failure, ErrThingsNotRunning) {
+			return OperationResult{}, failure
+		}
+		return failedResult(failure), nil
+	}
+
+	matchCount, ids := parseMatchResult(outputStr)
+	message := fmt.Sprintf("To-do \"%s\" restored from the Trash to the Inbox!", todoName)
+	if matchCount > 1 {
+		message += fmt.Sprintf(" (%d trashed to-dos matched; the most recently deleted was restored, use --id to pick another)", matchCount)
+	}
+	return OperationResult{
+		Success:       true,
+		Message:       message,
+		AffectedCount: 1,
+		IDs:           ids,
+	}, nil
+}
+
+// restoreTodoByID moves the to-do with the given id back to the Inbox in Things.app
+func restoreTodoByID(ctx context.Context, id string) (OperationResult, error) {
+	jxaScript := todoByIDScript(id, "app.move(todo, {to: app.lists.byName('Inbox')});")
+	if dryRun {
+		return dryRunResult(jxaScript, fmt.Sprintf("restore the to-do with id \"%s\" to the Inbox", id)), nil
+	}
+	name, found, err := runTodoByIDScript(ctx, jxaScript)
+	if err != nil {
+		return OperationResult{}, err
+	}
+	if !found {
+		return todoIDNotFound(id), nil
+	}
+	return OperationResult{
+		Success:       true,
+		Message:       fmt.Sprintf("To-do \"%s\" restored to the Inbox!", name),
+		AffectedCount: 1,
+		IDs:           []string{id},
+	}, nil
+}
+
+// buildShowURL builds a things:///show URL that reveals the to-do with the given id
+func buildShowURL(id string) string {
+	return "things:///show?id=" + urlEncode(id)
+}
+
+// parseTodoIDLookup extracts the first match's id and the match count from a "SUCCESS: <count> <id>" script result
+func parseTodoIDLookup(outputStr string) (string, int, error) {
+	rest, found := strings.CutPrefix(outputStr, "SUCCESS:")
+	if !found {
+		return "", 0, fmt.Errorf("unexpected output: %s", outputStr)
+	}
+	countStr, id, found := strings.Cut(strings.TrimSpace(rest), " ")
+	count, err := strconv.Atoi(countStr)
+	if !found || err != nil || count < 1 || id == "" {
+		return "", 0, fmt.Errorf("unexpected output: %s", outputStr)
+	}
+	return id, count, nil
+}
+
+// openTodoInThings reveals a todo by name from a specific list in Things.app
+// If several to-dos share the name, the first is opened
+func openTodoInThings(ctx context.Context, listName, todoName string) (OperationResult, error) {
+	listName = normalizeListName(listName)
+	escapedListName := jxaEscape(listName)
+	escapedTodoName := jxaEscape(todoName)
+	jxaScript := fmt.Sprintf(`
+try {
+    var app = Application('Things3');
+    var list = app.lists.byName('%s');
+    var todos = list.toDos();
+    var matchCount = 0;
+    var id = '';
+
+    for (var i = 0; i < todos.length; i++) {
+        if (todos[i].name() === '%s') {
+            if (matchCount === 0) id = todos[i].id();
+            matchCount++;
+        }
+    }
+
+    if (matchCount > 0) {
+        'SUCCESS: ' + matchCount + ' ' + id;
+    } else {
+        'ERROR: To-do not found in list';
+    }
+} catch (e) {
+    'ERROR: List not found: ' + e.message;
+}
+`, escapedListName, escapedTodoName)
+
+	output, err := runJXA(ctx, jxaScript)
+	if err != nil {
+		return OperationResult{}, err
+	}
+
+	outputStr := strings.TrimSpace(string(output))
+	if strings.HasPrefix(outputStr, "ERROR:") {
+		failure := classifyJXAError(outputStr, listName, todoName)
+		if errors.Is(failure, ErrThingsNotRunning) {
+			return OperationResult{}, failure
+		}
+		return failedResult(failure), nil
+	}
+
+	id, count, err := parseTodoIDLookup(outputStr)
+	if err != nil {
+		return OperationResult{}, err
+	}
+
+	if _, err := executor.ExecuteContext(ctx, "open", buildShowURL(id)); err != nil {
+		return OperationResult{}, fmt.Errorf("error opening Things URL: %v", err)
+	}
+
+	message := fmt.Sprintf("Opened to-do \"%s\" from list \"%s\" in Things.app!", todoName, listName)
+	if count > 1 {
+		message += fmt.Sprintf(" (%d to-dos matched; opened the first)", count)
+	}
+	return OperationResult{
+		Success:       true,
+		Message:       message,
+		AffectedCount: 1,
+	}, nil
+}
+
+// logCompletedNow tells Things.app to move completed todos to the Logbook
+func logCompletedNow(ctx context.Context) error {
+	jxaScript := `
+try {
+    var app = Application('Things3');
+    app.logCompletedNow();
+    'SUCCESS';
+} catch (e) {
+    'ERROR: ' + e.message;
+}
+`
+	output, err := runJXA(ctx, jxaScript)
+	if err != nil {
+		return err
+	}
+
+	outputStr := strings.TrimSpace(string(output))
+	if strings.HasPrefix(outputStr, "ERROR:") {
+		return fmt.Errorf("%s", outputStr)
+	}
+
+	return nil
+}
+
+// calculateStartDate returns the start date based on the filter
+// weekStart sets the first day of the week used by "this week", and periods start at midnight in loc
+func calculateStartDate(filter string, weekStart time.Weekday, loc *time.Location) time.Time {
+	current := now().In(loc)
 	switch filter {
 	case "today":
-		return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+		return time.Date(current.Year(), current.Month(), current.Day(), 0, 0, 0, 0, current.Location())
 	case "this week":
-		// Go back to most recent Sunday at midnight
-		daysBack := int(now.Weekday()) // Sunday = 0, Monday = 1, etc.
-		sunday := now.AddDate(0, 0, -daysBack)
-		return time.Date(sunday.Year(), sunday.Month(), sunday.Day(), 0, 0, 0, 0, sunday.Location())
+		// Go back to the most recent week start at midnight
+		// For a Monday start this is (weekday+6)%7, since Sunday = 0, Monday = 1, etc.
+		daysBack := (int(current.Weekday()) - int(weekStart) + 7) % 7
+		start := current.AddDate(0, 0, -daysBack)
+		return time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, start.Location())
 	case "this month":
-		return time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+		return time.Date(current.Year(), current.Month(), 1, 0, 0, 0, 0, current.Location())
 	default:
 		return time.Time{} // Zero time
 	}
 }
 
-// parseDateFilter parses a date filter string and returns the start time and whether it represents a single day
-// Returns: (startTime, isSingleDay, error)
-// - For keywords like "today", "this week", "this month": returns (start of period, false, nil)
-// - For YYYY-MM-DD dates: returns (midnight of that day, true, nil)
-func parseDateFilter(filter string) (time.Time, bool, error) {
+// parseWeekStart converts a --week-start value into the weekday that begins the week
+func parseWeekStart(value string) (time.Weekday, error) {
+	switch strings.ToLower(value) {
+	case "sunday", "":
+		return time.Sunday, nil
+	case "monday":
+		return time.Monday, nil
+	default:
+		return time.Sunday, fmt.Errorf("ERROR: --week-start must be one of: sunday, monday")
+	}
+}
+
+// DateRange is the span of completion dates matched by a log date filter
+// Start is inclusive and End is exclusive; a zero End leaves the range open-ended
+type DateRange struct {
+	Start time.Time
+	End   time.Time
+}
+
+// Contains reports whether t falls within the range
+// The bounds already carry the timezone the range was parsed in, so t can be in any zone
+func (r DateRange) Contains(t time.Time) bool {
+	if t.Before(r.Start) {
+		return false
+	}
+	return r.End.IsZero() || t.Before(r.End)
+}
+
+// parseTimezone converts a --timezone value into a location, with the empty string meaning the local timezone
+func parseTimezone(value string) (*time.Location, error) {
+	if value == "" {
+		return time.Local, nil
+	}
+	loc, err := time.LoadLocation(value)
+	if err != nil {
+		return nil, fmt.Errorf("ERROR: --timezone must be an IANA timezone name, such as UTC or America/New_York; got \"%s\"", value)
+	}
+	return loc, nil
+}
+
+// parseDay parses a YYYY-MM-DD date as midnight in loc
+func parseDay(value string, loc *time.Location) (time.Time, error) {
+	t, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date format: %s", value)
+	}
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc), nil
+}
+
+// parseDateFilter parses a date filter string into the range of completion dates it covers
+// - For keywords like "today", "this week", "this month": returns an open-ended range from the start of the period
+// - For "yesterday", "last week", "last month": returns the bounded range ending at the start of the current period
+// - For YYYY-MM-DD dates: returns the range covering that single day
+// - For YYYY-MM-DD..YYYY-MM-DD ranges: returns the range covering both days and everything between
+// Days start at midnight in loc
+func parseDateFilter(filter string, weekStart time.Weekday, loc *time.Location) (DateRange, error) {
 	// Check if it's a keyword
-	if filter == "today" || filter == "this week" || filter == "this month" {
-		return calculateStartDate(filter), false, nil
+	switch filter {
+	case "today", "this week", "this month":
+		return DateRange{Start: calculateStartDate(filter, weekStart, loc)}, nil
+	case "yesterday":
+		today := calculateStartDate("today", weekStart, loc)
+		return DateRange{Start: today.AddDate(0, 0, -1), End: today}, nil
+	case "last week":
+		thisWeek := calculateStartDate("this week", weekStart, loc)
+		return DateRange{Start: thisWeek.AddDate(0, 0, -7), End: thisWeek}, nil
+	case "last month":
+		thisMonth := calculateStartDate("this month", weekStart, loc)
+		return DateRange{Start: thisMonth.AddDate(0, -1, 0), End: thisMonth}, nil
+	}
+
+	// Check if it's a range of YYYY-MM-DD dates
+	if startStr, endStr, found := strings.Cut(filter, ".."); found {
+		start, err := parseDay(startStr, loc)
+		if err != nil {
+			return DateRange{}, err
+		}
+		end, err := parseDay(endStr, loc)
+		if err != nil {
+			return DateRange{}, err
+		}
+		if end.Before(start) {
+			return DateRange{}, fmt.Errorf("ERROR: date range end %s is before start %s", endStr, startStr)
+		}
+		return DateRange{Start: start, End: end.AddDate(0, 0, 1)}, nil
 	}
 
 	// Try parsing as YYYY-MM-DD
-	t, err := time.Parse("2006-01-02", filter)
+	startOfDay, err := parseDay(filter, loc)
 	if err != nil {
-		return time.Time{}, false, fmt.Errorf("invalid date format: %s", filter)
+		return DateRange{}, err
 	}
-
-	// Set to midnight in local timezone
-	startOfDay := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.Local)
-	return startOfDay, true, nil
+	return DateRange{Start: startOfDay, End: startOfDay.AddDate(0, 0, 1)}, nil
 }
 
-// getCompletedTodos retrieves completed todos from the Logbook filtered by date
-func getCompletedTodos(dateFilter string) ([]Todo, error) {
-	// First, ensure all completed todos are moved to the Logbook
-	if err := logCompletedNow(); err != nil {
+// getCompletedTodos retrieves completed todos from the Logbook filtered by date, with days starting at midnight in loc
+// If flush is set, completed todos not yet in the Logbook are moved there first so they're included
+func getCompletedTodos(ctx context.Context, dateFilter string, weekStart time.Weekday, loc *time.Location, flush bool) ([]Todo, error) {
+	dateRange, err := parseDateFilter(dateFilter, weekStart, loc)
+	if err != nil {
 		return nil, err
 	}
 
-	startDate, isSingleDay, err := parseDateFilter(dateFilter)
+	if flush {
+		if err := logCompletedNow(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	startDateISO := dateRange.Start.Format(time.RFC3339)
+	todos, err := getTodosFromListWithFilter(ctx, "Logbook", startDateISO)
 	if err != nil {
 		return nil, err
 	}
 
-	startDateISO := startDate.Format(time.RFC3339)
-	todos, err := getTodosFromListWithFilter("Logbook", startDateISO)
+	// Open-ended ranges are already filtered by the script
+	if dateRange.End.IsZero() {
+		return todos, nil
+	}
+
+	// For bounded ranges, only include todos completed before the end of the range
+	var filtered []Todo
+	for _, todo := range todos {
+		if todo.CompletionDate != nil && dateRange.Contains(*todo.CompletionDate) {
+			filtered = append(filtered, todo)
+		}
+	}
+	return filtered, nil
+}
+
+// getCompletedTodosFiltered retrieves completed todos with optional area/project filters
+func getCompletedTodosFiltered(ctx context.Context, dateFilter, areaFilter, projectFilter string, weekStart time.Weekday, loc *time.Location, flush bool) ([]Todo, error) {
+	todos, err := getCompletedTodos(ctx, dateFilter, weekStart, loc, flush)
 	if err != nil {
 		return nil, err
 	}
 
-	// If filtering for a single day, only include todos completed within that specific day
-	if isSingleDay {
-		endOfDay := startDate.AddDate(0, 0, 1) // Midnight of next day in local time
-		var filtered []Todo
-		for _, todo := range todos {
-			if todo.CompletionDate != nil {
-				// Convert completion date to local timezone for comparison
-				completionLocal := todo.CompletionDate.In(time.Local)
-				// Include if completion is on or after startDate AND before endOfDay
-				if !completionLocal.Before(startDate) && completionLocal.Before(endOfDay) {
-					filtered = append(filtered, todo)
-				}
-			}
+	return filterTodosByAreaProject(todos, areaFilter, projectFilter), nil
+}
+
+// jxaFindFirstTodo is a JXA snippet setting todo to the first to-do in list whose name matches, or null
+const jxaFindFirstTodo = `
+    var todos = list.toDos();
+    var todo = null;
+    for (var i = 0; i < todos.length && !todo; i++) {
+        if (%s) todo = todos[i];
+    }`
+
+// getTodoTags returns the tag names of the first to-do named todoName in listName
+// Errors starting with "ERROR:" mean the list or the to-do doesn't exist
+func getTodoTags(ctx context.Context, listName, todoName string) ([]string, error) {
+	listName = normalizeListName(listName)
+	escapedListName := jxaEscape(listName)
+	escapedTodoName := jxaEscape(todoName)
+	jxaScript := fmt.Sprintf(`
+try {
+    var app = Application('Things3');
+    var list = app.lists.byName('%s');%s
+
+    if (todo) {
+        JSON.stringify(todo.tags().map(function(t) { return t.name(); }));
+    } else {
+        'ERROR: To-do not found in list';
+    }
+} catch (e) {
+    'ERROR: List not found: ' + e.message;
+}
+`, escapedListName, fmt.Sprintf(jxaFindFirstTodo, jxaNameMatch("todos[i].name()", escapedTodoName, false)))
+
+	output, err := runJXA(ctx, jxaScript)
+	if err != nil {
+		return nil, err
+	}
+
+	outputStr := strings.TrimSpace(string(output))
+	if strings.HasPrefix(outputStr, "ERROR:") {
+		return nil, classifyJXAError(outputStr, listName, todoName)
+	}
+
+	var tags []string
+	if err := json.Unmarshal([]byte(outputStr), &tags); err != nil {
+		return nil, fmt.Errorf("error parsing JSON: %v", err)
+	}
+	return tags, nil
+}
+
+// writeTodoTags replaces the tags of the first to-do named todoName in listName with tags
+func writeTodoTags(ctx context.Context, listName, todoName string, tags []string) (OperationResult, error) {
+	listName = normalizeListName(listName)
+	escapedListName := jxaEscape(listName)
+	escapedTodoName := jxaEscape(todoName)
+	plain, _ := splitCommaTags(tags)
+	escapedTags := make([]string, len(plain))
+	for i, tag := range plain {
+		escapedTags[i] = jxaEscape(tag)
+	}
+	jxaScript := fmt.Sprintf(`
+try {
+    var app = Application('Things3');
+    var list = app.lists.byName('%s');%s
+
+    if (todo) {
+        todo.tagNames = '%s';%s
+        'SUCCESS';
+    } else {
+        'ERROR: To-do not found in list';
+    }
+} catch (e) {
+    'ERROR: List not found: ' + e.message;
+}
+`, escapedListName, fmt.Sprintf(jxaFindFirstTodo, jxaNameMatch("todos[i].name()", escapedTodoName, false)), strings.Join(escapedTags, ", "), jxaAttachTags(tags))
+
+	if dryRun {
+		outcome := fmt.Sprintf("clear the tags of to-do \"%s\" in list \"%s\"", todoName, listName)
+		if len(tags) > 0 {
+			outcome = fmt.Sprintf("set the tags of to-do \"%s\" in list \"%s\" to %s", todoName, listName, quoteTags(tags))
 		}
-		return filtered, nil
+		return dryRunResult(jxaScript, outcome), nil
 	}
 
-	return todos, nil
+	output, err := runJXA(ctx, jxaScript)
+	if err != nil {
+		return OperationResult{}, err
+	}
+
+	outputStr := strings.TrimSpace(string(output))
+	if strings.HasPrefix(outputStr, "ERROR:") {
+		failure := classifyJXAError(outputStr, listName, todoName)
+		if errors.Is(failure, ErrThingsNotRunning) {
+			return OperationResult{}, failure
+		}
+		return failedResult(failure), nil
+	}
+
+	message := fmt.Sprintf("Tags of to-do \"%s\" in list \"%s\" cleared!", todoName, listName)
+	if len(tags) > 0 {
+		message = fmt.Sprintf("Tags of to-do \"%s\" in list \"%s\" set to %s!", todoName, listName, quoteTags(tags))
+	}
+	return OperationResult{
+		Success:       true,
+		Message:       message,
+		AffectedCount: 1,
+	}, nil
 }
 
-// getCompletedTodosFiltered retrieves completed todos with optional area/project filters
-func getCompletedTodosFiltered(dateFilter, areaFilter, projectFilter string) ([]Todo, error) {
-	todos, err := getCompletedTodos(dateFilter)
+// quoteTags lists tags for a message, quoting each so tags containing a comma stay readable
+func quoteTags(tags []string) string {
+	return "\"" + strings.Join(tags, "\", \"") + "\""
+}
+
+// unionTags returns existing followed by each added tag it doesn't already contain
+func unionTags(existing, added []string) []string {
+	union := append([]string{}, existing...)
+	for _, tag := range added {
+		if !slices.Contains(union, tag) {
+			union = append(union, tag)
+		}
+	}
+	return union
+}
+
+// setTodoTags sets the tags of the first to-do named todoName in listName
+// With replace, the to-do ends up with exactly tags; otherwise they're added to the tags it already has
+func setTodoTags(ctx context.Context, listName, todoName string, tags []string, replace bool) (OperationResult, error) {
+	listName = normalizeListName(listName)
+	if replace {
+		return writeTodoTags(ctx, listName, todoName, unionTags(nil, tags))
+	}
+
+	existing, err := getTodoTags(ctx, listName, todoName)
 	if err != nil {
-		return nil, err
+		if errors.Is(err, ErrListNotFound) || errors.Is(err, ErrTodoNotFound) {
+			return failedResult(err), nil
+		}
+		return OperationResult{}, err
 	}
+	return writeTodoTags(ctx, listName, todoName, unionTags(existing, tags))
+}
 
-	// If no filters, return all
-	if areaFilter == "" && projectFilter == "" {
-		return todos, nil
+// removeTodoTags removes tags from the first to-do named todoName in listName
+// Tags the to-do doesn't have are ignored, so removing only those succeeds without changing anything
+func removeTodoTags(ctx context.Context, listName, todoName string, tags []string) (OperationResult, error) {
+	listName = normalizeListName(listName)
+	existing, err := getTodoTags(ctx, listName, todoName)
+	if err != nil {
+		if errors.Is(err, ErrListNotFound) || errors.Is(err, ErrTodoNotFound) {
+			return failedResult(err), nil
+		}
+		return OperationResult{}, err
 	}
 
-	var filtered []Todo
-	for _, todo := range todos {
-		// Apply area filter if specified
-		if areaFilter != "" && todo.Area != areaFilter {
-			continue
+	var remaining, removed []string
+	for _, tag := range existing {
+		if slices.Contains(tags, tag) {
+			removed = append(removed, tag)
+		} else {
+			remaining = append(remaining, tag)
 		}
+	}
+	if len(removed) == 0 {
+		return OperationResult{
+			Success: true,
+			Message: fmt.Sprintf("To-do \"%s\" in list \"%s\" has none of those tags; nothing to remove", todoName, listName),
+		}, nil
+	}
 
-		// Apply project filter if specified
-		if projectFilter != "" && todo.Project != projectFilter {
-			continue
+	result, err := writeTodoTags(ctx, listName, todoName, remaining)
+	if err != nil || !result.Success || dryRun {
+		return result, err
+	}
+	result.Message = fmt.Sprintf("Removed %s from to-do \"%s\" in list \"%s\"!", quoteTags(removed), todoName, listName)
+	return result, nil
+}
+
+// TodoChanges lists the fields editTodo changes; nil fields are left as they are
+type TodoChanges struct {
+	Name  *string
+	Due   *time.Time // the zero time clears the due date
+	When  *string    // today, tomorrow, anytime, someday, or a YYYY-MM-DD date
+	Notes *string    // an empty string clears the notes
+	Tags  *[]string  // replaces the to-do's tags; an empty list clears them
+
+	Reminder *string // an HH:MM time to be reminded at on the day When schedules for
+}
+
+// whenLists are the --when values that put a to-do on the list of the same name
+var whenLists = map[string]string{
+	"today":   "Today",
+	"anytime": "Anytime",
+	"someday": "Someday",
+}
+
+// parseWhen checks a --when value, returning it lowercased if it's a keyword
+func parseWhen(value string) (string, error) {
+	keyword := strings.ToLower(value)
+	if _, ok := whenLists[keyword]; ok || keyword == "tomorrow" {
+		return keyword, nil
+	}
+	if _, err := parseDay(value, time.Local); err == nil {
+		return value, nil
+	}
+	return "", fmt.Errorf("ERROR: --when must be one of: today, tomorrow, anytime, someday, or a date in YYYY-MM-DD format")
+}
+
+// parseReminder parses an HH:MM reminder time
+func parseReminder(value string) (hour, minute int, err error) {
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, 0, fmt.Errorf("ERROR: --reminder must be a time in HH:MM format")
+	}
+	return t.Hour(), t.Minute(), nil
+}
+
+// checkReminder reports whether a reminder can go with the when value it's scheduled by
+// Reminders need a day, so they can't be set without when, or with anytime or someday
+func checkReminder(when, reminder *string) error {
+	if reminder == nil {
+		return nil
+	}
+	if when == nil {
+		return fmt.Errorf("ERROR: --reminder requires --when today, tomorrow, or a date")
+	}
+	if *when == "anytime" || *when == "someday" {
+		return fmt.Errorf("ERROR: --reminder can't be used with --when %s", *when)
+	}
+	return nil
+}
+
+// jxaLocalDate returns a JXA expression for t's day in the local timezone, at midnight unless t has a time of day
+func jxaLocalDate(t time.Time) string {
+	if t.Hour() != 0 || t.Minute() != 0 {
+		return fmt.Sprintf("new Date(%d, %d, %d, %d, %d)", t.Year(), int(t.Month())-1, t.Day(), t.Hour(), t.Minute())
+	}
+	return fmt.Sprintf("new Date(%d, %d, %d)", t.Year(), int(t.Month())-1, t.Day())
+}
+
+// jxaAssignments returns a JXA statement for each field set in c, acting on a variable named todo,
+// along with a label for each changed field
+// Nil fields produce no statement, while set-but-empty ones produce a statement that clears the field
+func (c TodoChanges) jxaAssignments() (assignments, changed []string, err error) {
+	if c.Name != nil {
+		assignments = append(assignments, fmt.Sprintf("todo.name = '%s';", jxaEscape(*c.Name)))
+		changed = append(changed, "name")
+	}
+	if c.Due != nil {
+		if c.Due.IsZero() {
+			assignments = append(assignments, "todo.dueDate = null;")
+		} else {
+			assignments = append(assignments, fmt.Sprintf("todo.dueDate = %s;", jxaLocalDate(*c.Due)))
+		}
+		changed = append(changed, "due date")
+	}
+	if c.Notes != nil {
+		assignments = append(assignments, fmt.Sprintf("todo.notes = '%s';", jxaEscape(*c.Notes)))
+		changed = append(changed, "notes")
+	}
+	if c.Tags != nil {
+		plain, withComma := splitCommaTags(*c.Tags)
+		escapedTags := make([]string, len(plain))
+		for i, tag := range plain {
+			escapedTags[i] = jxaEscape(tag)
+		}
+		assignments = append(assignments, fmt.Sprintf("todo.tagNames = '%s';", strings.Join(escapedTags, ", ")))
+		// As in jxaAttachTags, tags containing a comma are attached as tag objects
+		for _, tag := range withComma {
+			assignments = append(assignments, fmt.Sprintf("todo.tags.push(app.tags.byName('%s'));", jxaEscape(tag)))
+		}
+		changed = append(changed, "tags")
+	}
+	if err := checkReminder(c.When, c.Reminder); err != nil {
+		return nil, nil, err
+	}
+	if c.When != nil {
+		var schedule string
+		if c.Reminder != nil {
+			// Scripts can't set reminders, so the reminder's day and time go through the URL scheme's when
+			hour, minute, err := parseReminder(*c.Reminder)
+			if err != nil {
+				return nil, nil, err
+			}
+			day := now()
+			if *c.When == "tomorrow" {
+				day = day.AddDate(0, 0, 1)
+			} else if *c.When != "today" {
+				if day, err = parseDay(*c.When, time.Local); err != nil {
+					return nil, nil, err
+				}
+			}
+			if authToken == "" {
+				return nil, nil, errors.New(errNoAuthToken)
+			}
+			schedule = jxaWhenUpdate(fmt.Sprintf("%s@%02d:%02d", day.Format("2006-01-02"), hour, minute))
+		} else if list, ok := whenLists[*c.When]; ok {
+			// As with move --to, the to-do is scheduled for the list rather than moved there
+			schedule, _ = jxaScheduleOnList(list)
+			if *c.When != "today" && authToken == "" {
+				return nil, nil, errors.New(errNoAuthToken)
+			}
+		} else if *c.When == "tomorrow" {
+			schedule = scheduledLists["upcoming"]
+		} else {
+			day, err := parseDay(*c.When, time.Local)
+			if err != nil {
+				return nil, nil, err
+			}
+			schedule = "app.schedule(%s, {for: " + jxaLocalDate(day) + "});"
 		}
+		// Scheduling can move the to-do out of the list, so it goes after the other changes
+		assignments = append(assignments, fmt.Sprintf(schedule, "todo"))
+		changed = append(changed, "schedule")
+		if c.Reminder != nil {
+			changed = append(changed, "reminder")
+		}
+	}
+	return assignments, changed, nil
+}
 
-		filtered = append(filtered, todo)
+// editTodo applies changes to the first to-do named todoName in listName in a single script
+// Only the fields set in changes are touched, so the rest keep their current values
+func editTodo(ctx context.Context, listName, todoName string, changes TodoChanges) (OperationResult, error) {
+	listName = normalizeListName(listName)
+	assignments, changed, err := changes.jxaAssignments()
+	if err != nil {
+		return OperationResult{}, err
 	}
-	return filtered, nil
+	if len(assignments) == 0 {
+		return OperationResult{Success: false, Message: "ERROR: nothing to change"}, nil
+	}
+
+	escapedListName := jxaEscape(listName)
+	escapedTodoName := jxaEscape(todoName)
+	jxaScript := fmt.Sprintf(`
+try {
+    var app = Application('Things3');
+    var list = app.lists.byName('%s');%s
+
+    if (todo) {
+        %s
+        'SUCCESS';
+    } else {
+        'ERROR: To-do not found in list';
+    }
+} catch (e) {
+    'ERROR: List not found: ' + e.message;
+}
+`, escapedListName, fmt.Sprintf(jxaFindFirstTodo, jxaNameMatch("todos[i].name()", escapedTodoName, false)), strings.Join(assignments, "\n        "))
+
+	if dryRun {
+		return dryRunResult(jxaScript, fmt.Sprintf("change the %s of to-do \"%s\" in list \"%s\"", strings.Join(changed, ", "), todoName, listName)), nil
+	}
+
+	output, err := runJXA(ctx, jxaScript)
+	if err != nil {
+		return OperationResult{}, err
+	}
+
+	outputStr := strings.TrimSpace(string(output))
+	if strings.HasPrefix(outputStr, "ERROR:") {
+		failure := classifyJXAError(outputStr, listName, todoName)
+		if errors.Is(failure, ErrThingsNotRunning) {
+			return OperationResult{}, failure
+		}
+		return failedResult(failure), nil
+	}
+
+	return OperationResult{
+		Success:       true,
+		Message:       fmt.Sprintf("Changed the %s of to-do \"%s\" in list \"%s\"!", strings.Join(changed, ", "), todoName, listName),
+		AffectedCount: 1,
+	}, nil
 }
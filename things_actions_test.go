@@ -1,8 +1,14 @@
 package main
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 )
@@ -12,9 +18,11 @@ type MockExecutor struct {
 	outputs   [][]byte
 	errors    []error
 	callCount int
+	calls     [][]string
 }
 
 func (m *MockExecutor) Execute(name string, args ...string) ([]byte, error) {
+	m.calls = append(m.calls, append([]string{name}, args...))
 	if m.callCount >= len(m.outputs) {
 		// If we run out of mock outputs, return the last one
 		if len(m.outputs) > 0 {
@@ -31,6 +39,24 @@ func (m *MockExecutor) Execute(name string, args ...string) ([]byte, error) {
 	return output, err
 }
 
+// ExecuteContext fails with the context's error if it is already done, otherwise behaves like Execute
+func (m *MockExecutor) ExecuteContext(ctx context.Context, name string, args ...string) ([]byte, error) {
+	if err := ctx.Err(); err != nil {
+		m.calls = append(m.calls, append([]string{name}, args...))
+		return nil, err
+	}
+	return m.Execute(name, args...)
+}
+
+// lastScript returns the script passed to the most recent Execute call
+func (m *MockExecutor) lastScript() string {
+	if len(m.calls) == 0 {
+		return ""
+	}
+	call := m.calls[len(m.calls)-1]
+	return call[len(call)-1]
+}
+
 // Helper to set up mock executor with a single output and restore original after test
 func setupMockExecutor(output string, err error) func() {
 	return setupMockExecutorMulti([]string{output}, []error{err})
@@ -39,6 +65,7 @@ func setupMockExecutor(output string, err error) func() {
 // Helper to set up mock executor with multiple outputs and restore original after test
 func setupMockExecutorMulti(outputs []string, errors []error) func() {
 	originalExecutor := executor
+	originalThingsAvailable := thingsAvailable
 
 	byteOutputs := make([][]byte, len(outputs))
 	for i, output := range outputs {
@@ -49,8 +76,11 @@ func setupMockExecutorMulti(outputs []string, errors []error) func() {
 		outputs: byteOutputs,
 		errors:  errors,
 	}
+	// Skip the pre-flight check so each mock output answers the script under test
+	thingsAvailable = true
 	return func() {
 		executor = originalExecutor
+		thingsAvailable = originalThingsAvailable
 	}
 }
 
@@ -93,7 +123,7 @@ func TestGetTodosFromList_Success(t *testing.T) {
 			cleanup := setupMockExecutor(tt.output, nil)
 			defer cleanup()
 
-			result, err := getTodosFromList(tt.listName)
+			result, err := getTodosFromList(context.Background(), tt.listName)
 			if err != nil {
 				t.Errorf("unexpected error: %v", err)
 			}
@@ -141,7 +171,7 @@ func TestGetTodosFromList_Errors(t *testing.T) {
 			cleanup := setupMockExecutor(tt.output, tt.execError)
 			defer cleanup()
 
-			result, err := getTodosFromList(tt.listName)
+			result, err := getTodosFromList(context.Background(), tt.listName)
 
 			if tt.expectErr {
 				if err == nil {
@@ -159,6 +189,91 @@ func TestGetTodosFromList_Errors(t *testing.T) {
 	}
 }
 
+func TestNormalizeListName(t *testing.T) {
+	tests := map[string]string{
+		"Work":           "Work",
+		" Work ":         "Work",
+		"\tWork\n":       "Work",
+		"  Home  Office": "Home  Office",
+		"   ":            "",
+	}
+	for input, expected := range tests {
+		if got := normalizeListName(input); got != expected {
+			t.Errorf("normalizeListName(%q) = %q, want %q", input, got, expected)
+		}
+	}
+}
+
+func TestListNamesAreTrimmed(t *testing.T) {
+	actions := []struct {
+		name       string
+		output     string
+		expectCall string
+		run        func(listName string) (string, error)
+	}{
+		{
+			name:   "show",
+			output: `[]`,
+			run: func(listName string) (string, error) {
+				_, err := getTodosFromList(context.Background(), listName)
+				return "", err
+			},
+		},
+		{
+			name:   "add",
+			output: `SUCCESS: 1 ["T1"]`,
+			run: func(listName string) (string, error) {
+				result, err := addTodoToList(context.Background(), listName, "Task", nil, nil)
+				return result.Message, err
+			},
+		},
+		{
+			name:   "complete",
+			output: `SUCCESS: 1 ["T1"]`,
+			run: func(listName string) (string, error) {
+				result, err := completeTodoInList(context.Background(), listName, "Task", MatchOptions{})
+				return result.Message, err
+			},
+		},
+		{
+			name:       "move to a smart list",
+			output:     `SUCCESS: 1 ["T1"]`,
+			expectCall: "app.schedule(",
+			run: func(listName string) (string, error) {
+				result, err := moveTodoBetweenLists(context.Background(), listName, " today ", "Task", MatchOptions{})
+				return result.Message, err
+			},
+		},
+	}
+
+	for _, tt := range actions {
+		t.Run(tt.name, func(t *testing.T) {
+			cleanup := setupMockExecutor(tt.output, nil)
+			defer cleanup()
+
+			expectedMessage, err := tt.run("Work")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			expectedScript := executor.(*MockExecutor).lastScript()
+
+			message, err := tt.run(" Work ")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if message != expectedMessage {
+				t.Errorf("expected message %q, got %q", expectedMessage, message)
+			}
+			if script := executor.(*MockExecutor).lastScript(); script != expectedScript {
+				t.Errorf("expected \" Work \" to run the same script as \"Work\", got:\n%s", script)
+			}
+			if !strings.Contains(expectedScript, tt.expectCall) {
+				t.Errorf("expected %q in the script, got:\n%s", tt.expectCall, expectedScript)
+			}
+		})
+	}
+}
+
 func TestAddTodoToList_Success(t *testing.T) {
 	tests := []struct {
 		name            string
@@ -191,7 +306,7 @@ func TestAddTodoToList_Success(t *testing.T) {
 			cleanup := setupMockExecutor(tt.output, nil)
 			defer cleanup()
 
-			result, err := addTodoToList(tt.listName, tt.todoName, "")
+			result, err := addTodoToList(context.Background(), tt.listName, tt.todoName, nil, nil)
 			if err != nil {
 				t.Errorf("unexpected error: %v", err)
 			}
@@ -207,6 +322,308 @@ func TestAddTodoToList_Success(t *testing.T) {
 	}
 }
 
+func TestAddTodosToList(t *testing.T) {
+	todos := []NewTodo{
+		{Name: "First"},
+		{Name: "Second", Tags: []string{"Home"}},
+		{Name: "Third", Notes: "Details"},
+	}
+
+	tests := []struct {
+		name            string
+		output          string
+		execErr         error
+		expectErr       bool
+		expectSuccesses []bool
+		expectMessages  []string
+	}{
+		{
+			name:            "all succeed",
+			output:          `[{"success":true},{"success":true},{"success":true}]`,
+			expectSuccesses: []bool{true, true, true},
+			expectMessages: []string{
+				`To-do "First" added successfully to list "Work"!`,
+				`To-do "Second" added successfully to list "Work"!`,
+				`To-do "Third" added successfully to list "Work"!`,
+			},
+		},
+		{
+			name:            "one item fails",
+			output:          `[{"success":true},{"success":false,"message":"ERROR: Can't make tag"},{"success":true}]`,
+			expectSuccesses: []bool{true, false, true},
+			expectMessages: []string{
+				`To-do "First" added successfully to list "Work"!`,
+				`ERROR: Can't make tag (to-do "Second")`,
+				`To-do "Third" added successfully to list "Work"!`,
+			},
+		},
+		{
+			name:      "list not found",
+			output:    "ERROR: Can't get object.",
+			expectErr: true,
+		},
+		{
+			name:      "result count mismatch",
+			output:    `[{"success":true}]`,
+			expectErr: true,
+		},
+		{
+			name:      "exec error",
+			execErr:   errors.New("osascript failed"),
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cleanup := setupMockExecutor(tt.output, tt.execErr)
+			defer cleanup()
+
+			results, err := addTodosToList(context.Background(), "Work", todos)
+			if tt.expectErr {
+				if err == nil {
+					t.Error("expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if len(results) != len(tt.expectSuccesses) {
+				t.Fatalf("expected %d results, got %d", len(tt.expectSuccesses), len(results))
+			}
+			for i, result := range results {
+				if result.Success != tt.expectSuccesses[i] {
+					t.Errorf("result %d: expected success %v, got %v", i, tt.expectSuccesses[i], result.Success)
+				}
+				if result.Message != tt.expectMessages[i] {
+					t.Errorf("result %d: expected message %q, got %q", i, tt.expectMessages[i], result.Message)
+				}
+			}
+		})
+	}
+}
+
+func TestAddTodosToList_SingleInvocation(t *testing.T) {
+	cleanup := setupMockExecutor(`[{"success":true},{"success":true}]`, nil)
+	defer cleanup()
+
+	_, err := addTodosToList(context.Background(), "Work", []NewTodo{{Name: "It's done"}, {Name: "Second"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	mock := executor.(*MockExecutor)
+	if len(mock.calls) != 1 {
+		t.Errorf("expected 1 osascript call, got %d", len(mock.calls))
+	}
+	if script := mock.lastScript(); !strings.Contains(script, `[{"name":"It's done"},{"name":"Second"}]`) {
+		t.Errorf("expected to-dos embedded as JSON, got script:\n%s", script)
+	}
+}
+
+func TestBuildAddURL(t *testing.T) {
+	tests := []struct {
+		name     string
+		todoName string
+		list     string
+		tags     []string
+		notes    string
+		expected string
+	}{
+		{
+			name:     "spaces are percent-encoded",
+			todoName: "Buy milk today",
+			list:     "Groceries",
+			expected: "things:///add?title=Buy%20milk%20today&list=Groceries",
+		},
+		{
+			name:     "ampersands and equals signs are escaped",
+			todoName: "Tom & Jerry = fun",
+			list:     "Shows & Films",
+			expected: "things:///add?title=Tom%20%26%20Jerry%20%3D%20fun&list=Shows%20%26%20Films",
+		},
+		{
+			name:     "unicode is UTF-8 encoded",
+			todoName: "Café ☕",
+			list:     "Work",
+			expected: "things:///add?title=Caf%C3%A9%20%E2%98%95&list=Work",
+		},
+		{
+			name:     "tags and notes",
+			todoName: "Review PR",
+			list:     "Work",
+			tags:     []string{"urgent", "code-review"},
+			notes:    "Line one\nLine two",
+			expected: "things:///add?title=Review%20PR&notes=Line%20one%0ALine%20two&list=Work&tags=urgent%2Ccode-review",
+		},
+		{
+			name:     "inbox needs no list",
+			todoName: "Task",
+			list:     "inbox",
+			expected: "things:///add?title=Task",
+		},
+		{
+			name:     "built-in lists use when",
+			todoName: "Task",
+			list:     "Today",
+			expected: "things:///add?title=Task&when=today",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := buildAddURL(tt.todoName, tt.list, tt.tags, tt.notes)
+			if result != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestAddTodoViaURL(t *testing.T) {
+	cleanup := setupMockExecutor("", nil)
+	defer cleanup()
+
+	result, err := addTodoViaURL(context.Background(), "Work", "Review PR", []string{"urgent"}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !result.Success {
+		t.Errorf("expected success, got %q", result.Message)
+	}
+
+	calls := executor.(*MockExecutor).calls
+	if len(calls) != 1 {
+		t.Fatalf("expected 1 call, got %d", len(calls))
+	}
+	expected := []string{"open", "things:///add?title=Review%20PR&list=Work&tags=urgent"}
+	if strings.Join(calls[0], " ") != strings.Join(expected, " ") {
+		t.Errorf("expected call %v, got %v", expected, calls[0])
+	}
+}
+
+func TestAddTodoViaURL_CommaTag(t *testing.T) {
+	cleanup := setupMockExecutor("", nil)
+	defer cleanup()
+
+	result, err := addTodoViaURL(context.Background(), "Work", "Task", []string{"urgent", "Home, Garden"}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := `ERROR: tag "Home, Garden" contains a comma, which the Things URL scheme can't send; add the to-do without --via url`
+	if result.Success || result.Message != expected {
+		t.Errorf("expected failure %q, got (%v, %q)", expected, result.Success, result.Message)
+	}
+	if calls := executor.(*MockExecutor).calls; len(calls) != 0 {
+		t.Errorf("expected no URL to be opened, got %v", calls)
+	}
+}
+
+func TestAddTodoViaURL_Error(t *testing.T) {
+	cleanup := setupMockExecutor("", errors.New("open failed"))
+	defer cleanup()
+
+	if _, err := addTodoViaURL(context.Background(), "Work", "Task", nil, nil); err == nil {
+		t.Error("expected error but got none")
+	}
+}
+
+func TestAddTodoToProject(t *testing.T) {
+	tests := []struct {
+		name            string
+		output          string
+		expectedSuccess bool
+		expectedMessage string
+	}{
+		{
+			name:            "project exists",
+			output:          "SUCCESS",
+			expectedSuccess: true,
+			expectedMessage: `To-do added successfully to project "Redesign"!`,
+		},
+		{
+			name:            "project not found",
+			output:          "ERROR: Project not found",
+			expectedSuccess: false,
+			expectedMessage: `ERROR: Project "Redesign" not found`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cleanup := setupMockExecutor(tt.output, nil)
+			defer cleanup()
+
+			result, err := addTodoToProject(context.Background(), "Redesign", "", "Sketch logo", []string{"Design"}, nil)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result.Success != tt.expectedSuccess {
+				t.Errorf("expected success %v, got %v", tt.expectedSuccess, result.Success)
+			}
+			if result.Message != tt.expectedMessage {
+				t.Errorf("expected message %q, got %q", tt.expectedMessage, result.Message)
+			}
+
+			script := executor.(*MockExecutor).lastScript()
+			for _, expected := range []string{"app.projects.byName('Redesign')", "project.toDos.push(todo);", "name: 'Sketch logo'", "tagNames: 'Design'"} {
+				if !strings.Contains(script, expected) {
+					t.Errorf("expected script to contain %q, got:\n%s", expected, script)
+				}
+			}
+		})
+	}
+}
+
+func TestAddTodoToProject_Heading(t *testing.T) {
+	tests := []struct {
+		name            string
+		output          string
+		expectedSuccess bool
+		expectedMessage string
+	}{
+		{
+			name:            "heading exists",
+			output:          "SUCCESS",
+			expectedSuccess: true,
+			expectedMessage: `To-do added successfully to heading "Drafts" in project "Redesign"!`,
+		},
+		{
+			name:            "heading not found",
+			output:          "ERROR: Heading not found",
+			expectedSuccess: false,
+			expectedMessage: `ERROR: Heading "Drafts" not found in project "Redesign"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cleanup := setupMockExecutor(tt.output, nil)
+			defer cleanup()
+
+			result, err := addTodoToProject(context.Background(), "Redesign", "Drafts", "Sketch logo", nil, nil)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result.Success != tt.expectedSuccess {
+				t.Errorf("expected success %v, got %v", tt.expectedSuccess, result.Success)
+			}
+			if result.Message != tt.expectedMessage {
+				t.Errorf("expected message %q, got %q", tt.expectedMessage, result.Message)
+			}
+
+			script := executor.(*MockExecutor).lastScript()
+			for _, expected := range []string{"project.headings.byName('Drafts')", "throw new Error('Heading not found');", "heading.toDos.push(todo);"} {
+				if !strings.Contains(script, expected) {
+					t.Errorf("expected script to contain %q, got:\n%s", expected, script)
+				}
+			}
+		})
+	}
+}
+
 func TestAddTodoToList_Errors(t *testing.T) {
 	tests := []struct {
 		name            string
@@ -238,7 +655,7 @@ func TestAddTodoToList_Errors(t *testing.T) {
 			cleanup := setupMockExecutor(tt.output, tt.execError)
 			defer cleanup()
 
-			result, err := addTodoToList(tt.listName, tt.todoName, "")
+			result, err := addTodoToList(context.Background(), tt.listName, tt.todoName, nil, nil)
 
 			if tt.expectErr {
 				if err == nil {
@@ -264,6 +681,7 @@ func TestDeleteTodoFromList_Success(t *testing.T) {
 		output          string
 		expectedSuccess bool
 		expectedMessage string
+		expectedIDs     []string
 	}{
 		{
 			name:            "delete existing todo from list",
@@ -273,6 +691,15 @@ func TestDeleteTodoFromList_Success(t *testing.T) {
 			expectedSuccess: true,
 			expectedMessage: `To-do "Buy groceries" deleted successfully from list "Inbox"!`,
 		},
+		{
+			name:            "reports the deleted id",
+			listName:        "Inbox",
+			todoName:        "Buy groceries",
+			output:          `SUCCESS: 1 ["ABC123"]`,
+			expectedSuccess: true,
+			expectedMessage: `To-do "Buy groceries" deleted successfully from list "Inbox"!`,
+			expectedIDs:     []string{"ABC123"},
+		},
 	}
 
 	for _, tt := range tests {
@@ -280,7 +707,7 @@ func TestDeleteTodoFromList_Success(t *testing.T) {
 			cleanup := setupMockExecutor(tt.output, nil)
 			defer cleanup()
 
-			result, err := deleteTodoFromList(tt.listName, tt.todoName)
+			result, err := deleteTodoFromList(context.Background(), tt.listName, tt.todoName, MatchOptions{})
 			if err != nil {
 				t.Errorf("unexpected error: %v", err)
 			}
@@ -292,6 +719,14 @@ func TestDeleteTodoFromList_Success(t *testing.T) {
 			if result.Message != tt.expectedMessage {
 				t.Errorf("expected message %q, got %q", tt.expectedMessage, result.Message)
 			}
+
+			if result.AffectedCount != 1 {
+				t.Errorf("expected 1 affected to-do, got %d", result.AffectedCount)
+			}
+
+			if !slices.Equal(result.IDs, tt.expectedIDs) {
+				t.Errorf("expected ids %q, got %q", tt.expectedIDs, result.IDs)
+			}
 		})
 	}
 }
@@ -337,7 +772,7 @@ func TestDeleteTodoFromList_Errors(t *testing.T) {
 			cleanup := setupMockExecutor(tt.output, tt.execError)
 			defer cleanup()
 
-			result, err := deleteTodoFromList(tt.listName, tt.todoName)
+			result, err := deleteTodoFromList(context.Background(), tt.listName, tt.todoName, MatchOptions{})
 
 			if tt.expectErr {
 				if err == nil {
@@ -402,7 +837,7 @@ func TestMoveTodoBetweenLists_Success(t *testing.T) {
 			cleanup := setupMockExecutor(tt.output, nil)
 			defer cleanup()
 
-			result, err := moveTodoBetweenLists(tt.fromList, tt.toList, tt.todoName)
+			result, err := moveTodoBetweenLists(context.Background(), tt.fromList, tt.toList, tt.todoName, MatchOptions{})
 			if err != nil {
 				t.Errorf("unexpected error: %v", err)
 			}
@@ -418,32 +853,199 @@ func TestMoveTodoBetweenLists_Success(t *testing.T) {
 	}
 }
 
-func TestMoveTodoBetweenLists_Errors(t *testing.T) {
+func TestMoveTodoBetweenLists_ScheduledLists(t *testing.T) {
 	tests := []struct {
-		name            string
-		fromList        string
-		toList          string
-		todoName        string
-		output          string
-		execError       error
-		expectErr       bool
-		expectedSuccess bool
-		expectedMessage string
+		toList       string
+		expectScript string
+		expectMove   bool
 	}{
-		{
-			name:      "exec fails",
-			fromList:  "Inbox",
-			toList:    "Work",
-			todoName:  "Test",
-			execError: errors.New("command failed"),
-			expectErr: true,
-		},
-		{
-			name:            "source list not found",
-			fromList:        "NonExistent",
-			toList:          "Work",
-			todoName:        "Test Todo",
-			output:          "ERROR: can't get object",
+		{"Today", "app.schedule(todos[i], {for: new Date()});", false},
+		{"today", "app.schedule(todos[i], {for: new Date()});", false},
+		{"Upcoming", "when.setDate(when.getDate() + 1); app.schedule(todos[i], {for: when});", false},
+		// Scripts can't put a to-do in Someday or Anytime, so they go through the URL scheme's update
+		{"Someday", "currentApp.openLocation('things:///update?auth-token=' + encodeURIComponent('secret') + '&id=' + encodeURIComponent(todos[i].id()) + '&when=' + encodeURIComponent('someday'));", false},
+		{"anytime", "encodeURIComponent(todos[i].id()) + '&when=' + encodeURIComponent('anytime'));", false},
+		{"Work", "app.move(todos[i], {to: destination});", true},
+	}
+
+	authToken = "secret"
+	defer func() { authToken = "" }()
+
+	for _, tt := range tests {
+		t.Run(tt.toList, func(t *testing.T) {
+			cleanup := setupMockExecutor(`SUCCESS: 1 ["ABC123"]`, nil)
+			defer cleanup()
+
+			result, err := moveTodoBetweenLists(context.Background(), "Inbox", tt.toList, "Buy milk", MatchOptions{})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !result.Success {
+				t.Fatalf("expected success, got %q", result.Message)
+			}
+
+			script := executor.(*MockExecutor).lastScript()
+			if !strings.Contains(script, tt.expectScript) {
+				t.Errorf("expected script to contain %q", tt.expectScript)
+			}
+			if strings.Contains(script, "app.move(") != tt.expectMove {
+				t.Errorf("expected app.move in script: %v", tt.expectMove)
+			}
+		})
+	}
+}
+
+func TestMoveTodoBetweenLists_NoAuthToken(t *testing.T) {
+	cleanup := setupMockExecutor(`SUCCESS: 1 ["ABC123"]`, nil)
+	defer cleanup()
+
+	result, err := moveTodoBetweenLists(context.Background(), "Inbox", "Someday", "Buy milk", MatchOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Success || result.Message != errNoAuthToken {
+		t.Errorf("expected the auth token error, got (%v, %q)", result.Success, result.Message)
+	}
+	if calls := executor.(*MockExecutor).calls; len(calls) != 0 {
+		t.Errorf("expected no script to run, got %d", len(calls))
+	}
+}
+
+func TestJxaWhenUpdate(t *testing.T) {
+	authToken = "a%b'c"
+	defer func() { authToken = "" }()
+
+	statement := fmt.Sprintf(jxaWhenUpdate("2026-10-20@09:30"), "todo")
+	expected := "currentApp.openLocation('things:///update?auth-token=' + encodeURIComponent('a%b\\'c') + '&id=' + encodeURIComponent(todo.id()) + '&when=' + encodeURIComponent('2026-10-20@09:30'));"
+	if !strings.HasSuffix(statement, expected) {
+		t.Errorf("expected statement ending in %q, got %q", expected, statement)
+	}
+}
+
+func TestMoveTodoBetweenLists_FromSmartList(t *testing.T) {
+	tests := []struct {
+		fromList     string
+		expectLookup string
+	}{
+		{"Today", "var fromList = app.lists.byId('TMTodayListSource');"},
+		{"someday", "var fromList = app.lists.byId('TMSomedayListSource');"},
+		{"Upcoming", "var fromList = app.lists.byId('TMCalendarListSource');"},
+		{"Inbox", "var fromList = app.lists.byName('Inbox');"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.fromList, func(t *testing.T) {
+			cleanup := setupMockExecutor(`SUCCESS: 1 ["ABC123"]`, nil)
+			defer cleanup()
+
+			result, err := moveTodoBetweenLists(context.Background(), tt.fromList, "Work", "Buy milk", MatchOptions{})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if expected := fmt.Sprintf(`To-do "Buy milk" moved successfully from list "%s" to list "Work"!`, tt.fromList); result.Message != expected {
+				t.Errorf("expected message %q, got %q", expected, result.Message)
+			}
+			if script := executor.(*MockExecutor).lastScript(); !strings.Contains(script, tt.expectLookup) {
+				t.Errorf("expected script to contain %q", tt.expectLookup)
+			}
+		})
+	}
+}
+
+func TestMoveTodosByTag(t *testing.T) {
+	tests := []struct {
+		name            string
+		output          string
+		expectedSuccess bool
+		expectedMessage string
+		expectedIDs     []string
+	}{
+		{
+			name:            "tagged to-dos moved, untagged left",
+			output:          `SUCCESS: 2 ["A1","A3"]`,
+			expectedSuccess: true,
+			expectedMessage: `Moved 2 to-dos tagged "review" from list "Inbox" to list "Later"!`,
+			expectedIDs:     []string{"A1", "A3"},
+		},
+		{
+			name:            "no tagged to-dos",
+			output:          "SUCCESS: 0 []",
+			expectedMessage: `ERROR: No to-dos tagged "review" found in list "Inbox"`,
+		},
+		{
+			name:            "source list not found",
+			output:          "ERROR: List not found: Can't get object.",
+			expectedMessage: `ERROR: List "Inbox" not found`,
+		},
+		{
+			name:            "destination list not found",
+			output:          "ERROR: Destination not found",
+			expectedMessage: `ERROR: List "Later" not found`,
+		},
+		{
+			name:            "other failure",
+			output:          "ERROR: Can't move to-do.",
+			expectedMessage: "ERROR: Can't move to-do.",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cleanup := setupMockExecutor(tt.output, nil)
+			defer cleanup()
+
+			result, err := moveTodosByTag(context.Background(), "Inbox", "Later", "review")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result.Success != tt.expectedSuccess || result.Message != tt.expectedMessage {
+				t.Errorf("expected (%v, %q), got (%v, %q)", tt.expectedSuccess, tt.expectedMessage, result.Success, result.Message)
+			}
+			if !slices.Equal(result.IDs, tt.expectedIDs) || result.AffectedCount != len(tt.expectedIDs) {
+				t.Errorf("expected ids %v, got %v (count %d)", tt.expectedIDs, result.IDs, result.AffectedCount)
+			}
+
+			script := executor.(*MockExecutor).lastScript()
+			for _, want := range []string{
+				"var fromList = app.lists.byName('Inbox');",
+				"todos[i].tags().some(function(t) { return t.name() === 'review'; })",
+				"app.move(todos[i], {to: destination});",
+				"throw new Error('Destination not found');",
+			} {
+				if !strings.Contains(script, want) {
+					t.Errorf("expected script to contain %q", want)
+				}
+			}
+		})
+	}
+}
+
+func TestMoveTodoBetweenLists_Errors(t *testing.T) {
+	tests := []struct {
+		name            string
+		fromList        string
+		toList          string
+		todoName        string
+		output          string
+		execError       error
+		expectErr       bool
+		expectedSuccess bool
+		expectedMessage string
+	}{
+		{
+			name:      "exec fails",
+			fromList:  "Inbox",
+			toList:    "Work",
+			todoName:  "Test",
+			execError: errors.New("command failed"),
+			expectErr: true,
+		},
+		{
+			name:            "source list not found",
+			fromList:        "NonExistent",
+			toList:          "Work",
+			todoName:        "Test Todo",
+			output:          "ERROR: can't get object",
 			expectedSuccess: false,
 			expectedMessage: "ERROR: can't get object",
 		},
@@ -472,7 +1074,7 @@ func TestMoveTodoBetweenLists_Errors(t *testing.T) {
 			cleanup := setupMockExecutor(tt.output, tt.execError)
 			defer cleanup()
 
-			result, err := moveTodoBetweenLists(tt.fromList, tt.toList, tt.todoName)
+			result, err := moveTodoBetweenLists(context.Background(), tt.fromList, tt.toList, tt.todoName, MatchOptions{})
 
 			if tt.expectErr {
 				if err == nil {
@@ -493,12 +1095,72 @@ func TestMoveTodoBetweenLists_Errors(t *testing.T) {
 	}
 }
 
+func TestMoveTodoToProject(t *testing.T) {
+	tests := []struct {
+		name            string
+		output          string
+		opts            MatchOptions
+		expectedSuccess bool
+		expectedMessage string
+	}{
+		{
+			name:            "move into project",
+			output:          "SUCCESS: 1",
+			expectedSuccess: true,
+			expectedMessage: `To-do "Draft outline" moved successfully from list "Inbox" to project "Redesign"!`,
+		},
+		{
+			name:            "move all matches into project",
+			output:          "SUCCESS: 2",
+			opts:            MatchOptions{All: true},
+			expectedSuccess: true,
+			expectedMessage: `Moved 2 to-dos named "Draft outline" from list "Inbox" to project "Redesign"!`,
+		},
+		{
+			name:            "project not found",
+			output:          "ERROR: Destination not found",
+			expectedMessage: `ERROR: Project "Redesign" not found`,
+		},
+		{
+			name:            "to-do not found",
+			output:          "ERROR: To-do not found",
+			expectedMessage: `ERROR: To-do "Draft outline" not found in list "Inbox"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cleanup := setupMockExecutor(tt.output, nil)
+			defer cleanup()
+
+			result, err := moveTodoToProject(context.Background(), "Inbox", "Redesign", "Draft outline", tt.opts)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result.Success != tt.expectedSuccess {
+				t.Errorf("expected success %v, got %v", tt.expectedSuccess, result.Success)
+			}
+			if result.Message != tt.expectedMessage {
+				t.Errorf("expected message %q, got %q", tt.expectedMessage, result.Message)
+			}
+
+			script := executor.(*MockExecutor).lastScript()
+			if !strings.Contains(script, "app.projects.byName('Redesign')") {
+				t.Error("expected script to look up the project")
+			}
+			if !strings.Contains(script, "todos[i].project = destination;") {
+				t.Error("expected script to set the to-do's project")
+			}
+		})
+	}
+}
+
 func TestAddTodoToList_WithTags(t *testing.T) {
 	tests := []struct {
 		name            string
 		listName        string
 		todoName        string
-		tags            string
+		tags            []string
 		output          string
 		expectedSuccess bool
 		expectedMessage string
@@ -507,7 +1169,7 @@ func TestAddTodoToList_WithTags(t *testing.T) {
 			name:            "add todo with single tag",
 			listName:        "Work",
 			todoName:        "New Task",
-			tags:            "Important",
+			tags:            []string{"Important"},
 			output:          `To-do added successfully to list "Work"!`,
 			expectedSuccess: true,
 			expectedMessage: `To-do added successfully to list "Work"!`,
@@ -516,7 +1178,7 @@ func TestAddTodoToList_WithTags(t *testing.T) {
 			name:            "add todo with multiple tags",
 			listName:        "Work",
 			todoName:        "New Task",
-			tags:            "Important, Urgent, Home",
+			tags:            []string{"Important", "Urgent", "Home"},
 			output:          `To-do added successfully to list "Work"!`,
 			expectedSuccess: true,
 			expectedMessage: `To-do added successfully to list "Work"!`,
@@ -525,7 +1187,7 @@ func TestAddTodoToList_WithTags(t *testing.T) {
 			name:            "add todo with tags containing quotes",
 			listName:        "Work",
 			todoName:        "New Task",
-			tags:            "Mom's stuff, Dad's work",
+			tags:            []string{"Mom's stuff", "Dad's work"},
 			output:          `To-do added successfully to list "Work"!`,
 			expectedSuccess: true,
 			expectedMessage: `To-do added successfully to list "Work"!`,
@@ -534,7 +1196,7 @@ func TestAddTodoToList_WithTags(t *testing.T) {
 			name:            "add todo with empty tags",
 			listName:        "inbox",
 			todoName:        "Quick note",
-			tags:            "",
+			tags:            nil,
 			output:          `To-do added successfully to list "inbox"!`,
 			expectedSuccess: true,
 			expectedMessage: `To-do added successfully to list "inbox"!`,
@@ -546,7 +1208,7 @@ func TestAddTodoToList_WithTags(t *testing.T) {
 			cleanup := setupMockExecutor(tt.output, nil)
 			defer cleanup()
 
-			result, err := addTodoToList(tt.listName, tt.todoName, tt.tags)
+			result, err := addTodoToList(context.Background(), tt.listName, tt.todoName, tt.tags, nil)
 			if err != nil {
 				t.Errorf("unexpected error: %v", err)
 			}
@@ -562,6 +1224,56 @@ func TestAddTodoToList_WithTags(t *testing.T) {
 	}
 }
 
+func TestAddTodoToList_WithChecklist(t *testing.T) {
+	tests := []struct {
+		name               string
+		tags               []string
+		checklist          []string
+		expectedProperties string
+	}{
+		{
+			name:               "single item",
+			checklist:          []string{"Eggs"},
+			expectedProperties: `{name: 'Groceries', checklistItems: ['Eggs']}`,
+		},
+		{
+			name:               "multiple items with tags",
+			tags:               []string{"Home"},
+			checklist:          []string{"Eggs", "Milk", "Bread"},
+			expectedProperties: `{name: 'Groceries', tagNames: 'Home', checklistItems: ['Eggs', 'Milk', 'Bread']}`,
+		},
+		{
+			name:               "items containing quotes",
+			checklist:          []string{"Mom's list", `The "good" bread`},
+			expectedProperties: `{name: 'Groceries', checklistItems: ['Mom\'s list', 'The "good" bread']}`,
+		},
+		{
+			name:               "no items",
+			expectedProperties: `{name: 'Groceries'}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cleanup := setupMockExecutor("SUCCESS", nil)
+			defer cleanup()
+
+			result, err := addTodoToList(context.Background(), "Inbox", "Groceries", tt.tags, tt.checklist)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !result.Success {
+				t.Errorf("expected success, got %q", result.Message)
+			}
+
+			script := executor.(*MockExecutor).lastScript()
+			if !strings.Contains(script, "app.ToDo("+tt.expectedProperties+")") {
+				t.Errorf("expected properties %s in script:\n%s", tt.expectedProperties, script)
+			}
+		})
+	}
+}
+
 func TestRenameTodoInList_Success(t *testing.T) {
 	tests := []struct {
 		name            string
@@ -571,6 +1283,7 @@ func TestRenameTodoInList_Success(t *testing.T) {
 		output          string
 		expectedSuccess bool
 		expectedMessage string
+		expectedIDs     []string
 	}{
 		{
 			name:            "rename todo in list",
@@ -599,6 +1312,16 @@ func TestRenameTodoInList_Success(t *testing.T) {
 			expectedSuccess: true,
 			expectedMessage: `To-do "Buy mom's gift" renamed to "Buy mom's birthday gift" in list "Personal"!`,
 		},
+		{
+			name:            "reports the renamed id",
+			listName:        "Inbox",
+			oldName:         "Old Task Name",
+			newName:         "New Task Name",
+			output:          `SUCCESS: 1 ["ABC123"]`,
+			expectedSuccess: true,
+			expectedMessage: `To-do "Old Task Name" renamed to "New Task Name" in list "Inbox"!`,
+			expectedIDs:     []string{"ABC123"},
+		},
 	}
 
 	for _, tt := range tests {
@@ -606,7 +1329,7 @@ func TestRenameTodoInList_Success(t *testing.T) {
 			cleanup := setupMockExecutor(tt.output, nil)
 			defer cleanup()
 
-			result, err := renameTodoInList(tt.listName, tt.oldName, tt.newName)
+			result, err := renameTodoInList(context.Background(), tt.listName, tt.oldName, tt.newName, MatchOptions{})
 			if err != nil {
 				t.Errorf("unexpected error: %v", err)
 			}
@@ -618,6 +1341,14 @@ func TestRenameTodoInList_Success(t *testing.T) {
 			if result.Message != tt.expectedMessage {
 				t.Errorf("expected message %q, got %q", tt.expectedMessage, result.Message)
 			}
+
+			if result.AffectedCount != 1 {
+				t.Errorf("expected 1 affected to-do, got %d", result.AffectedCount)
+			}
+
+			if !slices.Equal(result.IDs, tt.expectedIDs) {
+				t.Errorf("expected ids %q, got %q", tt.expectedIDs, result.IDs)
+			}
 		})
 	}
 }
@@ -667,7 +1398,7 @@ func TestRenameTodoInList_Errors(t *testing.T) {
 			cleanup := setupMockExecutor(tt.output, tt.execError)
 			defer cleanup()
 
-			result, err := renameTodoInList(tt.listName, tt.oldName, tt.newName)
+			result, err := renameTodoInList(context.Background(), tt.listName, tt.oldName, tt.newName, MatchOptions{})
 
 			if tt.expectErr {
 				if err == nil {
@@ -688,9 +1419,17 @@ func TestRenameTodoInList_Errors(t *testing.T) {
 	}
 }
 
+// setNow pins the package clock to a fixed time for the duration of a test
+func setNow(t *testing.T, fixed time.Time) {
+	t.Helper()
+	original := now
+	now = func() time.Time { return fixed }
+	t.Cleanup(func() { now = original })
+}
+
 func TestCalculateStartDate(t *testing.T) {
 	// Fixed time for testing: Jan 15, 2024 (Monday), 14:30:00
-	now := time.Date(2024, 1, 15, 14, 30, 0, 0, time.UTC)
+	fixed := time.Date(2024, 1, 15, 14, 30, 0, 0, time.UTC)
 
 	tests := []struct {
 		name     string
@@ -722,460 +1461,2731 @@ func TestCalculateStartDate(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			// Note: This test will fail since we can't mock time.Now()
-			// In production code, we'd need to inject time dependency
-			// For now, just documenting expected behavior
-			_ = now
-			_ = tt.expected
-		})
-	}
-}
-
-func TestLogCompletedNow_Success(t *testing.T) {
-	cleanup := setupMockExecutor("SUCCESS", nil)
-	defer cleanup()
+			setNow(t, fixed)
 
-	err := logCompletedNow()
-	if err != nil {
-		t.Errorf("unexpected error: %v", err)
+			result := calculateStartDate(tt.filter, time.Sunday, time.UTC)
+			if !result.Equal(tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, result)
+			}
+		})
 	}
 }
 
-func TestLogCompletedNow_Errors(t *testing.T) {
+func TestCalculateStartDate_WeekStart(t *testing.T) {
 	tests := []struct {
 		name      string
-		output    string
-		execError error
-		expectErr bool
+		fixed     time.Time
+		weekStart time.Weekday
+		expected  time.Time
 	}{
 		{
-			name:      "exec command fails",
-			execError: errors.New("osascript not found"),
-			expectErr: true,
+			name:      "Wednesday with Sunday start",
+			fixed:     time.Date(2024, 1, 17, 14, 30, 0, 0, time.UTC),
+			weekStart: time.Sunday,
+			expected:  time.Date(2024, 1, 14, 0, 0, 0, 0, time.UTC),
 		},
 		{
-			name:      "Things.app returns error",
-			output:    "ERROR: Things.app is not running",
-			expectErr: true,
+			name:      "Wednesday with Monday start",
+			fixed:     time.Date(2024, 1, 17, 14, 30, 0, 0, time.UTC),
+			weekStart: time.Monday,
+			expected:  time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC),
 		},
 		{
-			name:      "JXA script error",
-			output:    "ERROR: logCompletedNow is not a function",
-			expectErr: true,
+			name:      "Sunday with Monday start goes back six days",
+			fixed:     time.Date(2024, 1, 21, 8, 0, 0, 0, time.UTC),
+			weekStart: time.Monday,
+			expected:  time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:      "Monday with Monday start is the same day",
+			fixed:     time.Date(2024, 1, 15, 8, 0, 0, 0, time.UTC),
+			weekStart: time.Monday,
+			expected:  time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC),
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			cleanup := setupMockExecutor(tt.output, tt.execError)
-			defer cleanup()
+			setNow(t, tt.fixed)
+
+			result := calculateStartDate("this week", tt.weekStart, time.UTC)
+			if !result.Equal(tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, result)
+			}
+
+			dateRange, err := parseDateFilter("this week", tt.weekStart, time.UTC)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !dateRange.Start.Equal(tt.expected) {
+				t.Errorf("parseDateFilter: expected %v, got %v", tt.expected, dateRange.Start)
+			}
+		})
+	}
+}
 
-			err := logCompletedNow()
+func TestParseWeekStart(t *testing.T) {
+	tests := []struct {
+		value     string
+		expected  time.Weekday
+		expectErr bool
+	}{
+		{"sunday", time.Sunday, false},
+		{"monday", time.Monday, false},
+		{"Monday", time.Monday, false},
+		{"friday", time.Sunday, true},
+	}
 
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			result, err := parseWeekStart(tt.value)
 			if tt.expectErr {
 				if err == nil {
 					t.Error("expected error but got none")
 				}
-			} else {
-				if err != nil {
-					t.Errorf("unexpected error: %v", err)
-				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, result)
 			}
 		})
 	}
 }
 
-func TestGetCompletedTodos(t *testing.T) {
-	// Mock output with completed todos
-	mockOutput := `[
-		{"name":"Completed task 1","status":"completed","completionDate":"2024-01-15T10:00:00Z"},
-		{"name":"Completed task 2","status":"completed","completionDate":"2024-01-14T15:30:00Z"}
-	]`
+func TestDefaultExecutor_CanceledContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	e := &DefaultExecutor{}
+	_, err := e.ExecuteContext(ctx, "sleep", "5")
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
+
+func TestActions_CanceledContext(t *testing.T) {
+	cleanup := setupMockExecutor(`[]`, nil)
+	defer cleanup()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := getTodosFromList(ctx, "Today")
+	if err == nil || !strings.Contains(err.Error(), context.Canceled.Error()) {
+		t.Errorf("expected canceled error, got %v", err)
+	}
+
+	_, err = addTodoToList(ctx, "Inbox", "Task", nil, nil)
+	if err == nil || !strings.Contains(err.Error(), context.Canceled.Error()) {
+		t.Errorf("expected canceled error, got %v", err)
+	}
+}
 
+func TestCompleteTodoInList(t *testing.T) {
 	tests := []struct {
-		name        string
-		dateFilter  string
-		mockOutputs []string
-		mockErrors  []error
-		expectErr   bool
+		name            string
+		output          string
+		opts            MatchOptions
+		expectedSuccess bool
+		expectedMessage string
 	}{
 		{
-			name:        "get completed todos for today",
-			dateFilter:  "today",
-			mockOutputs: []string{"SUCCESS", mockOutput},
-			mockErrors:  []error{nil, nil},
-			expectErr:   false,
+			name:            "complete single to-do",
+			output:          "SUCCESS: 1",
+			expectedSuccess: true,
+			expectedMessage: `To-do "Task" completed in list "Today"!`,
 		},
 		{
-			name:        "get completed todos for this week",
-			dateFilter:  "this week",
-			mockOutputs: []string{"SUCCESS", mockOutput},
-			mockErrors:  []error{nil, nil},
-			expectErr:   false,
+			name:            "complete all matches",
+			output:          "SUCCESS: 2",
+			opts:            MatchOptions{All: true},
+			expectedSuccess: true,
+			expectedMessage: `Completed 2 to-dos named "Task" in list "Today"!`,
 		},
 		{
-			name:        "get completed todos for this month",
-			dateFilter:  "this month",
-			mockOutputs: []string{"SUCCESS", mockOutput},
-			mockErrors:  []error{nil, nil},
-			expectErr:   false,
+			name:            "to-do not found",
+			output:          "ERROR: To-do not found in list",
+			expectedMessage: `ERROR: To-do "Task" not found in list "Today"`,
 		},
 		{
-			name:        "error from logCompletedNow",
-			dateFilter:  "today",
-			mockOutputs: []string{"ERROR: Things.app is not running"},
-			mockErrors:  []error{nil},
-			expectErr:   true,
+			name:            "list not found",
+			output:          "ERROR: List not found",
+			expectedMessage: `ERROR: List "Today" not found`,
 		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cleanup := setupMockExecutor(tt.output, nil)
+			defer cleanup()
+
+			result, err := completeTodoInList(context.Background(), "Today", "Task", tt.opts)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result.Success != tt.expectedSuccess {
+				t.Errorf("expected success %v, got %v", tt.expectedSuccess, result.Success)
+			}
+			if result.Message != tt.expectedMessage {
+				t.Errorf("expected message %q, got %q", tt.expectedMessage, result.Message)
+			}
+			if script := executor.(*MockExecutor).lastScript(); !strings.Contains(script, "status = 'completed'") {
+				t.Error("expected script to set the completed status")
+			}
+		})
+	}
+}
+
+func TestTodoOperations_ByID(t *testing.T) {
+	tests := []struct {
+		name            string
+		run             func() (OperationResult, error)
+		output          string
+		expectedSuccess bool
+		expectedMessage string
+		expectedAction  string
+	}{
 		{
-			name:        "error from getTodosFromListWithFilter",
-			dateFilter:  "today",
-			mockOutputs: []string{"SUCCESS", `ERROR: List "Logbook" not found`},
-			mockErrors:  []error{nil, nil},
-			expectErr:   true,
+			name:            "delete by id",
+			run:             func() (OperationResult, error) { return deleteTodoByID(context.Background(), "ABC123") },
+			output:          "SUCCESS: Buy milk",
+			expectedSuccess: true,
+			expectedMessage: `To-do "Buy milk" deleted successfully!`,
+			expectedAction:  "app.delete(todo);",
+		},
+		{
+			name:            "complete by id",
+			run:             func() (OperationResult, error) { return completeTodoByID(context.Background(), "ABC123") },
+			output:          "SUCCESS: Buy milk",
+			expectedSuccess: true,
+			expectedMessage: `To-do "Buy milk" completed!`,
+			expectedAction:  "todo.status = 'completed';",
+		},
+		{
+			name:            "rename by id",
+			run:             func() (OperationResult, error) { return renameTodoByID(context.Background(), "ABC123", "Buy oat milk") },
+			output:          "SUCCESS: Buy milk",
+			expectedSuccess: true,
+			expectedMessage: `To-do "Buy milk" renamed to "Buy oat milk"!`,
+			expectedAction:  "todo.name = 'Buy oat milk';",
+		},
+		{
+			name:            "invalid id",
+			run:             func() (OperationResult, error) { return deleteTodoByID(context.Background(), "ABC123") },
+			output:          "ERROR: To-do not found",
+			expectedMessage: `ERROR: No to-do found with id "ABC123"`,
+			expectedAction:  "app.delete(todo);",
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			cleanup := setupMockExecutorMulti(tt.mockOutputs, tt.mockErrors)
+			cleanup := setupMockExecutor(tt.output, nil)
 			defer cleanup()
 
-			result, err := getCompletedTodos(tt.dateFilter)
+			result, err := tt.run()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result.Success != tt.expectedSuccess {
+				t.Errorf("expected success %v, got %v", tt.expectedSuccess, result.Success)
+			}
+			if result.Message != tt.expectedMessage {
+				t.Errorf("expected message %q, got %q", tt.expectedMessage, result.Message)
+			}
 
-			if tt.expectErr {
-				if err == nil {
-					t.Error("expected error but got none")
-				}
-			} else {
-				if err != nil {
-					t.Errorf("unexpected error: %v", err)
+			script := executor.(*MockExecutor).lastScript()
+			if !strings.Contains(script, "findTodoById(app, 'ABC123')") {
+				t.Error("expected script to look up the to-do by id")
+			}
+			if strings.Contains(script, "lists.byName") {
+				t.Error("expected script not to scan lists")
+			}
+			if !strings.Contains(script, tt.expectedAction) {
+				t.Errorf("expected script to contain %q", tt.expectedAction)
+			}
+		})
+	}
+}
+
+func TestCompleteTodosInList(t *testing.T) {
+	tests := []struct {
+		name            string
+		tag             string
+		output          string
+		expectedMessage string
+		expectedIDs     []string
+		expectScript    string
+	}{
+		{
+			name:            "every open to-do",
+			output:          `SUCCESS: 3 ["A1","A2","A4"]`,
+			expectedMessage: `Completed 3 to-dos in list "Today"!`,
+			expectedIDs:     []string{"A1", "A2", "A4"},
+			expectScript:    "if (todos[i].status() === 'open' && true) {",
+		},
+		{
+			name:            "tagged to-dos",
+			tag:             "errand",
+			output:          `SUCCESS: 1 ["A2"]`,
+			expectedMessage: `Completed 1 to-dos in list "Today"!`,
+			expectedIDs:     []string{"A2"},
+			expectScript:    "if (todos[i].status() === 'open' && todos[i].tags().some(function(t) { return t.name() === 'errand'; })) {",
+		},
+		{
+			name:            "everything already completed",
+			output:          "SUCCESS: 0 []",
+			expectedMessage: `Completed 0 to-dos in list "Today"!`,
+			expectScript:    "todos[i].status() === 'open'",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cleanup := setupMockExecutor(tt.output, nil)
+			defer cleanup()
+
+			result, err := completeTodosInList(context.Background(), "Today", tt.tag)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !result.Success || result.Message != tt.expectedMessage {
+				t.Errorf("expected success with %q, got %+v", tt.expectedMessage, result)
+			}
+			if result.AffectedCount != len(tt.expectedIDs) || !slices.Equal(result.IDs, tt.expectedIDs) {
+				t.Errorf("expected ids %v, got %v (count %d)", tt.expectedIDs, result.IDs, result.AffectedCount)
+			}
+			// Only open to-dos are changed, so completed and canceled ones keep their dates
+			if script := executor.(*MockExecutor).lastScript(); !strings.Contains(script, tt.expectScript) {
+				t.Errorf("expected script to contain %q", tt.expectScript)
+			}
+		})
+	}
+}
+
+func TestCompleteTodosInList_ListNotFound(t *testing.T) {
+	cleanup := setupMockExecutor("ERROR: List not found: Can't get object.", nil)
+	defer cleanup()
+
+	result, err := completeTodosInList(context.Background(), "Nowhere", "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Success || result.Message != `ERROR: List "Nowhere" not found` {
+		t.Errorf("unexpected result: %+v", result)
+	}
+}
+
+func TestReopenTodo(t *testing.T) {
+	tests := []struct {
+		name            string
+		listName        string
+		output          string
+		expectedSuccess bool
+		expectedMessage string
+		expectedIDs     []string
+	}{
+		{
+			name:            "success",
+			listName:        "Logbook",
+			output:          `SUCCESS: 1 ["ABC123"]`,
+			expectedSuccess: true,
+			expectedMessage: `To-do "Buy milk" reopened!`,
+			expectedIDs:     []string{"ABC123"},
+		},
+		{
+			name:            "todo not found",
+			listName:        "Logbook",
+			output:          "ERROR: To-do not found in list",
+			expectedMessage: `ERROR: To-do "Buy milk" not found in list "Logbook"`,
+		},
+		{
+			name:            "list not found",
+			listName:        "Nowhere",
+			output:          "ERROR: List not found: Can't get object.",
+			expectedMessage: `ERROR: List "Nowhere" not found`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cleanup := setupMockExecutor(tt.output, nil)
+			defer cleanup()
+
+			result, err := reopenTodo(context.Background(), tt.listName, "Buy milk")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result.Success != tt.expectedSuccess {
+				t.Errorf("expected success %v, got %v", tt.expectedSuccess, result.Success)
+			}
+			if result.Message != tt.expectedMessage {
+				t.Errorf("expected message %q, got %q", tt.expectedMessage, result.Message)
+			}
+			if !slices.Equal(result.IDs, tt.expectedIDs) {
+				t.Errorf("expected ids %v, got %v", tt.expectedIDs, result.IDs)
+			}
+
+			script := executor.(*MockExecutor).lastScript()
+			for _, want := range []string{"status() !== 'open'", "todo.status = 'open';"} {
+				if !strings.Contains(script, want) {
+					t.Errorf("expected script to contain %q", want)
 				}
-				if result == nil {
-					t.Error("expected result but got nil")
+			}
+		})
+	}
+}
+
+func TestRestoreTodoFromTrash(t *testing.T) {
+	tests := []struct {
+		name            string
+		output          string
+		expectedSuccess bool
+		expectedMessage string
+		expectedIDs     []string
+	}{
+		{
+			name:            "single match",
+			output:          `SUCCESS: 1 ["ABC123"]`,
+			expectedSuccess: true,
+			expectedMessage: `To-do "Buy milk" restored from the Trash to the Inbox!`,
+			expectedIDs:     []string{"ABC123"},
+		},
+		{
+			name:            "several matches restore the most recent",
+			output:          `SUCCESS: 3 ["DEF456"]`,
+			expectedSuccess: true,
+			expectedMessage: `To-do "Buy milk" restored from the Trash to the Inbox! (3 trashed to-dos matched; the most recently deleted was restored, use --id to pick another)`,
+			expectedIDs:     []string{"DEF456"},
+		},
+		{
+			name:            "not found",
+			output:          "ERROR: To-do not found in list",
+			expectedMessage: `ERROR: To-do "Buy milk" not found in list "Trash"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cleanup := setupMockExecutor(tt.output, nil)
+			defer cleanup()
+
+			result, err := restoreTodoFromTrash(context.Background(), "Buy milk")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result.Success != tt.expectedSuccess {
+				t.Errorf("expected success %v, got %v", tt.expectedSuccess, result.Success)
+			}
+			if result.Message != tt.expectedMessage {
+				t.Errorf("expected message %q, got %q", tt.expectedMessage, result.Message)
+			}
+			if !slices.Equal(result.IDs, tt.expectedIDs) {
+				t.Errorf("expected ids %v, got %v", tt.expectedIDs, result.IDs)
+			}
+
+			script := executor.(*MockExecutor).lastScript()
+			for _, want := range []string{"lists.byName('Trash')", "modificationDate()", "app.move(todo, {to: app.lists.byName('Inbox')})"} {
+				if !strings.Contains(script, want) {
+					t.Errorf("expected script to contain %q", want)
 				}
 			}
 		})
 	}
 }
 
-func TestGetCompletedTodosFiltered(t *testing.T) {
-	mockOutput := `[
-		{"name":"Task 1","status":"completed","area":"Work","project":"Project A"},
-		{"name":"Task 2","status":"completed","area":"Personal","project":""},
-		{"name":"Task 3","status":"completed","area":"Work","project":"Project B"}
-	]`
+func TestRestoreTodoByID(t *testing.T) {
+	cleanup := setupMockExecutorMulti([]string{"SUCCESS: Buy milk", "ERROR: To-do not found"}, []error{nil, nil})
+	defer cleanup()
+
+	result, err := restoreTodoByID(context.Background(), "ABC123")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !result.Success || result.Message != `To-do "Buy milk" restored to the Inbox!` {
+		t.Errorf("unexpected result: %+v", result)
+	}
+	if !strings.Contains(executor.(*MockExecutor).lastScript(), "findTodoById(app, 'ABC123')") {
+		t.Error("expected script to look up the to-do by id")
+	}
+
+	result, err = restoreTodoByID(context.Background(), "ABC123")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Success || result.Message != `ERROR: No to-do found with id "ABC123"` {
+		t.Errorf("unexpected result: %+v", result)
+	}
+}
+
+func TestFindTodosByRegex(t *testing.T) {
+	listOutput := `[{"id":"A1","name":"Buy milk"},{"id":"A2","name":"Buy eggs"},{"id":"A3","name":"Call mom"}]`
 
 	tests := []struct {
 		name          string
-		dateFilter    string
-		areaFilter    string
-		projectFilter string
-		expectCount   int
+		pattern       string
+		opts          MatchOptions
+		expectedIDs   []string
+		expectedTotal int
+		expectedErr   string
+	}{
+		{"first match", `^Buy`, MatchOptions{}, []string{"A1"}, 2, ""},
+		{"all matches", `^Buy`, MatchOptions{All: true}, []string{"A1", "A2"}, 2, ""},
+		{"indexed match", `^Buy`, MatchOptions{Index: 1}, []string{"A2"}, 2, ""},
+		{"no match", `^bread$`, MatchOptions{}, nil, 0, `ERROR: No to-do matching /^bread$/ found in list "Inbox"`},
+		{"index out of range", `mom`, MatchOptions{Index: 1}, nil, 0, `ERROR: Index 1 is out of range; only 1 to-dos matching /mom/ found in list "Inbox"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cleanup := setupMockExecutor(listOutput, nil)
+			defer cleanup()
+
+			todos, total, err := findTodosByRegex(context.Background(), "Inbox", regexp.MustCompile(tt.pattern), tt.opts)
+			if tt.expectedErr != "" {
+				if err == nil || err.Error() != tt.expectedErr {
+					t.Fatalf("expected error %q, got %v", tt.expectedErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			var ids []string
+			for _, todo := range todos {
+				ids = append(ids, todo.ID)
+			}
+			if !slices.Equal(ids, tt.expectedIDs) || total != tt.expectedTotal {
+				t.Errorf("expected %v of %d, got %v of %d", tt.expectedIDs, tt.expectedTotal, ids, total)
+			}
+		})
+	}
+}
+
+func TestMoveTodoByID(t *testing.T) {
+	tests := []struct {
+		name            string
+		dest            moveDestination
+		output          string
+		expectedSuccess bool
+		expectedMessage string
+		expectedAction  string
 	}{
+		{"to list", listDestination("Work"), "SUCCESS: Buy milk", true, `To-do "Buy milk" moved to list "Work"!`, "app.move(todo, {to: destination});"},
+		{"to today", listDestination("Today"), "SUCCESS: Buy milk", true, `To-do "Buy milk" moved to list "Today"!`, "app.schedule(todo, {for: new Date()});"},
+		{"to project", projectDestination("Launch"), "SUCCESS: Buy milk", true, `To-do "Buy milk" moved to project "Launch"!`, "todo.project = destination;"},
+		{"missing project", projectDestination("Nope"), "ERROR: To-do not found: Destination not found", false, `ERROR: Project "Nope" not found`, "throw new Error('Destination not found')"},
+		{"invalid id", listDestination("Work"), "ERROR: To-do not found: Can't get object", false, `ERROR: No to-do found with id "ABC123"`, "findTodoById(app, 'ABC123')"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cleanup := setupMockExecutor(tt.output, nil)
+			defer cleanup()
+
+			result, err := moveTodoByID(context.Background(), "ABC123", tt.dest)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result.Success != tt.expectedSuccess || result.Message != tt.expectedMessage {
+				t.Errorf("expected (%v, %q), got (%v, %q)", tt.expectedSuccess, tt.expectedMessage, result.Success, result.Message)
+			}
+			if script := executor.(*MockExecutor).lastScript(); !strings.Contains(script, tt.expectedAction) {
+				t.Errorf("expected script to contain %q", tt.expectedAction)
+			}
+		})
+	}
+}
+
+func TestApplyByID(t *testing.T) {
+	todos := []Todo{{ID: "A1"}, {ID: "A2"}, {ID: "A3"}}
+	cleanup := setupMockExecutorMulti([]string{"SUCCESS: Buy milk", "ERROR: To-do not found"}, []error{nil, nil})
+	defer cleanup()
+
+	result, err := applyByID(context.Background(), todos, deleteTodoByID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Success {
+		t.Error("expected the second deletion's failure to stop the run")
+	}
+	if result.Message != `ERROR: No to-do found with id "A2"` || result.AffectedCount != 1 || !slices.Equal(result.IDs, []string{"A1"}) {
+		t.Errorf("unexpected result: %+v", result)
+	}
+	if calls := len(executor.(*MockExecutor).calls); calls != 2 {
+		t.Errorf("expected 2 executor calls, got %d", calls)
+	}
+}
+
+func TestTodoOperations_ByID_ExecError(t *testing.T) {
+	cleanup := setupMockExecutor("", errors.New("osascript failed"))
+	defer cleanup()
+
+	if _, err := completeTodoByID(context.Background(), "ABC123"); err == nil {
+		t.Error("expected error but got none")
+	}
+}
+
+func TestBuildShowURL(t *testing.T) {
+	result := buildShowURL("2Xr4 ab&c")
+	expected := "things:///show?id=2Xr4%20ab%26c"
+	if result != expected {
+		t.Errorf("expected %q, got %q", expected, result)
+	}
+}
+
+func TestParseTodoIDLookup(t *testing.T) {
+	tests := []struct {
+		output      string
+		expectID    string
+		expectCount int
+		expectErr   bool
+	}{
+		{"SUCCESS: 1 ABC123", "ABC123", 1, false},
+		{"SUCCESS: 3 ABC123", "ABC123", 3, false},
+		{"SUCCESS: 1", "", 0, true},
+		{"SUCCESS: x ABC123", "", 0, true},
+		{"SUCCESS: 0 ABC123", "", 0, true},
+		{"something else", "", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.output, func(t *testing.T) {
+			id, count, err := parseTodoIDLookup(tt.output)
+			if tt.expectErr {
+				if err == nil {
+					t.Error("expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if id != tt.expectID || count != tt.expectCount {
+				t.Errorf("expected (%q, %d), got (%q, %d)", tt.expectID, tt.expectCount, id, count)
+			}
+		})
+	}
+}
+
+func TestOpenTodoInThings(t *testing.T) {
+	tests := []struct {
+		name            string
+		outputs         []string
+		expectedSuccess bool
+		expectedMessage string
+		expectedURL     string
+	}{
+		{
+			name:            "single match",
+			outputs:         []string{"SUCCESS: 1 ABC123", ""},
+			expectedSuccess: true,
+			expectedMessage: `Opened to-do "Task" from list "Today" in Things.app!`,
+			expectedURL:     "things:///show?id=ABC123",
+		},
+		{
+			name:            "several matches open the first",
+			outputs:         []string{"SUCCESS: 2 ABC123", ""},
+			expectedSuccess: true,
+			expectedMessage: `Opened to-do "Task" from list "Today" in Things.app! (2 to-dos matched; opened the first)`,
+			expectedURL:     "things:///show?id=ABC123",
+		},
+		{
+			name:            "to-do not found",
+			outputs:         []string{"ERROR: To-do not found in list"},
+			expectedMessage: `ERROR: To-do "Task" not found in list "Today"`,
+		},
+		{
+			name:            "list not found",
+			outputs:         []string{"ERROR: List not found"},
+			expectedMessage: `ERROR: List "Today" not found`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cleanup := setupMockExecutorMulti(tt.outputs, make([]error, len(tt.outputs)))
+			defer cleanup()
+
+			result, err := openTodoInThings(context.Background(), "Today", "Task")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result.Success != tt.expectedSuccess {
+				t.Errorf("expected success %v, got %v", tt.expectedSuccess, result.Success)
+			}
+			if result.Message != tt.expectedMessage {
+				t.Errorf("expected message %q, got %q", tt.expectedMessage, result.Message)
+			}
+
+			calls := executor.(*MockExecutor).calls
+			if tt.expectedURL == "" {
+				if len(calls) != 1 {
+					t.Errorf("expected only the lookup call, got %d calls", len(calls))
+				}
+				return
+			}
+			if len(calls) != 2 || calls[1][0] != "open" || calls[1][1] != tt.expectedURL {
+				t.Errorf("expected open %s, got %v", tt.expectedURL, calls)
+			}
+		})
+	}
+}
+
+func TestLogCompletedNow_Success(t *testing.T) {
+	cleanup := setupMockExecutor("SUCCESS", nil)
+	defer cleanup()
+
+	err := logCompletedNow(context.Background())
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestLogCompletedNow_Errors(t *testing.T) {
+	tests := []struct {
+		name      string
+		output    string
+		execError error
+		expectErr bool
+	}{
+		{
+			name:      "exec command fails",
+			execError: errors.New("osascript not found"),
+			expectErr: true,
+		},
+		{
+			name:      "Things.app returns error",
+			output:    "ERROR: Things.app is not running",
+			expectErr: true,
+		},
+		{
+			name:      "JXA script error",
+			output:    "ERROR: logCompletedNow is not a function",
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cleanup := setupMockExecutor(tt.output, tt.execError)
+			defer cleanup()
+
+			err := logCompletedNow(context.Background())
+
+			if tt.expectErr {
+				if err == nil {
+					t.Error("expected error but got none")
+				}
+			} else {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+			}
+		})
+	}
+}
+
+func TestGetCompletedTodos(t *testing.T) {
+	// Mock output with completed todos
+	mockOutput := `[
+		{"name":"Completed task 1","status":"completed","completionDate":"2024-01-15T10:00:00Z"},
+		{"name":"Completed task 2","status":"completed","completionDate":"2024-01-14T15:30:00Z"}
+	]`
+
+	tests := []struct {
+		name        string
+		dateFilter  string
+		noFlush     bool
+		mockOutputs []string
+		mockErrors  []error
+		expectErr   bool
+	}{
+		{
+			name:        "get completed todos for today",
+			dateFilter:  "today",
+			mockOutputs: []string{"SUCCESS", mockOutput},
+			mockErrors:  []error{nil, nil},
+			expectErr:   false,
+		},
+		{
+			name:        "get completed todos for this week",
+			dateFilter:  "this week",
+			mockOutputs: []string{"SUCCESS", mockOutput},
+			mockErrors:  []error{nil, nil},
+			expectErr:   false,
+		},
+		{
+			name:        "get completed todos for this month",
+			dateFilter:  "this month",
+			mockOutputs: []string{"SUCCESS", mockOutput},
+			mockErrors:  []error{nil, nil},
+			expectErr:   false,
+		},
+		{
+			name:        "error from logCompletedNow",
+			dateFilter:  "today",
+			mockOutputs: []string{"ERROR: Things.app is not running"},
+			mockErrors:  []error{nil},
+			expectErr:   true,
+		},
+		{
+			name:        "error from getTodosFromListWithFilter",
+			dateFilter:  "today",
+			mockOutputs: []string{"SUCCESS", `ERROR: List "Logbook" not found`},
+			mockErrors:  []error{nil, nil},
+			expectErr:   true,
+		},
+		{
+			name:        "without flushing",
+			dateFilter:  "today",
+			noFlush:     true,
+			mockOutputs: []string{mockOutput},
+			mockErrors:  []error{nil},
+			expectErr:   false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cleanup := setupMockExecutorMulti(tt.mockOutputs, tt.mockErrors)
+			defer cleanup()
+
+			result, err := getCompletedTodos(context.Background(), tt.dateFilter, time.Sunday, time.Local, !tt.noFlush)
+
+			if tt.expectErr {
+				if err == nil {
+					t.Error("expected error but got none")
+				}
+			} else {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				if result == nil {
+					t.Error("expected result but got nil")
+				}
+			}
+
+			calls := executor.(*MockExecutor).calls
+			if tt.noFlush && len(calls) != 1 {
+				t.Errorf("expected only the Logbook to be read, got %d calls", len(calls))
+			}
+			flushed := strings.Contains(calls[0][len(calls[0])-1], "app.logCompletedNow();")
+			if flushed == tt.noFlush {
+				t.Errorf("expected flushing to be %v, got %v", !tt.noFlush, flushed)
+			}
+		})
+	}
+}
+
+// areaProjectFixture is a to-do list with a mix of areas and projects, shared by the area and project filter tests
+const areaProjectFixture = `[
+	{"name":"Task 1","status":"completed","area":"Work","project":"Project A"},
+	{"name":"Task 2","status":"completed","area":"Personal","project":""},
+	{"name":"Task 3","status":"completed","area":"Work","project":"Project B"}
+]`
+
+// areaProjectFilterCases are the expected matches in areaProjectFixture for each area and project filter
+var areaProjectFilterCases = []struct {
+	name          string
+	areaFilter    string
+	projectFilter string
+	expectCount   int
+}{
+	{
+		name:        "no filters",
+		expectCount: 3,
+	},
+	{
+		name:        "filter by area",
+		areaFilter:  "Work",
+		expectCount: 2,
+	},
+	{
+		name:          "filter by project",
+		projectFilter: "Project A",
+		expectCount:   1,
+	},
+	{
+		name:          "filter by both area and project",
+		areaFilter:    "Work",
+		projectFilter: "Project B",
+		expectCount:   1,
+	},
+	{
+		name:        "no matches",
+		areaFilter:  "NonExistent",
+		expectCount: 0,
+	},
+}
+
+func TestGetCompletedTodosFiltered(t *testing.T) {
+	mockOutput := areaProjectFixture
+	tests := areaProjectFilterCases
+
+	for _, flush := range []bool{true, false} {
+		for _, tt := range tests {
+			t.Run(fmt.Sprintf("%s (flush %v)", tt.name, flush), func(t *testing.T) {
+				// Mock both logCompletedNow(context.Background()) and getTodosFromListWithFilter() calls,
+				// or just the latter without flushing
+				outputs := []string{"SUCCESS", mockOutput}
+				if !flush {
+					outputs = outputs[1:]
+				}
+				cleanup := setupMockExecutorMulti(outputs, make([]error, len(outputs)))
+				defer cleanup()
+
+				result, err := getCompletedTodosFiltered(context.Background(), "today", tt.areaFilter, tt.projectFilter, time.Sunday, time.Local, flush)
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+
+				if len(result) != tt.expectCount {
+					t.Errorf("expected %d todos, got %d", tt.expectCount, len(result))
+				}
+				if calls := executor.(*MockExecutor).calls; len(calls) != len(outputs) {
+					t.Errorf("expected %d calls, got %d", len(outputs), len(calls))
+				}
+			})
+		}
+	}
+}
+
+func TestGetTodosWithRichData(t *testing.T) {
+	mockOutput := `[
+		{
+			"id":"2Xr4TrcUwZ9jLQdXhkT3fd",
+			"name":"Task with all fields",
+			"notes":"Important notes",
+			"status":"open",
+			"creationDate":"2024-01-10T10:00:00Z",
+			"dueDate":"2024-01-20T00:00:00Z",
+			"tagNames":["Work","Important"],
+			"area":"Projects",
+			"project":"Q1 Goals"
+		},
+		{
+			"name":"Simple task",
+			"status":"open"
+		}
+	]`
+
+	cleanup := setupMockExecutor(mockOutput, nil)
+	defer cleanup()
+
+	todos, err := getTodosFromList(context.Background(), "Work")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(todos) != 2 {
+		t.Fatalf("expected 2 todos, got %d", len(todos))
+	}
+
+	// Test rich data parsing
+	richTodo := todos[0]
+	if richTodo.ID != "2Xr4TrcUwZ9jLQdXhkT3fd" {
+		t.Errorf("expected id '2Xr4TrcUwZ9jLQdXhkT3fd', got %q", richTodo.ID)
+	}
+	if richTodo.Name != "Task with all fields" {
+		t.Errorf("expected name 'Task with all fields', got %q", richTodo.Name)
+	}
+	if richTodo.Notes != "Important notes" {
+		t.Errorf("expected notes 'Important notes', got %q", richTodo.Notes)
+	}
+	if richTodo.Area != "Projects" {
+		t.Errorf("expected area 'Projects', got %q", richTodo.Area)
+	}
+	if richTodo.Project != "Q1 Goals" {
+		t.Errorf("expected project 'Q1 Goals', got %q", richTodo.Project)
+	}
+	if len(richTodo.TagNames) != 2 {
+		t.Errorf("expected 2 tags, got %d", len(richTodo.TagNames))
+	}
+	if richTodo.DueDate == nil {
+		t.Error("expected dueDate to be set")
+	}
+	if richTodo.CreationDate == nil {
+		t.Error("expected creationDate to be set")
+	}
+
+	// Test simple todo
+	simpleTodo := todos[1]
+	if simpleTodo.Name != "Simple task" {
+		t.Errorf("expected name 'Simple task', got %q", simpleTodo.Name)
+	}
+	if simpleTodo.ID != "" {
+		t.Error("expected empty id")
+	}
+	if simpleTodo.Notes != "" {
+		t.Error("expected empty notes")
+	}
+	if len(simpleTodo.TagNames) != 0 {
+		t.Error("expected empty tags")
+	}
+}
+
+func TestGetTodos_ChecklistItems(t *testing.T) {
+	mockOutput := `[
+		{
+			"name":"Pack for trip",
+			"status":"open",
+			"checklistItems":[
+				{"name":"Passport","completed":true},
+				{"name":"Charger","completed":false}
+			]
+		},
+		{"name":"No checklist","status":"open"}
+	]`
+
+	cleanup := setupMockExecutor(mockOutput, nil)
+	defer cleanup()
+
+	todos, err := getTodosFromList(context.Background(), "Today")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	items := todos[0].ChecklistItems
+	if len(items) != 2 {
+		t.Fatalf("expected 2 checklist items, got %d", len(items))
+	}
+	if items[0].Name != "Passport" || !items[0].Completed {
+		t.Errorf("expected completed item 'Passport', got %+v", items[0])
+	}
+	if items[1].Name != "Charger" || items[1].Completed {
+		t.Errorf("expected open item 'Charger', got %+v", items[1])
+	}
+	if todos[1].ChecklistItems != nil {
+		t.Errorf("expected no checklist items, got %+v", todos[1].ChecklistItems)
+	}
+
+	script := executor.(*MockExecutor).lastScript()
+	if !strings.Contains(script, "todo.checklistItems()") {
+		t.Error("expected script to read checklist items")
+	}
+}
+
+func TestParseDateFilter(t *testing.T) {
+	tests := []struct {
+		name          string
+		filter        string
+		expectError   bool
+		expectSingle  bool
+		validateStart func(time.Time) bool
+	}{
+		{
+			name:         "keyword: today",
+			filter:       "today",
+			expectError:  false,
+			expectSingle: false,
+			validateStart: func(t time.Time) bool {
+				// Should be midnight of the pinned day
+				return t.Equal(time.Date(2024, 1, 17, 0, 0, 0, 0, time.UTC))
+			},
+		},
+		{
+			name:         "keyword: this week",
+			filter:       "this week",
+			expectError:  false,
+			expectSingle: false,
+			validateStart: func(t time.Time) bool {
+				// Should be the Sunday before the pinned Wednesday
+				return t.Equal(time.Date(2024, 1, 14, 0, 0, 0, 0, time.UTC))
+			},
+		},
+		{
+			name:         "keyword: this month",
+			filter:       "this month",
+			expectError:  false,
+			expectSingle: false,
+			validateStart: func(t time.Time) bool {
+				// Should be first day of the pinned month
+				return t.Equal(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+			},
+		},
+		{
+			name:         "YYYY-MM-DD date",
+			filter:       "2024-01-15",
+			expectError:  false,
+			expectSingle: true,
+			validateStart: func(t time.Time) bool {
+				expected := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+				return t.Equal(expected)
+			},
+		},
+		{
+			name:         "YYYY-MM-DD different date",
+			filter:       "2023-12-25",
+			expectError:  false,
+			expectSingle: true,
+			validateStart: func(t time.Time) bool {
+				expected := time.Date(2023, 12, 25, 0, 0, 0, 0, time.UTC)
+				return t.Equal(expected)
+			},
+		},
+		{
+			name:        "invalid keyword",
+			filter:      "tomorrow",
+			expectError: true,
+		},
+		{
+			name:        "invalid date format DD-MM-YYYY",
+			filter:      "15-01-2024",
+			expectError: true,
+		},
+		{
+			name:        "incomplete date",
+			filter:      "2024-01",
+			expectError: true,
+		},
+		{
+			name:        "malformed date",
+			filter:      "2024-13-01",
+			expectError: true,
+		},
+		{
+			name:        "invalid date",
+			filter:      "not-a-date",
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Wednesday, Jan 17, 2024
+			setNow(t, time.Date(2024, 1, 17, 9, 45, 0, 0, time.UTC))
+
+			dateRange, err := parseDateFilter(tt.filter, time.Sunday, time.UTC)
+
+			if tt.expectError {
+				if err == nil {
+					t.Error("expected error but got none")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+				return
+			}
+
+			isSingleDay := dateRange.End.Equal(dateRange.Start.AddDate(0, 0, 1))
+			if isSingleDay != tt.expectSingle {
+				t.Errorf("expected isSingleDay=%v, got %v", tt.expectSingle, isSingleDay)
+			}
+
+			if !tt.expectSingle && !dateRange.End.IsZero() {
+				t.Errorf("expected open-ended range, got end %v", dateRange.End)
+			}
+
+			if tt.validateStart != nil && !tt.validateStart(dateRange.Start) {
+				t.Errorf("start time validation failed for %v", dateRange.Start)
+			}
+		})
+	}
+}
+
+func TestParseDateFilter_RelativeKeywords(t *testing.T) {
+	tests := []struct {
+		name        string
+		filter      string
+		weekStart   time.Weekday
+		expectStart time.Time
+		expectEnd   time.Time
+	}{
+		{
+			name:        "yesterday",
+			filter:      "yesterday",
+			weekStart:   time.Sunday,
+			expectStart: time.Date(2024, 1, 16, 0, 0, 0, 0, time.UTC),
+			expectEnd:   time.Date(2024, 1, 17, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:        "last week with Sunday start",
+			filter:      "last week",
+			weekStart:   time.Sunday,
+			expectStart: time.Date(2024, 1, 7, 0, 0, 0, 0, time.UTC),
+			expectEnd:   time.Date(2024, 1, 14, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:        "last week with Monday start",
+			filter:      "last week",
+			weekStart:   time.Monday,
+			expectStart: time.Date(2024, 1, 8, 0, 0, 0, 0, time.UTC),
+			expectEnd:   time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:        "last month crosses the year boundary",
+			filter:      "last month",
+			weekStart:   time.Sunday,
+			expectStart: time.Date(2023, 12, 1, 0, 0, 0, 0, time.UTC),
+			expectEnd:   time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Wednesday, Jan 17, 2024
+			setNow(t, time.Date(2024, 1, 17, 9, 45, 0, 0, time.UTC))
+
+			dateRange, err := parseDateFilter(tt.filter, tt.weekStart, time.UTC)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !dateRange.Start.Equal(tt.expectStart) {
+				t.Errorf("expected start %v, got %v", tt.expectStart, dateRange.Start)
+			}
+			if !dateRange.End.Equal(tt.expectEnd) {
+				t.Errorf("expected end %v, got %v", tt.expectEnd, dateRange.End)
+			}
+		})
+	}
+}
+
+func TestParseDateFilter_Range(t *testing.T) {
+	tests := []struct {
+		name          string
+		filter        string
+		expectError   bool
+		expectStart   time.Time
+		expectEnd     time.Time
+		errorContains string
+	}{
+		{
+			name:        "valid range",
+			filter:      "2024-01-10..2024-01-15",
+			expectStart: time.Date(2024, 1, 10, 0, 0, 0, 0, time.Local),
+			expectEnd:   time.Date(2024, 1, 16, 0, 0, 0, 0, time.Local),
+		},
+		{
+			name:        "single-day range",
+			filter:      "2024-01-15..2024-01-15",
+			expectStart: time.Date(2024, 1, 15, 0, 0, 0, 0, time.Local),
+			expectEnd:   time.Date(2024, 1, 16, 0, 0, 0, 0, time.Local),
+		},
+		{
+			name:        "range across months",
+			filter:      "2023-12-30..2024-01-02",
+			expectStart: time.Date(2023, 12, 30, 0, 0, 0, 0, time.Local),
+			expectEnd:   time.Date(2024, 1, 3, 0, 0, 0, 0, time.Local),
+		},
+		{
+			name:          "inverted range",
+			filter:        "2024-01-15..2024-01-10",
+			expectError:   true,
+			errorContains: "ERROR:",
+		},
+		{
+			name:        "invalid start",
+			filter:      "2024-13-01..2024-01-10",
+			expectError: true,
+		},
+		{
+			name:        "missing end",
+			filter:      "2024-01-10..",
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dateRange, err := parseDateFilter(tt.filter, time.Sunday, time.Local)
+
+			if tt.expectError {
+				if err == nil {
+					t.Fatal("expected error but got none")
+				}
+				if tt.errorContains != "" && !strings.Contains(err.Error(), tt.errorContains) {
+					t.Errorf("expected error containing %q, got %q", tt.errorContains, err.Error())
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !dateRange.Start.Equal(tt.expectStart) {
+				t.Errorf("expected start %v, got %v", tt.expectStart, dateRange.Start)
+			}
+			if !dateRange.End.Equal(tt.expectEnd) {
+				t.Errorf("expected end %v, got %v", tt.expectEnd, dateRange.End)
+			}
+		})
+	}
+}
+
+func TestParseTimezone(t *testing.T) {
+	tests := []struct {
+		value     string
+		expected  string
+		expectErr bool
+	}{
+		{"", "", false},
+		{"UTC", "UTC", false},
+		{"America/New_York", "America/New_York", false},
+		{"Mars/Olympus_Mons", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			loc, err := parseTimezone(tt.value)
+			if tt.expectErr {
+				if err == nil || !strings.HasPrefix(err.Error(), "ERROR: --timezone") {
+					t.Errorf("expected --timezone error, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.value == "" {
+				if loc != time.Local {
+					t.Errorf("expected the local timezone, got %s", loc)
+				}
+				return
+			}
+			if loc.String() != tt.expected {
+				t.Errorf("expected %s, got %s", tt.expected, loc)
+			}
+		})
+	}
+}
+
+func TestGetCompletedTodos_Timezone(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("timezone data unavailable: %v", err)
+	}
+	// 02:00 UTC on Jan 16 is still the evening of Jan 15 in New York
+	setNow(t, time.Date(2024, 1, 16, 2, 0, 0, 0, time.UTC))
+
+	mockOutput := `[
+		{"name":"Morning","status":"completed","completionDate":"2024-01-15T14:00:00Z"},
+		{"name":"Late evening","status":"completed","completionDate":"2024-01-16T01:00:00Z"}
+	]`
+
+	tests := []struct {
+		name        string
+		dateFilter  string
+		loc         *time.Location
+		expectStart time.Time
+		expectNames []string
+	}{
+		{
+			name:        "single day in UTC",
+			dateFilter:  "2024-01-15",
+			loc:         time.UTC,
+			expectStart: time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC),
+			expectNames: []string{"Morning"},
+		},
+		{
+			name:        "single day in New York",
+			dateFilter:  "2024-01-15",
+			loc:         newYork,
+			expectStart: time.Date(2024, 1, 15, 5, 0, 0, 0, time.UTC),
+			expectNames: []string{"Morning", "Late evening"},
+		},
+		{
+			name:        "today in UTC",
+			dateFilter:  "today",
+			loc:         time.UTC,
+			expectStart: time.Date(2024, 1, 16, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:        "today in New York",
+			dateFilter:  "today",
+			loc:         newYork,
+			expectStart: time.Date(2024, 1, 15, 5, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dateRange, err := parseDateFilter(tt.dateFilter, time.Sunday, tt.loc)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !dateRange.Start.Equal(tt.expectStart) {
+				t.Errorf("expected start %v, got %v", tt.expectStart, dateRange.Start.UTC())
+			}
+			if tt.expectNames == nil {
+				return
+			}
+
+			cleanup := setupMockExecutorMulti([]string{"SUCCESS", mockOutput}, []error{nil, nil})
+			defer cleanup()
+
+			result, err := getCompletedTodos(context.Background(), tt.dateFilter, time.Sunday, tt.loc, true)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			names := make([]string, len(result))
+			for i, todo := range result {
+				names[i] = todo.Name
+			}
+			if !slices.Equal(names, tt.expectNames) {
+				t.Errorf("expected %v, got %v", tt.expectNames, names)
+			}
+		})
+	}
+}
+
+func TestGetCompletedTodos_SingleDayFiltering(t *testing.T) {
+	// Test that single-day filtering properly excludes todos from the next day
+	// Use Local timezone to match the filtering logic in getCompletedTodos
+	jan15Start := time.Date(2024, 1, 15, 0, 0, 0, 0, time.Local)
+	jan15Mid := time.Date(2024, 1, 15, 12, 0, 0, 0, time.Local)
+	jan16Start := time.Date(2024, 1, 16, 0, 0, 1, 0, time.Local)
+	jan16Mid := time.Date(2024, 1, 16, 12, 0, 0, 0, time.Local)
+
+	mockOutputWithMultipleDays := fmt.Sprintf(`[
+		{"name":"Task 1","status":"completed","completionDate":"%s"},
+		{"name":"Task 2","status":"completed","completionDate":"%s"},
+		{"name":"Task 3","status":"completed","completionDate":"%s"},
+		{"name":"Task 4","status":"completed","completionDate":"%s"}
+	]`, jan15Start.Format(time.RFC3339), jan15Mid.Format(time.RFC3339),
+		jan16Start.Format(time.RFC3339), jan16Mid.Format(time.RFC3339))
+
+	tests := []struct {
+		name        string
+		dateFilter  string
+		mockOutputs []string
+		expectCount int
+		expectNames []string
+	}{
+		{
+			name:        "specific date filters next day",
+			dateFilter:  "2024-01-15",
+			mockOutputs: []string{"SUCCESS", mockOutputWithMultipleDays},
+			expectCount: 2,
+			expectNames: []string{"Task 1", "Task 2"},
+		},
+		{
+			name:        "keyword filter includes all",
+			dateFilter:  "today",
+			mockOutputs: []string{"SUCCESS", mockOutputWithMultipleDays},
+			expectCount: 4, // Keywords don't filter by end date
+			expectNames: []string{"Task 1", "Task 2", "Task 3", "Task 4"},
+		},
+		{
+			name:        "different specific date",
+			dateFilter:  "2024-01-16",
+			mockOutputs: []string{"SUCCESS", mockOutputWithMultipleDays},
+			expectCount: 2,
+			expectNames: []string{"Task 3", "Task 4"},
+		},
+		{
+			name:        "range includes both end days",
+			dateFilter:  "2024-01-15..2024-01-16",
+			mockOutputs: []string{"SUCCESS", mockOutputWithMultipleDays},
+			expectCount: 4,
+			expectNames: []string{"Task 1", "Task 2", "Task 3", "Task 4"},
+		},
+		{
+			name:        "range ending before later completions",
+			dateFilter:  "2024-01-14..2024-01-15",
+			mockOutputs: []string{"SUCCESS", mockOutputWithMultipleDays},
+			expectCount: 2,
+			expectNames: []string{"Task 1", "Task 2"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cleanup := setupMockExecutorMulti(tt.mockOutputs, []error{nil, nil})
+			defer cleanup()
+
+			result, err := getCompletedTodos(context.Background(), tt.dateFilter, time.Sunday, time.Local, true)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if len(result) != tt.expectCount {
+				t.Errorf("expected %d todos, got %d", tt.expectCount, len(result))
+				for i, todo := range result {
+					t.Logf("  todo %d: %s (completed: %v)", i, todo.Name, todo.CompletionDate)
+				}
+			}
+
+			for i, expectedName := range tt.expectNames {
+				if i >= len(result) {
+					t.Errorf("missing todo at index %d (expected %q)", i, expectedName)
+					continue
+				}
+				if result[i].Name != expectedName {
+					t.Errorf("todo %d: expected name %q, got %q", i, expectedName, result[i].Name)
+				}
+			}
+		})
+	}
+}
+
+func TestTodoOperations_IgnoreCase(t *testing.T) {
+	tests := []struct {
+		name            string
+		run             func() (OperationResult, error)
+		output          string
+		expectedMessage string
+	}{
+		{
+			name: "delete mixed-case match",
+			run: func() (OperationResult, error) {
+				return deleteTodoFromList(context.Background(), "Inbox", "buy GROCERIES", MatchOptions{IgnoreCase: true})
+			},
+			output:          "SUCCESS: 1",
+			expectedMessage: `To-do "buy GROCERIES" deleted successfully from list "Inbox"!`,
+		},
+		{
+			name: "rename mixed-case match",
+			run: func() (OperationResult, error) {
+				return renameTodoInList(context.Background(), "Inbox", "call MOM", "Call dad", MatchOptions{IgnoreCase: true})
+			},
+			output:          "SUCCESS: 1",
+			expectedMessage: `To-do "call MOM" renamed to "Call dad" in list "Inbox"!`,
+		},
+		{
+			name: "move mixed-case match",
+			run: func() (OperationResult, error) {
+				return moveTodoBetweenLists(context.Background(), "Inbox", "Work", "write REPORT", MatchOptions{IgnoreCase: true})
+			},
+			output:          "SUCCESS: 1",
+			expectedMessage: `To-do "write REPORT" moved successfully from list "Inbox" to list "Work"!`,
+		},
+		{
+			name: "delete with multiple case-insensitive matches",
+			run: func() (OperationResult, error) {
+				return deleteTodoFromList(context.Background(), "Inbox", "task", MatchOptions{IgnoreCase: true})
+			},
+			output:          "SUCCESS: 3",
+			expectedMessage: `To-do "task" deleted successfully from list "Inbox"! (3 to-dos matched; only the first was changed)`,
+		},
+		{
+			name: "rename with multiple case-insensitive matches",
+			run: func() (OperationResult, error) {
+				return renameTodoInList(context.Background(), "Inbox", "task", "Done", MatchOptions{IgnoreCase: true})
+			},
+			output:          "SUCCESS: 2",
+			expectedMessage: `To-do "task" renamed to "Done" in list "Inbox"! (2 to-dos matched; only the first was changed)`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cleanup := setupMockExecutor(tt.output, nil)
+			defer cleanup()
+
+			result, err := tt.run()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !result.Success {
+				t.Errorf("expected success, got failure: %s", result.Message)
+			}
+			if result.Message != tt.expectedMessage {
+				t.Errorf("expected message %q, got %q", tt.expectedMessage, result.Message)
+			}
+
+			script := executor.(*MockExecutor).lastScript()
+			if !strings.Contains(script, ".toLowerCase() === '") {
+				t.Errorf("expected case-insensitive comparison in script, got:\n%s", script)
+			}
+		})
+	}
+}
+
+func TestJxaNameMatch(t *testing.T) {
+	if got := jxaNameMatch("todos[i].name()", "Task", false); got != "todos[i].name() === 'Task'" {
+		t.Errorf("unexpected exact match expression: %s", got)
+	}
+	if got := jxaNameMatch("todos[i].name()", "Task", true); got != "todos[i].name().toLowerCase() === 'Task'.toLowerCase()" {
+		t.Errorf("unexpected case-insensitive match expression: %s", got)
+	}
+}
+
+func TestMatchOptions_JXAMatch(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     MatchOptions
+		expected string
+	}{
+		{"exact", MatchOptions{}, "todos[i].name() === 'Task'"},
+		{"contains", MatchOptions{Contains: true}, "todos[i].name().indexOf('Task') !== -1"},
+		{"contains ignoring case", MatchOptions{Contains: true, IgnoreCase: true}, "todos[i].name().toLowerCase().indexOf('Task'.toLowerCase()) !== -1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.opts.jxaMatch("todos[i].name()", "Task"); got != tt.expected {
+				t.Errorf("expected %s, got %s", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestTodoOperations_All(t *testing.T) {
+	tests := []struct {
+		name            string
+		run             func() (OperationResult, error)
+		output          string
+		expectedMessage string
+		expectedCount   int
+	}{
+		{
+			name: "delete all duplicates",
+			run: func() (OperationResult, error) {
+				return deleteTodoFromList(context.Background(), "Inbox", "Dup", MatchOptions{All: true})
+			},
+			output:          "SUCCESS: 3",
+			expectedMessage: `Deleted 3 to-dos named "Dup" from list "Inbox"!`,
+			expectedCount:   3,
+		},
+		{
+			name: "rename all duplicates",
+			run: func() (OperationResult, error) {
+				return renameTodoInList(context.Background(), "Inbox", "Dup", "Unique", MatchOptions{All: true})
+			},
+			output:          "SUCCESS: 2",
+			expectedMessage: `Renamed 2 to-dos named "Dup" to "Unique" in list "Inbox"!`,
+			expectedCount:   2,
+		},
+		{
+			name: "move all duplicates",
+			run: func() (OperationResult, error) {
+				return moveTodoBetweenLists(context.Background(), "Inbox", "Work", "Dup", MatchOptions{All: true})
+			},
+			output:          "SUCCESS: 2",
+			expectedMessage: `Moved 2 to-dos named "Dup" from list "Inbox" to list "Work"!`,
+			expectedCount:   2,
+		},
+		{
+			name: "single delete still reports one affected",
+			run: func() (OperationResult, error) {
+				return deleteTodoFromList(context.Background(), "Inbox", "Dup", MatchOptions{})
+			},
+			output:          "SUCCESS: 3",
+			expectedMessage: `To-do "Dup" deleted successfully from list "Inbox"! (3 to-dos matched; only the first was changed)`,
+			expectedCount:   1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cleanup := setupMockExecutor(tt.output, nil)
+			defer cleanup()
+
+			result, err := tt.run()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result.Message != tt.expectedMessage {
+				t.Errorf("expected message %q, got %q", tt.expectedMessage, result.Message)
+			}
+			if result.AffectedCount != tt.expectedCount {
+				t.Errorf("expected affected count %d, got %d", tt.expectedCount, result.AffectedCount)
+			}
+
+			script := executor.(*MockExecutor).lastScript()
+			if tt.expectedCount > 1 && !strings.Contains(script, "if (true) {") {
+				t.Errorf("expected script to act on every match, got:\n%s", script)
+			}
+		})
+	}
+}
+
+func TestTodoOperations_Index(t *testing.T) {
+	tests := []struct {
+		name            string
+		run             func() (OperationResult, error)
+		output          string
+		expectedSuccess bool
+		expectedMessage string
+	}{
+		{
+			name: "delete second duplicate",
+			run: func() (OperationResult, error) {
+				return deleteTodoFromList(context.Background(), "Inbox", "Dup", MatchOptions{Index: 1})
+			},
+			output:          "SUCCESS: 2",
+			expectedSuccess: true,
+			expectedMessage: `To-do "Dup" deleted successfully from list "Inbox"!`,
+		},
+		{
+			name: "rename second duplicate",
+			run: func() (OperationResult, error) {
+				return renameTodoInList(context.Background(), "Inbox", "Dup", "Second", MatchOptions{Index: 1})
+			},
+			output:          "SUCCESS: 2",
+			expectedSuccess: true,
+			expectedMessage: `To-do "Dup" renamed to "Second" in list "Inbox"!`,
+		},
+		{
+			name: "delete index out of range",
+			run: func() (OperationResult, error) {
+				return deleteTodoFromList(context.Background(), "Inbox", "Dup", MatchOptions{Index: 5})
+			},
+			output:          "ERROR: Index out of range: 2",
+			expectedSuccess: false,
+			expectedMessage: `ERROR: Index 5 is out of range; only 2 to-dos named "Dup" found in list "Inbox"`,
+		},
+		{
+			name: "move index out of range",
+			run: func() (OperationResult, error) {
+				return moveTodoBetweenLists(context.Background(), "Inbox", "Work", "Dup", MatchOptions{Index: 2})
+			},
+			output:          "ERROR: Index out of range: 2",
+			expectedSuccess: false,
+			expectedMessage: `ERROR: Index 2 is out of range; only 2 to-dos named "Dup" found in list "Inbox"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cleanup := setupMockExecutor(tt.output, nil)
+			defer cleanup()
+
+			result, err := tt.run()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result.Success != tt.expectedSuccess {
+				t.Errorf("expected success %v, got %v", tt.expectedSuccess, result.Success)
+			}
+			if result.Message != tt.expectedMessage {
+				t.Errorf("expected message %q, got %q", tt.expectedMessage, result.Message)
+			}
+		})
+	}
+}
+
+func TestMatchOptions_JXASelect(t *testing.T) {
+	tests := []struct {
+		opts     MatchOptions
+		expected string
+	}{
+		{MatchOptions{}, "matchCount === 0"},
+		{MatchOptions{Index: 1}, "matchCount === 1"},
+		{MatchOptions{All: true}, "true"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.opts.jxaSelect(); got != tt.expected {
+			t.Errorf("jxaSelect(%+v): expected %q, got %q", tt.opts, tt.expected, got)
+		}
+	}
+}
+
+func TestSearchTodos(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    string
+		status   string
+		output   string
+		expected []Todo
+	}{
+		{
+			name:   "matches across multiple lists",
+			query:  "report",
+			output: `[{"name":"Write report","status":"open","area":"Work"},{"name":"Review expense report","status":"open","project":"Finances"},{"name":"Report bug","status":"completed"}]`,
+			expected: []Todo{
+				{Name: "Write report", Status: "open", Area: "Work"},
+				{Name: "Review expense report", Status: "open", Project: "Finances"},
+				{Name: "Report bug", Status: "completed"},
+			},
+		},
+		{
+			name:     "no matches",
+			query:    "nothing",
+			output:   `[]`,
+			expected: []Todo{},
+		},
+		{
+			name:     "with status filter",
+			query:    "report",
+			status:   "open",
+			output:   `[{"name":"Write report","status":"open","area":"Work"},{"name":"Report bug","status":"completed"}]`,
+			expected: []Todo{{Name: "Write report", Status: "open", Area: "Work"}},
+		},
+		{
+			name:     "status filter matches variant spellings",
+			query:    "report",
+			status:   "canceled",
+			output:   `[{"name":"Old report","status":"cancelled"},{"name":"Write report","status":"open"}]`,
+			expected: []Todo{{Name: "Old report", Status: "cancelled"}},
+		},
+		{
+			name:     "variant spelling of the status filter",
+			query:    "report",
+			status:   "cancelled",
+			output:   `[{"name":"Old report","status":"canceled"},{"name":"Write report","status":"open"}]`,
+			expected: []Todo{{Name: "Old report", Status: "canceled"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cleanup := setupMockExecutorMulti([]string{`["Inbox"]`, tt.output}, []error{nil, nil})
+			defer cleanup()
+
+			result, err := searchTodos(context.Background(), tt.query, tt.status, defaultSearchConcurrency)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if len(result) != len(tt.expected) {
+				t.Fatalf("expected %d todos, got %d", len(tt.expected), len(result))
+			}
+			for i, todo := range result {
+				if todo.Name != tt.expected[i].Name || todo.Area != tt.expected[i].Area || todo.Project != tt.expected[i].Project {
+					t.Errorf("todo %d: expected %+v, got %+v", i, tt.expected[i], todo)
+				}
+			}
+
+			script := executor.(*MockExecutor).lastScript()
+			if !strings.Contains(script, "app.lists.byName('Inbox')") {
+				t.Errorf("expected the list to be scanned, got:\n%s", script)
+			}
+			if !strings.Contains(script, "'"+tt.query+"'.toLowerCase()") {
+				t.Errorf("expected lowercased query in script, got:\n%s", script)
+			}
+		})
+	}
+}
+
+func TestSearchTodos_Errors(t *testing.T) {
+	cleanup := setupMockExecutor("ERROR: Things3 got an error", nil)
+	defer cleanup()
+
+	if _, err := searchTodos(context.Background(), "task", "", defaultSearchConcurrency); err == nil {
+		t.Error("expected error but got none")
+	}
+}
+
+// listMockExecutor answers the lists script with its lists, and each list's script with that list's output
+// It's safe for concurrent use, and delays earlier lists longest so scans finish out of order
+type listMockExecutor struct {
+	lists   []string
+	outputs map[string]string
+
+	mu      sync.Mutex
+	scanned []string
+}
+
+var listNamePattern = regexp.MustCompile(`app\.lists\.byName\('([^']*)'\)`)
+
+func (m *listMockExecutor) Execute(name string, args ...string) ([]byte, error) {
+	return m.ExecuteContext(context.Background(), name, args...)
+}
+
+func (m *listMockExecutor) ExecuteContext(ctx context.Context, name string, args ...string) ([]byte, error) {
+	script := args[len(args)-1]
+	match := listNamePattern.FindStringSubmatch(script)
+	if match == nil {
+		lists, _ := json.Marshal(m.lists)
+		return lists, nil
+	}
+
+	listName := match[1]
+	delay := time.Duration(len(m.lists)-slices.Index(m.lists, listName)) * time.Millisecond
+	select {
+	case <-time.After(delay):
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	m.mu.Lock()
+	m.scanned = append(m.scanned, listName)
+	m.mu.Unlock()
+	return []byte(m.outputs[listName]), nil
+}
+
+func TestSearchTodos_Concurrent(t *testing.T) {
+	mock := &listMockExecutor{
+		lists: []string{"Today", "Inbox", "Anytime", "Someday", "Logbook", "Upcoming"},
+		outputs: map[string]string{
+			"Today":    `[{"id":"1","name":"Write report"},{"id":"2","name":"Report bug"}]`,
+			"Inbox":    `[{"id":"3","name":"Report idea"}]`,
+			"Anytime":  `[{"id":"1","name":"Write report"},{"id":"4","name":"Quarterly report"}]`,
+			"Someday":  `[]`,
+			"Logbook":  `[{"id":"5","name":"Old report","status":"completed"}]`,
+			"Upcoming": `ERROR: Things3 got an error`,
+		},
+	}
+
+	originalExecutor := executor
+	originalThingsAvailable := thingsAvailable
+	defer func() {
+		executor = originalExecutor
+		thingsAvailable = originalThingsAvailable
+	}()
+	executor = mock
+	thingsAvailable = true
+
+	t.Run("first error", func(t *testing.T) {
+		if _, err := searchTodos(context.Background(), "report", "", 3); err == nil {
+			t.Error("expected error but got none")
+		}
+	})
+
+	mock.outputs["Upcoming"] = `[{"id":"6","name":"Report due"}]`
+	// Ordered by list name, then by order in the list, keeping the first appearance of each to-do
+	expected := []string{"Write report", "Quarterly report", "Report idea", "Old report", "Report bug", "Report due"}
+
+	for _, concurrency := range []int{1, 2, 4, 100} {
+		t.Run(fmt.Sprintf("concurrency %d", concurrency), func(t *testing.T) {
+			mock.scanned = nil
+
+			result, err := searchTodos(context.Background(), "report", "", concurrency)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			names := make([]string, len(result))
+			for i, todo := range result {
+				names[i] = todo.Name
+			}
+			if !slices.Equal(names, expected) {
+				t.Errorf("expected %v, got %v", expected, names)
+			}
+			if len(mock.scanned) != len(mock.lists) {
+				t.Errorf("expected all %d lists to be scanned, got %v", len(mock.lists), mock.scanned)
+			}
+		})
+	}
+}
+
+func TestFindTodoMatches(t *testing.T) {
+	tests := []struct {
+		name          string
+		output        string
+		expectMatches TodoMatches
+		expectErr     string
+	}{
+		{
+			name:          "matches",
+			output:        `{"selected":["Task"],"total":2}`,
+			expectMatches: TodoMatches{Selected: []string{"Task"}, Total: 2},
+		},
+		{
+			name:      "to-do not found",
+			output:    "ERROR: To-do not found in list",
+			expectErr: `ERROR: To-do "Task" not found in list "Inbox"`,
+		},
+		{
+			name:      "list not found",
+			output:    "ERROR: List not found",
+			expectErr: `ERROR: List "Inbox" not found`,
+		},
+		{
+			name:      "index out of range",
+			output:    "ERROR: Index out of range: 2",
+			expectErr: `ERROR: Index 0 is out of range; only 2 to-dos named "Task" found in list "Inbox"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cleanup := setupMockExecutor(tt.output, nil)
+			defer cleanup()
+
+			matches, err := findTodoMatches(context.Background(), "Inbox", "Task", MatchOptions{})
+			if tt.expectErr != "" {
+				if err == nil || err.Error() != tt.expectErr {
+					t.Errorf("expected error %q, got %v", tt.expectErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if matches.Total != tt.expectMatches.Total || strings.Join(matches.Selected, ",") != strings.Join(tt.expectMatches.Selected, ",") {
+				t.Errorf("expected %+v, got %+v", tt.expectMatches, matches)
+			}
+		})
+	}
+}
+
+func TestLoggingExecutor(t *testing.T) {
+	mock := &MockExecutor{outputs: [][]byte{[]byte("SUCCESS")}, errors: []error{errors.New("boom")}}
+	var log strings.Builder
+	wrapped := &loggingExecutor{next: mock, w: &log}
+
+	script := "\nvar app = Application('Things3');\n"
+	output, err := wrapped.ExecuteContext(context.Background(), "osascript", "-l", "JavaScript", "-e", script)
+
+	if string(output) != "SUCCESS" {
+		t.Errorf("expected output to be forwarded unchanged, got %q", output)
+	}
+	if err == nil || err.Error() != "boom" {
+		t.Errorf("expected error to be forwarded unchanged, got %v", err)
+	}
+	if len(mock.calls) != 1 || mock.lastScript() != script {
+		t.Errorf("expected one call with the original script, got %v", mock.calls)
+	}
+	if expected := "osascript -l JavaScript -e " + script + "\n"; log.String() != expected {
+		t.Errorf("expected log %q, got %q", expected, log.String())
+	}
+}
+
+func TestRetryingExecutor(t *testing.T) {
+	originalDelay := retryBaseDelay
+	retryBaseDelay = time.Millisecond
+	defer func() { retryBaseDelay = originalDelay }()
+
+	transient := errors.New("exit status 1")
+	tests := []struct {
+		name         string
+		retries      int
+		outputs      []string
+		errs         []error
+		expectOutput string
+		expectErr    bool
+		expectCalls  int
+	}{
+		{"fails twice then succeeds", 3, []string{"", "", "SUCCESS"}, []error{transient, transient, nil}, "SUCCESS", false, 3},
+		{"runs out of retries", 1, []string{"", "", "SUCCESS"}, []error{transient, transient, nil}, "", true, 2},
+		{"script errors aren't retried", 3, []string{"ERROR: List not found"}, []error{nil}, "ERROR: List not found", false, 1},
+		{"no retries", 0, []string{""}, []error{transient}, "", true, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			outputs := make([][]byte, len(tt.outputs))
+			for i, output := range tt.outputs {
+				outputs[i] = []byte(output)
+			}
+			mock := &MockExecutor{outputs: outputs, errors: tt.errs}
+			wrapped := &retryingExecutor{next: mock, retries: tt.retries}
+
+			output, err := wrapped.ExecuteContext(context.Background(), "osascript", "-e", "script")
+			if tt.expectErr != (err != nil) {
+				t.Errorf("expected error %v, got %v", tt.expectErr, err)
+			}
+			if string(output) != tt.expectOutput {
+				t.Errorf("expected output %q, got %q", tt.expectOutput, output)
+			}
+			if len(mock.calls) != tt.expectCalls {
+				t.Errorf("expected %d calls, got %d", tt.expectCalls, len(mock.calls))
+			}
+		})
+	}
+}
+
+func TestRetryingExecutor_Canceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	mock := &MockExecutor{outputs: [][]byte{nil}, errors: []error{errors.New("exit status 1")}}
+	wrapped := &retryingExecutor{next: mock, retries: 3}
+
+	if _, err := wrapped.ExecuteContext(ctx, "osascript", "-e", "script"); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+	if len(mock.calls) != 1 {
+		t.Errorf("expected no retries once the context is done, got %d calls", len(mock.calls))
+	}
+}
+
+func TestJXAErrorClassification(t *testing.T) {
+	notRunning := "ERROR: Application isn't running. (-600)"
+	missingList := "ERROR: Can't get object."
+
+	t.Run("get todos with Things not running", func(t *testing.T) {
+		cleanup := setupMockExecutor(notRunning, nil)
+		defer cleanup()
+
+		_, err := getTodosFromList(context.Background(), "Today")
+		if !errors.Is(err, ErrThingsNotRunning) {
+			t.Errorf("expected ErrThingsNotRunning, got %v", err)
+		}
+		if errors.Is(err, ErrListNotFound) {
+			t.Error("did not expect ErrListNotFound")
+		}
+	})
+
+	t.Run("get todos from a missing list", func(t *testing.T) {
+		cleanup := setupMockExecutor(missingList, nil)
+		defer cleanup()
+
+		_, err := getTodosFromList(context.Background(), "Nope")
+		if !errors.Is(err, ErrListNotFound) {
+			t.Errorf("expected ErrListNotFound, got %v", err)
+		}
+		if err == nil || err.Error() != `ERROR: List "Nope" not found` {
+			t.Errorf("unexpected message: %v", err)
+		}
+	})
+
+	t.Run("delete with Things not running", func(t *testing.T) {
+		cleanup := setupMockExecutor("ERROR: List not found: Application isn't running.", nil)
+		defer cleanup()
+
+		_, err := deleteTodoFromList(context.Background(), "Inbox", "Task", MatchOptions{})
+		if !errors.Is(err, ErrThingsNotRunning) {
+			t.Errorf("expected ErrThingsNotRunning, got %v", err)
+		}
+	})
+
+	t.Run("delete from a missing list", func(t *testing.T) {
+		cleanup := setupMockExecutor("ERROR: List not found: Can't get object.", nil)
+		defer cleanup()
+
+		result, err := deleteTodoFromList(context.Background(), "Nope", "Task", MatchOptions{})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result.Success || result.Message != `ERROR: List "Nope" not found` {
+			t.Errorf("expected list not found result, got %+v", result)
+		}
+	})
+
+	t.Run("lookup of a missing to-do", func(t *testing.T) {
+		cleanup := setupMockExecutor("ERROR: To-do not found in list", nil)
+		defer cleanup()
+
+		_, err := findTodoMatches(context.Background(), "Inbox", "Task", MatchOptions{})
+		if !errors.Is(err, ErrTodoNotFound) {
+			t.Errorf("expected ErrTodoNotFound, got %v", err)
+		}
+	})
+
+	t.Run("by id with Things not running", func(t *testing.T) {
+		cleanup := setupMockExecutor("ERROR: To-do not found: Application isn't running.", nil)
+		defer cleanup()
+
+		_, err := completeTodoByID(context.Background(), "ABC123")
+		if !errors.Is(err, ErrThingsNotRunning) {
+			t.Errorf("expected ErrThingsNotRunning, got %v", err)
+		}
+	})
+}
+
+func TestEnsureThingsAvailable(t *testing.T) {
+	tests := []struct {
+		name        string
+		output      string
+		err         error
+		expectErr   bool
+		expectCalls int
+	}{
+		{"installed", "Things3", nil, false, 2},
+		{"not installed", "execution error: Error: Error: Application can't be found. (-2700)", errors.New("exit status 1"), true, 1},
+		{"can't get application", "Can't get application \"Things3\"", nil, true, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cleanup := setupMockExecutorMulti([]string{tt.output, "[]"}, []error{tt.err, nil})
+			defer cleanup()
+			thingsAvailable = false
+
+			_, err := getTodosFromList(context.Background(), "Today")
+
+			if tt.expectErr {
+				if err == nil || err.Error() != "ERROR: Things 3 does not appear to be installed or running" {
+					t.Errorf("expected friendly error, got %v", err)
+				}
+				if !errors.Is(err, ErrThingsNotRunning) {
+					t.Error("expected error to match ErrThingsNotRunning")
+				}
+			} else if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			mock := executor.(*MockExecutor)
+			if len(mock.calls) != tt.expectCalls {
+				t.Fatalf("expected %d calls, got %d", tt.expectCalls, len(mock.calls))
+			}
+			if script := mock.calls[0][len(mock.calls[0])-1]; !strings.Contains(script, "Application('Things3').name()") {
+				t.Errorf("expected the first call to be the availability check, got %q", script)
+			}
+			if thingsAvailable == tt.expectErr {
+				t.Errorf("expected thingsAvailable to be %v", !tt.expectErr)
+			}
+		})
+	}
+}
+
+func TestGetTodos_TagWithComma(t *testing.T) {
+	cleanup := setupMockExecutor(`[{"name":"Task","status":"open","tagNames":["Work, Urgent"]}]`, nil)
+	defer cleanup()
+
+	todos, err := getTodosFromList(context.Background(), "Work")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(todos) != 1 || len(todos[0].TagNames) != 1 || todos[0].TagNames[0] != "Work, Urgent" {
+		t.Fatalf("expected a single tag \"Work, Urgent\", got %+v", todos)
+	}
+
+	script := executor.(*MockExecutor).lastScript()
+	if !strings.Contains(script, "todo.tags().map(") {
+		t.Error("expected script to read tag names from the tag objects")
+	}
+	if strings.Contains(script, ".split(',')") {
+		t.Error("expected script not to split tag names on commas")
+	}
+
+	// Importing the exported to-do should keep the tag whole
+	line, err := formatTodoAsJSONL(todos[0])
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	cleanupImport := setupMockExecutor(`[{"success":true}]`, nil)
+	defer cleanupImport()
+	if _, _, err := importTodos(context.Background(), strings.NewReader(line), "Work", true, &strings.Builder{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if script := executor.(*MockExecutor).lastScript(); !strings.Contains(script, `"tags":["Work, Urgent"]`) {
+		t.Errorf("expected the tag to round-trip as one tag, got script:\n%s", script)
+	}
+}
+
+func TestSetTodoTags(t *testing.T) {
+	tests := []struct {
+		name          string
+		tags          []string
+		replace       bool
+		outputs       []string
+		expectCalls   int
+		expectSuccess bool
+		expectMessage string
+		expectScript  string
+	}{
+		{
+			name:          "add unions with existing tags",
+			tags:          []string{"Work", "Urgent"},
+			outputs:       []string{`["Home"]`, "SUCCESS"},
+			expectCalls:   2,
+			expectSuccess: true,
+			expectMessage: `Tags of to-do "Task" in list "Inbox" set to "Home", "Work", "Urgent"!`,
+			expectScript:  "todo.tagNames = 'Home, Work, Urgent';",
+		},
+		{
+			name:          "add skips tags already present",
+			tags:          []string{"Home", "Work", "Work"},
+			outputs:       []string{`["Home","Work"]`, "SUCCESS"},
+			expectCalls:   2,
+			expectSuccess: true,
+			expectScript:  "todo.tagNames = 'Home, Work';",
+		},
+		{
+			name:          "replace doesn't read existing tags",
+			tags:          []string{"Mom's", "Mom's"},
+			replace:       true,
+			outputs:       []string{"SUCCESS"},
+			expectCalls:   1,
+			expectSuccess: true,
+			expectScript:  `todo.tagNames = 'Mom\'s';`,
+		},
+		{
+			name:          "replace with no tags clears them",
+			replace:       true,
+			outputs:       []string{"SUCCESS"},
+			expectCalls:   1,
+			expectSuccess: true,
+			expectMessage: `Tags of to-do "Task" in list "Inbox" cleared!`,
+			expectScript:  "todo.tagNames = '';",
+		},
+		{
+			name:          "tag with a comma is attached whole",
+			tags:          []string{"Home, Office"},
+			replace:       true,
+			outputs:       []string{"SUCCESS"},
+			expectCalls:   1,
+			expectSuccess: true,
+			expectScript:  "todo.tags.push(app.tags.byName('Home, Office'));",
+		},
+		{
+			name:          "to-do not found",
+			tags:          []string{"Work"},
+			outputs:       []string{"ERROR: To-do not found in list"},
+			expectCalls:   1,
+			expectMessage: `ERROR: To-do "Task" not found in list "Inbox"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cleanup := setupMockExecutorMulti(tt.outputs, make([]error, len(tt.outputs)))
+			defer cleanup()
+
+			result, err := setTodoTags(context.Background(), "Inbox", "Task", tt.tags, tt.replace)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result.Success != tt.expectSuccess {
+				t.Errorf("expected success %v, got %v (%s)", tt.expectSuccess, result.Success, result.Message)
+			}
+			if tt.expectMessage != "" && result.Message != tt.expectMessage {
+				t.Errorf("expected message %q, got %q", tt.expectMessage, result.Message)
+			}
+
+			mock := executor.(*MockExecutor)
+			if len(mock.calls) != tt.expectCalls {
+				t.Errorf("expected %d calls, got %d", tt.expectCalls, len(mock.calls))
+			}
+			if tt.expectScript != "" && !strings.Contains(mock.lastScript(), tt.expectScript) {
+				t.Errorf("expected script to contain %q:\n%s", tt.expectScript, mock.lastScript())
+			}
+		})
+	}
+}
+
+func TestRemoveTodoTags(t *testing.T) {
+	tests := []struct {
+		name          string
+		tags          []string
+		outputs       []string
+		expectCalls   int
+		expectSuccess bool
+		expectMessage string
+		expectScript  string
+	}{
+		{
+			name:          "remove one of several tags",
+			tags:          []string{"Work"},
+			outputs:       []string{`["Home","Work","Urgent"]`, "SUCCESS"},
+			expectCalls:   2,
+			expectSuccess: true,
+			expectMessage: `Removed "Work" from to-do "Task" in list "Inbox"!`,
+			expectScript:  "todo.tagNames = 'Home, Urgent';",
+		},
+		{
+			name:          "remove the last tag",
+			tags:          []string{"Home"},
+			outputs:       []string{`["Home"]`, "SUCCESS"},
+			expectCalls:   2,
+			expectSuccess: true,
+			expectScript:  "todo.tagNames = '';",
+		},
+		{
+			name:          "remove a tag that isn't present",
+			tags:          []string{"Errands"},
+			outputs:       []string{`["Home","Work"]`},
+			expectCalls:   1,
+			expectSuccess: true,
+			expectMessage: `To-do "Task" in list "Inbox" has none of those tags; nothing to remove`,
+		},
+		{
+			name:          "list not found",
+			tags:          []string{"Work"},
+			outputs:       []string{"ERROR: List not found: Can't get object."},
+			expectCalls:   1,
+			expectMessage: `ERROR: List "Inbox" not found`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cleanup := setupMockExecutorMulti(tt.outputs, make([]error, len(tt.outputs)))
+			defer cleanup()
+
+			result, err := removeTodoTags(context.Background(), "Inbox", "Task", tt.tags)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result.Success != tt.expectSuccess {
+				t.Errorf("expected success %v, got %v (%s)", tt.expectSuccess, result.Success, result.Message)
+			}
+			if tt.expectMessage != "" && result.Message != tt.expectMessage {
+				t.Errorf("expected message %q, got %q", tt.expectMessage, result.Message)
+			}
+
+			mock := executor.(*MockExecutor)
+			if len(mock.calls) != tt.expectCalls {
+				t.Errorf("expected %d calls, got %d", tt.expectCalls, len(mock.calls))
+			}
+			if tt.expectScript != "" && !strings.Contains(mock.lastScript(), tt.expectScript) {
+				t.Errorf("expected script to contain %q:\n%s", tt.expectScript, mock.lastScript())
+			}
+		})
+	}
+}
+
+func TestEditTodo(t *testing.T) {
+	newName := "Renamed"
+	due := time.Date(2024, 2, 1, 0, 0, 0, 0, time.Local)
+	noDue := time.Time{}
+	notes := "Call back"
+	noNotes := ""
+	today := "today"
+	tomorrow := "tomorrow"
+	someday := "someday"
+	day := "2024-03-05"
+	tags := []string{"Work", "Home, Garden"}
+	authToken = "secret"
+	defer func() { authToken = "" }()
+
+	// Every assignment editTodo can make, so each case can check the ones it mustn't
+	allAssignments := []string{"todo.name =", "todo.dueDate =", "todo.notes =", "todo.tagNames =", "app.schedule(", "app.move("}
+
+	tests := []struct {
+		name            string
+		changes         TodoChanges
+		expectScript    []string
+		expectedMessage string
+	}{
+		{
+			name:            "notes only",
+			changes:         TodoChanges{Notes: &notes},
+			expectScript:    []string{"todo.notes = 'Call back';"},
+			expectedMessage: `Changed the notes of to-do "Task" in list "Inbox"!`,
+		},
+		{
+			name:            "clear notes",
+			changes:         TodoChanges{Notes: &noNotes},
+			expectScript:    []string{"todo.notes = '';"},
+			expectedMessage: `Changed the notes of to-do "Task" in list "Inbox"!`,
+		},
+		{
+			name:            "due date",
+			changes:         TodoChanges{Due: &due},
+			expectScript:    []string{"todo.dueDate = new Date(2024, 1, 1);"},
+			expectedMessage: `Changed the due date of to-do "Task" in list "Inbox"!`,
+		},
 		{
-			name:        "no filters",
-			dateFilter:  "today",
-			expectCount: 3,
+			name:            "clear due date",
+			changes:         TodoChanges{Due: &noDue},
+			expectScript:    []string{"todo.dueDate = null;"},
+			expectedMessage: `Changed the due date of to-do "Task" in list "Inbox"!`,
 		},
 		{
-			name:        "filter by area",
-			dateFilter:  "today",
-			areaFilter:  "Work",
-			expectCount: 2,
+			name:            "schedule for tomorrow",
+			changes:         TodoChanges{When: &tomorrow},
+			expectScript:    []string{"when.setDate(when.getDate() + 1); app.schedule(todo, {for: when});"},
+			expectedMessage: `Changed the schedule of to-do "Task" in list "Inbox"!`,
 		},
 		{
-			name:          "filter by project",
-			dateFilter:    "today",
-			projectFilter: "Project A",
-			expectCount:   1,
+			name:         "schedule for today",
+			changes:      TodoChanges{When: &today},
+			expectScript: []string{"app.schedule(todo, {for: new Date()});"},
 		},
 		{
-			name:          "filter by both area and project",
-			dateFilter:    "today",
-			areaFilter:    "Work",
-			projectFilter: "Project B",
-			expectCount:   1,
+			name:            "schedule for someday",
+			changes:         TodoChanges{When: &someday},
+			expectScript:    []string{"encodeURIComponent(todo.id()) + '&when=' + encodeURIComponent('someday'));"},
+			expectedMessage: `Changed the schedule of to-do "Task" in list "Inbox"!`,
 		},
 		{
-			name:        "no matches",
-			dateFilter:  "today",
-			areaFilter:  "NonExistent",
-			expectCount: 0,
+			name:         "schedule for a date",
+			changes:      TodoChanges{When: &day},
+			expectScript: []string{"app.schedule(todo, {for: new Date(2024, 2, 5)});"},
+		},
+		{
+			name:         "tags",
+			changes:      TodoChanges{Tags: &tags},
+			expectScript: []string{"todo.tagNames = 'Work';", "todo.tags.push(app.tags.byName('Home, Garden'));"},
+		},
+		{
+			name:    "several fields",
+			changes: TodoChanges{Name: &newName, Due: &due, Notes: &notes},
+			expectScript: []string{
+				"todo.name = 'Renamed';",
+				"todo.dueDate = new Date(2024, 1, 1);",
+				"todo.notes = 'Call back';",
+			},
+			expectedMessage: `Changed the name, due date, notes of to-do "Task" in list "Inbox"!`,
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			// Mock both logCompletedNow() and getTodosFromListWithFilter() calls
-			cleanup := setupMockExecutorMulti([]string{"SUCCESS", mockOutput}, []error{nil, nil})
+			cleanup := setupMockExecutor("SUCCESS", nil)
 			defer cleanup()
 
-			result, err := getCompletedTodosFiltered(tt.dateFilter, tt.areaFilter, tt.projectFilter)
+			result, err := editTodo(context.Background(), "Inbox", "Task", tt.changes)
 			if err != nil {
-				t.Errorf("unexpected error: %v", err)
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !result.Success {
+				t.Fatalf("expected success, got %q", result.Message)
+			}
+			if tt.expectedMessage != "" && result.Message != tt.expectedMessage {
+				t.Errorf("expected message %q, got %q", tt.expectedMessage, result.Message)
 			}
 
-			if len(result) != tt.expectCount {
-				t.Errorf("expected %d todos, got %d", tt.expectCount, len(result))
+			script := executor.(*MockExecutor).lastScript()
+			for _, expected := range tt.expectScript {
+				if !strings.Contains(script, expected) {
+					t.Errorf("expected script to contain %q, got:\n%s", expected, script)
+				}
+			}
+			for _, assignment := range allAssignments {
+				expected := slices.ContainsFunc(tt.expectScript, func(s string) bool { return strings.Contains(s, assignment) })
+				if !expected && strings.Contains(script, assignment) {
+					t.Errorf("expected no %q for unset fields, got:\n%s", assignment, script)
+				}
 			}
 		})
 	}
 }
 
-func TestGetTodosWithRichData(t *testing.T) {
-	mockOutput := `[
-		{
-			"name":"Task with all fields",
-			"notes":"Important notes",
-			"status":"open",
-			"creationDate":"2024-01-10T10:00:00Z",
-			"dueDate":"2024-01-20T00:00:00Z",
-			"tagNames":["Work","Important"],
-			"area":"Projects",
-			"project":"Q1 Goals"
-		},
-		{
-			"name":"Simple task",
-			"status":"open"
+func TestEditTodo_Errors(t *testing.T) {
+	notes := "Call back"
+
+	t.Run("nothing to change", func(t *testing.T) {
+		cleanup := setupMockExecutor("SUCCESS", nil)
+		defer cleanup()
+
+		result, err := editTodo(context.Background(), "Inbox", "Task", TodoChanges{})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
 		}
-	]`
+		if result.Success {
+			t.Error("expected failure")
+		}
+		if calls := executor.(*MockExecutor).calls; len(calls) != 0 {
+			t.Errorf("expected no calls, got %d", len(calls))
+		}
+	})
 
-	cleanup := setupMockExecutor(mockOutput, nil)
-	defer cleanup()
+	t.Run("to-do not found", func(t *testing.T) {
+		cleanup := setupMockExecutor("ERROR: To-do not found in list", nil)
+		defer cleanup()
 
-	todos, err := getTodosFromList("Work")
-	if err != nil {
-		t.Fatalf("unexpected error: %v", err)
-	}
+		result, err := editTodo(context.Background(), "Inbox", "Task", TodoChanges{Notes: &notes})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result.Success || result.Message != `ERROR: To-do "Task" not found in list "Inbox"` {
+			t.Errorf("expected not found failure, got %+v", result)
+		}
+	})
 
-	if len(todos) != 2 {
-		t.Fatalf("expected 2 todos, got %d", len(todos))
-	}
+	t.Run("things not running", func(t *testing.T) {
+		cleanup := setupMockExecutor("ERROR: Application isn't running.", nil)
+		defer cleanup()
 
-	// Test rich data parsing
-	richTodo := todos[0]
-	if richTodo.Name != "Task with all fields" {
-		t.Errorf("expected name 'Task with all fields', got %q", richTodo.Name)
-	}
-	if richTodo.Notes != "Important notes" {
-		t.Errorf("expected notes 'Important notes', got %q", richTodo.Notes)
-	}
-	if richTodo.Area != "Projects" {
-		t.Errorf("expected area 'Projects', got %q", richTodo.Area)
-	}
-	if richTodo.Project != "Q1 Goals" {
-		t.Errorf("expected project 'Q1 Goals', got %q", richTodo.Project)
-	}
-	if len(richTodo.TagNames) != 2 {
-		t.Errorf("expected 2 tags, got %d", len(richTodo.TagNames))
-	}
-	if richTodo.DueDate == nil {
-		t.Error("expected dueDate to be set")
-	}
-	if richTodo.CreationDate == nil {
-		t.Error("expected creationDate to be set")
-	}
+		if _, err := editTodo(context.Background(), "Inbox", "Task", TodoChanges{Notes: &notes}); !errors.Is(err, ErrThingsNotRunning) {
+			t.Errorf("expected ErrThingsNotRunning, got %v", err)
+		}
+	})
+}
 
-	// Test simple todo
-	simpleTodo := todos[1]
-	if simpleTodo.Name != "Simple task" {
-		t.Errorf("expected name 'Simple task', got %q", simpleTodo.Name)
-	}
-	if simpleTodo.Notes != "" {
-		t.Error("expected empty notes")
+func TestParseWhen(t *testing.T) {
+	tests := []struct {
+		value     string
+		expected  string
+		expectErr bool
+	}{
+		{"today", "today", false},
+		{"Tomorrow", "tomorrow", false},
+		{"someday", "someday", false},
+		{"2024-03-05", "2024-03-05", false},
+		{"evening", "", true},
+		{"03/05/2024", "", true},
 	}
-	if len(simpleTodo.TagNames) != 0 {
-		t.Error("expected empty tags")
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			result, err := parseWhen(tt.value)
+			if tt.expectErr {
+				if err == nil {
+					t.Error("expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, result)
+			}
+		})
 	}
 }
 
-func TestParseDateFilter(t *testing.T) {
+func TestTodoChanges_JXAAssignments(t *testing.T) {
+	empty := ""
+	noDue := time.Time{}
+	noTags := []string{}
+	notes := "Call back"
+
 	tests := []struct {
-		name          string
-		filter        string
-		expectError   bool
-		expectSingle  bool
-		validateStart func(time.Time) bool
+		name        string
+		changes     TodoChanges
+		expected    []string
+		expectedErr bool
 	}{
 		{
-			name:         "keyword: today",
-			filter:       "today",
-			expectError:  false,
-			expectSingle: false,
-			validateStart: func(t time.Time) bool {
-				// Should be midnight today
-				now := time.Now()
-				expected := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
-				return t.Equal(expected)
-			},
-		},
-		{
-			name:         "keyword: this week",
-			filter:       "this week",
-			expectError:  false,
-			expectSingle: false,
-			validateStart: func(t time.Time) bool {
-				// Should be most recent Sunday
-				return t.Weekday() == time.Sunday && t.Hour() == 0 && t.Minute() == 0
-			},
-		},
-		{
-			name:         "keyword: this month",
-			filter:       "this month",
-			expectError:  false,
-			expectSingle: false,
-			validateStart: func(t time.Time) bool {
-				// Should be first day of current month
-				now := time.Now()
-				return t.Year() == now.Year() && t.Month() == now.Month() && t.Day() == 1
-			},
-		},
-		{
-			name:         "YYYY-MM-DD date",
-			filter:       "2024-01-15",
-			expectError:  false,
-			expectSingle: true,
-			validateStart: func(t time.Time) bool {
-				expected := time.Date(2024, 1, 15, 0, 0, 0, 0, time.Local)
-				return t.Equal(expected)
-			},
-		},
-		{
-			name:         "YYYY-MM-DD different date",
-			filter:       "2023-12-25",
-			expectError:  false,
-			expectSingle: true,
-			validateStart: func(t time.Time) bool {
-				expected := time.Date(2023, 12, 25, 0, 0, 0, 0, time.Local)
-				return t.Equal(expected)
-			},
+			name:     "nil fields produce no assignments",
+			changes:  TodoChanges{},
+			expected: nil,
 		},
 		{
-			name:        "invalid keyword",
-			filter:      "yesterday",
-			expectError: true,
+			name:     "only set fields are assigned",
+			changes:  TodoChanges{Notes: &notes},
+			expected: []string{"todo.notes = 'Call back';"},
 		},
 		{
-			name:        "invalid date format DD-MM-YYYY",
-			filter:      "15-01-2024",
-			expectError: true,
+			name:     "empty notes clear them",
+			changes:  TodoChanges{Notes: &empty},
+			expected: []string{"todo.notes = '';"},
 		},
 		{
-			name:        "incomplete date",
-			filter:      "2024-01",
-			expectError: true,
+			name:     "zero due date clears it",
+			changes:  TodoChanges{Due: &noDue},
+			expected: []string{"todo.dueDate = null;"},
 		},
 		{
-			name:        "malformed date",
-			filter:      "2024-13-01",
-			expectError: true,
+			name:     "empty tags clear them",
+			changes:  TodoChanges{Tags: &noTags},
+			expected: []string{"todo.tagNames = '';"},
 		},
 		{
-			name:        "invalid date",
-			filter:      "not-a-date",
-			expectError: true,
+			name:        "invalid when",
+			changes:     TodoChanges{When: &empty},
+			expectedErr: true,
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			startTime, isSingleDay, err := parseDateFilter(tt.filter)
-
-			if tt.expectError {
+			assignments, changed, err := tt.changes.jxaAssignments()
+			if tt.expectedErr {
 				if err == nil {
 					t.Error("expected error but got none")
 				}
 				return
 			}
-
 			if err != nil {
-				t.Errorf("unexpected error: %v", err)
-				return
+				t.Fatalf("unexpected error: %v", err)
 			}
-
-			if isSingleDay != tt.expectSingle {
-				t.Errorf("expected isSingleDay=%v, got %v", tt.expectSingle, isSingleDay)
+			if !slices.Equal(assignments, tt.expected) {
+				t.Errorf("expected assignments %q, got %q", tt.expected, assignments)
 			}
-
-			if tt.validateStart != nil && !tt.validateStart(startTime) {
-				t.Errorf("start time validation failed for %v", startTime)
+			if len(changed) != len(assignments) {
+				t.Errorf("expected a label per assignment, got %q", changed)
 			}
 		})
 	}
 }
 
-func TestGetCompletedTodos_SingleDayFiltering(t *testing.T) {
-	// Test that single-day filtering properly excludes todos from the next day
-	// Use Local timezone to match the filtering logic in getCompletedTodos
-	jan15Start := time.Date(2024, 1, 15, 0, 0, 0, 0, time.Local)
-	jan15Mid := time.Date(2024, 1, 15, 12, 0, 0, 0, time.Local)
-	jan16Start := time.Date(2024, 1, 16, 0, 0, 1, 0, time.Local)
-	jan16Mid := time.Date(2024, 1, 16, 12, 0, 0, 0, time.Local)
+func TestTodoChanges_Reminder(t *testing.T) {
+	setNow(t, time.Date(2024, 3, 4, 10, 0, 0, 0, time.Local))
+	authToken = "secret"
+	defer func() { authToken = "" }()
 
-	mockOutputWithMultipleDays := fmt.Sprintf(`[
-		{"name":"Task 1","status":"completed","completionDate":"%s"},
-		{"name":"Task 2","status":"completed","completionDate":"%s"},
-		{"name":"Task 3","status":"completed","completionDate":"%s"},
-		{"name":"Task 4","status":"completed","completionDate":"%s"}
-	]`, jan15Start.Format(time.RFC3339), jan15Mid.Format(time.RFC3339),
-		jan16Start.Format(time.RFC3339), jan16Mid.Format(time.RFC3339))
+	today := "today"
+	tomorrow := "tomorrow"
+	day := "2024-03-05"
+	someday := "someday"
+	reminder := "18:30"
+	badReminder := "6pm"
+
+	// Reminders are set by an update through the URL scheme, scheduling for the day and time at once
+	update := func(when string) string {
+		return "var currentApp = Application.currentApplication(); currentApp.includeStandardAdditions = true; " +
+			"currentApp.openLocation('things:///update?auth-token=' + encodeURIComponent('secret')" +
+			" + '&id=' + encodeURIComponent(todo.id()) + '&when=' + encodeURIComponent('" + when + "'));"
+	}
 
 	tests := []struct {
 		name        string
-		dateFilter  string
-		mockOutputs []string
-		expectCount int
-		expectNames []string
+		changes     TodoChanges
+		expected    string
+		expectedErr string
 	}{
 		{
-			name:        "specific date filters next day",
-			dateFilter:  "2024-01-15",
-			mockOutputs: []string{"SUCCESS", mockOutputWithMultipleDays},
-			expectCount: 2,
-			expectNames: []string{"Task 1", "Task 2"},
+			name:     "today",
+			changes:  TodoChanges{When: &today, Reminder: &reminder},
+			expected: update("2024-03-04@18:30"),
 		},
 		{
-			name:        "keyword filter includes all",
-			dateFilter:  "today",
-			mockOutputs: []string{"SUCCESS", mockOutputWithMultipleDays},
-			expectCount: 4, // Keywords don't filter by end date
-			expectNames: []string{"Task 1", "Task 2", "Task 3", "Task 4"},
+			name:     "tomorrow",
+			changes:  TodoChanges{When: &tomorrow, Reminder: &reminder},
+			expected: update("2024-03-05@18:30"),
 		},
 		{
-			name:        "different specific date",
-			dateFilter:  "2024-01-16",
-			mockOutputs: []string{"SUCCESS", mockOutputWithMultipleDays},
-			expectCount: 2,
-			expectNames: []string{"Task 3", "Task 4"},
+			name:     "date",
+			changes:  TodoChanges{When: &day, Reminder: &reminder},
+			expected: update("2024-03-05@18:30"),
+		},
+		{
+			name:        "invalid time",
+			changes:     TodoChanges{When: &today, Reminder: &badReminder},
+			expectedErr: "ERROR: --reminder must be a time in HH:MM format",
+		},
+		{
+			name:        "someday",
+			changes:     TodoChanges{When: &someday, Reminder: &reminder},
+			expectedErr: "ERROR: --reminder can't be used with --when someday",
+		},
+		{
+			name:        "without when",
+			changes:     TodoChanges{Reminder: &reminder},
+			expectedErr: "ERROR: --reminder requires --when today, tomorrow, or a date",
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			cleanup := setupMockExecutorMulti(tt.mockOutputs, []error{nil, nil})
-			defer cleanup()
-
-			result, err := getCompletedTodos(tt.dateFilter)
+			assignments, changed, err := tt.changes.jxaAssignments()
+			if tt.expectedErr != "" {
+				if err == nil || err.Error() != tt.expectedErr {
+					t.Errorf("expected error %q, got %v", tt.expectedErr, err)
+				}
+				return
+			}
 			if err != nil {
 				t.Fatalf("unexpected error: %v", err)
 			}
+			if !slices.Equal(assignments, []string{tt.expected}) {
+				t.Errorf("expected %q, got %q", tt.expected, assignments)
+			}
+			if !slices.Equal(changed, []string{"schedule", "reminder"}) {
+				t.Errorf("expected schedule and reminder to change, got %q", changed)
+			}
+		})
+	}
+}
 
-			if len(result) != tt.expectCount {
-				t.Errorf("expected %d todos, got %d", tt.expectCount, len(result))
-				for i, todo := range result {
-					t.Logf("  todo %d: %s (completed: %v)", i, todo.Name, todo.CompletionDate)
-				}
+func TestTodoChanges_NoAuthToken(t *testing.T) {
+	today := "today"
+	someday := "someday"
+	reminder := "09:00"
+
+	tests := []struct {
+		name    string
+		changes TodoChanges
+	}{
+		{"reminder", TodoChanges{When: &today, Reminder: &reminder}},
+		{"someday", TodoChanges{When: &someday}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := tt.changes.jxaAssignments(); err == nil || err.Error() != errNoAuthToken {
+				t.Errorf("expected error %q, got %v", errNoAuthToken, err)
 			}
+		})
+	}
+}
 
-			for i, expectedName := range tt.expectNames {
-				if i >= len(result) {
-					t.Errorf("missing todo at index %d (expected %q)", i, expectedName)
-					continue
-				}
-				if result[i].Name != expectedName {
-					t.Errorf("todo %d: expected name %q, got %q", i, expectedName, result[i].Name)
-				}
+func TestParseMatchResult(t *testing.T) {
+	tests := []struct {
+		output        string
+		expectedCount int
+		expectedIDs   []string
+	}{
+		{`SUCCESS: 2 ["A","B"]`, 2, []string{"A", "B"}},
+		{`SUCCESS: 1 []`, 1, []string{}},
+		{"SUCCESS: 3", 3, nil},
+		{"SUCCESS", 1, nil},
+		{"SUCCESS: 1 not-json", 1, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.output, func(t *testing.T) {
+			count, ids := parseMatchResult(tt.output)
+			if count != tt.expectedCount {
+				t.Errorf("expected count %d, got %d", tt.expectedCount, count)
+			}
+			if !slices.Equal(ids, tt.expectedIDs) {
+				t.Errorf("expected ids %q, got %q", tt.expectedIDs, ids)
 			}
 		})
 	}
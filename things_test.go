@@ -2,9 +2,12 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"io"
 	"os"
+	"path/filepath"
+	"slices"
 	"strings"
 	"testing"
 	"time"
@@ -20,6 +23,7 @@ func setupMockExecutorIntegration(output string, err error) func() {
 // setupMockExecutorIntegrationMulti sets up a mock executor with multiple outputs for testing and disables os.Exit
 func setupMockExecutorIntegrationMulti(outputs []string, errors []error) func() {
 	originalExecutor := executor
+	originalThingsAvailable := thingsAvailable
 	originalOsExiter := cli.OsExiter
 	originalStderr := os.Stderr
 
@@ -32,6 +36,8 @@ func setupMockExecutorIntegrationMulti(outputs []string, errors []error) func()
 		outputs: byteOutputs,
 		errors:  errors,
 	}
+	// Skip the pre-flight check so each mock output answers the script under test
+	thingsAvailable = true
 
 	// Override OsExiter to prevent actual exit during tests
 	cli.OsExiter = func(code int) {
@@ -48,258 +54,22 @@ func setupMockExecutorIntegrationMulti(outputs []string, errors []error) func()
 
 	return func() {
 		executor = originalExecutor
+		thingsAvailable = originalThingsAvailable
 		cli.OsExiter = originalOsExiter
 		os.Stderr = originalStderr
 	}
 }
 
-// createTestApp creates the CLI app for testing - same as main.go but testable
+// createTestApp creates the CLI app for testing with output discarded
 func createTestApp() *cli.Command {
 	return createTestAppWithWriters(io.Discard, io.Discard)
 }
 
 // createTestAppWithWriters creates the CLI app with custom writers for suppressing output
 func createTestAppWithWriters(writer, errWriter io.Writer) *cli.Command {
-	var listName string
-	var todoName string
-	var fromList string
-	var toList string
-	var tags string
-	var newName string
-	var dateFilter string
-	var areaFilter string
-	var projectFilter string
-	var jsonl bool
-
-	app := &cli.Command{
-		Name:    "things",
-		Version: "test",
-		Usage:   "Interact with Things.app from the command line.",
-		Commands: []*cli.Command{
-			{
-				Name:    "show",
-				Usage:   "Show to-dos from a specified list",
-				Aliases: []string{"s"},
-				Flags: []cli.Flag{
-					&cli.StringFlag{
-						Name:        "list",
-						Aliases:     []string{"l"},
-						Usage:       "show to-dos from the specified `list`",
-						Required:    true,
-						Destination: &listName,
-					},
-					&cli.BoolFlag{
-						Name:        "jsonl",
-						Usage:       "output todos in JSONL format",
-						Destination: &jsonl,
-					},
-				},
-				Action: func(ctx context.Context, cmd *cli.Command) error {
-					todos, err := getTodosFromList(listName)
-					if err != nil {
-						if strings.HasPrefix(err.Error(), "ERROR:") {
-							return cli.Exit(err.Error()+"\nUse `things list` to see available lists.", 1)
-						}
-						return err
-					}
-					_ = todos
-					_ = jsonl
-					return nil
-				},
-			},
-			{
-				Name:    "add",
-				Usage:   "Add a new todo to a specified list",
-				Aliases: []string{"a"},
-				Flags: []cli.Flag{
-					&cli.StringFlag{
-						Name:        "list",
-						Aliases:     []string{"l"},
-						Usage:       "the `list` to add the to-do to",
-						Value:       "inbox",
-						Destination: &listName,
-					},
-					&cli.StringFlag{
-						Name:        "name",
-						Aliases:     []string{"n"},
-						Usage:       "the `to-do name` to add",
-						Required:    true,
-						Destination: &todoName,
-					},
-					&cli.StringFlag{
-						Name:        "tags",
-						Aliases:     []string{"t"},
-						Usage:       "comma-separated `tags` to add to the to-do (e.g., \"Home, Work\")",
-						Destination: &tags,
-					},
-				},
-				Action: func(ctx context.Context, cmd *cli.Command) error {
-					result, err := addTodoToList(listName, todoName, tags)
-					if err != nil {
-						return err
-					}
-					if !result.Success {
-						return cli.Exit(result.Message, 1)
-					}
-					return nil
-				},
-			},
-			{
-				Name:    "delete",
-				Usage:   "Delete a todo by name from a specified list",
-				Aliases: []string{"d"},
-				Flags: []cli.Flag{
-					&cli.StringFlag{
-						Name:        "list",
-						Aliases:     []string{"l"},
-						Usage:       "the `list` to search for the to-do in",
-						Required:    true,
-						Destination: &listName,
-					},
-					&cli.StringFlag{
-						Name:        "name",
-						Aliases:     []string{"n"},
-						Usage:       "the `name` of the to-do to delete",
-						Required:    true,
-						Destination: &todoName,
-					},
-				},
-				Action: func(ctx context.Context, cmd *cli.Command) error {
-					result, err := deleteTodoFromList(listName, todoName)
-					if err != nil {
-						return err
-					}
-					if !result.Success {
-						return cli.Exit(result.Message, 1)
-					}
-					return nil
-				},
-			},
-			{
-				Name:    "move",
-				Usage:   "Move a todo from one list to another",
-				Aliases: []string{"m"},
-				Flags: []cli.Flag{
-					&cli.StringFlag{
-						Name:        "from",
-						Usage:       "the `list` to move the to-do from",
-						Required:    true,
-						Destination: &fromList,
-					},
-					&cli.StringFlag{
-						Name:        "to",
-						Usage:       "the `list` to move the to-do to",
-						Required:    true,
-						Destination: &toList,
-					},
-					&cli.StringFlag{
-						Name:        "name",
-						Aliases:     []string{"n"},
-						Usage:       "the `name` of the to-do to move",
-						Required:    true,
-						Destination: &todoName,
-					},
-				},
-				Action: func(ctx context.Context, cmd *cli.Command) error {
-					result, err := moveTodoBetweenLists(fromList, toList, todoName)
-					if err != nil {
-						return err
-					}
-					if !result.Success {
-						return cli.Exit(result.Message, 1)
-					}
-					return nil
-				},
-			},
-			{
-				Name:    "rename",
-				Usage:   "Rename a todo in a specified list",
-				Aliases: []string{"r"},
-				Flags: []cli.Flag{
-					&cli.StringFlag{
-						Name:        "list",
-						Aliases:     []string{"l"},
-						Usage:       "the `list` containing the to-do",
-						Required:    true,
-						Destination: &listName,
-					},
-					&cli.StringFlag{
-						Name:        "name",
-						Aliases:     []string{"n"},
-						Usage:       "the current `name` of the to-do",
-						Required:    true,
-						Destination: &todoName,
-					},
-					&cli.StringFlag{
-						Name:        "new-name",
-						Usage:       "the `new name` for the to-do",
-						Required:    true,
-						Destination: &newName,
-					},
-				},
-				Action: func(ctx context.Context, cmd *cli.Command) error {
-					result, err := renameTodoInList(listName, todoName, newName)
-					if err != nil {
-						return err
-					}
-					if !result.Success {
-						return cli.Exit(result.Message, 1)
-					}
-					return nil
-				},
-			},
-			{
-				Name:    "log",
-				Usage:   "Show completed to-dos from the Logbook",
-				Aliases: []string{"lg"},
-				Flags: []cli.Flag{
-					&cli.StringFlag{
-						Name:        "date",
-						Aliases:     []string{"d"},
-						Usage:       "show completed to-dos from `TIMEFRAME` (today, this week, this month)",
-						Required:    true,
-						Destination: &dateFilter,
-					},
-					&cli.StringFlag{
-						Name:        "area",
-						Aliases:     []string{"a"},
-						Usage:       "filter by `AREA` name",
-						Destination: &areaFilter,
-					},
-					&cli.StringFlag{
-						Name:        "project",
-						Aliases:     []string{"p"},
-						Usage:       "filter by `PROJECT` name",
-						Destination: &projectFilter,
-					},
-					&cli.BoolFlag{
-						Name:        "jsonl",
-						Usage:       "output todos in JSONL format",
-						Destination: &jsonl,
-					},
-				},
-				Action: func(ctx context.Context, cmd *cli.Command) error {
-					// Validate date filter - accept keywords or YYYY-MM-DD format
-					if dateFilter != "today" && dateFilter != "this week" && dateFilter != "this month" {
-						// Try parsing as YYYY-MM-DD date
-						if _, err := time.Parse("2006-01-02", dateFilter); err != nil {
-							return cli.Exit("ERROR: --date must be one of: today, this week, this month, or a date in YYYY-MM-DD format", 1)
-						}
-					}
-					todos, err := getCompletedTodosFiltered(dateFilter, areaFilter, projectFilter)
-					if err != nil {
-						if strings.HasPrefix(err.Error(), "ERROR:") {
-							return cli.Exit(err.Error(), 1)
-						}
-						return err
-					}
-					_ = todos
-					_ = jsonl
-					return nil
-				},
-			},
-		},
-	}
+	app := newApp()
+	app.Version = "test"
+	app.EnableShellCompletion = false
 
 	if writer != nil {
 		app.Writer = writer
@@ -349,8 +119,8 @@ func TestShowCommand_ListNotFound(t *testing.T) {
 
 	// Check if it's a cli.Exit error with correct exit code
 	if exitErr, ok := err.(cli.ExitCoder); ok {
-		if exitErr.ExitCode() != 1 {
-			t.Errorf("expected exit code 1, got %d", exitErr.ExitCode())
+		if exitErr.ExitCode() != exitNotFound {
+			t.Errorf("expected exit code %d, got %d", exitNotFound, exitErr.ExitCode())
 		}
 		if !strings.Contains(err.Error(), "ERROR:") {
 			t.Error("exit error should contain ERROR message")
@@ -381,6 +151,328 @@ func TestShowCommand_ExecError(t *testing.T) {
 	}
 }
 
+func TestRetryFlag(t *testing.T) {
+	originalDelay := retryBaseDelay
+	retryBaseDelay = time.Millisecond
+	defer func() { retryBaseDelay = originalDelay }()
+
+	transient := errors.New("exit status 1")
+	tests := []struct {
+		name      string
+		args      []string
+		expectErr bool
+	}{
+		{"succeeds with retries", []string{"things", "--retry", "3", "complete", "--list", "Today", "--name", "Task"}, false},
+		{"fails without retries", []string{"things", "complete", "--list", "Today", "--name", "Task"}, true},
+		{"negative", []string{"things", "--retry", "-1", "complete", "--list", "Today", "--name", "Task"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cleanup := setupMockExecutorIntegrationMulti([]string{"", "", "SUCCESS: 1"}, []error{transient, transient, nil})
+			defer cleanup()
+
+			app := createTestApp()
+			err := app.Run(context.Background(), tt.args)
+			if tt.expectErr != (err != nil) {
+				t.Errorf("expected error %v, got %v", tt.expectErr, err)
+			}
+			// The wrapper only lasts for the run
+			if _, ok := executor.(*MockExecutor); !ok {
+				t.Errorf("expected the executor to be restored, got %T", executor)
+			}
+		})
+	}
+}
+
+func TestShowCommand_CountOnly(t *testing.T) {
+	tests := []struct {
+		name         string
+		args         []string
+		output       string
+		expectOutput string
+		expectErr    string
+	}{
+		{
+			name:         "mixed statuses",
+			args:         []string{"things", "show", "--list", "Work", "--count-only"},
+			output:       `[{"name":"A","status":"open"},{"name":"B","status":"completed"},{"name":"C","status":"open"}]`,
+			expectOutput: "Work: 2 open, 1 completed\n",
+		},
+		{
+			name:         "after filters",
+			args:         []string{"things", "show", "--list", "Work", "--count-only", "--status", "completed"},
+			output:       `[{"name":"A","status":"open"},{"name":"B","status":"completed"}]`,
+			expectOutput: "Work: 1 completed\n",
+		},
+		{
+			name:         "empty list",
+			args:         []string{"things", "show", "--list", "Work", "--count-only"},
+			output:       `[]`,
+			expectOutput: "Work: no to-dos\n",
+		},
+		{
+			name:      "with a format",
+			args:      []string{"things", "show", "--list", "Work", "--count-only", "--jsonl"},
+			expectErr: "ERROR: --count-only can't be combined with an output format such as --jsonl",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cleanup := setupMockExecutorIntegration(tt.output, nil)
+			defer cleanup()
+
+			var out strings.Builder
+			app := createTestAppWithWriters(&out, io.Discard)
+			err := app.Run(context.Background(), tt.args)
+			if tt.expectErr != "" {
+				if err == nil || err.Error() != tt.expectErr {
+					t.Errorf("expected error %q, got %v", tt.expectErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if out.String() != tt.expectOutput {
+				t.Errorf("expected %q, got %q", tt.expectOutput, out.String())
+			}
+		})
+	}
+}
+
+func TestProjectCommand(t *testing.T) {
+	tests := []struct {
+		name         string
+		args         []string
+		output       string
+		expectOutput string
+		expectErr    string
+		expectCode   int
+	}{
+		{
+			name:         "create in an area",
+			args:         []string{"things", "project", "create", "--name", "Q3 Launch", "--area", "Work"},
+			output:       `SUCCESS: 1 ["P1"]`,
+			expectOutput: "Project \"Q3 Launch\" created in area \"Work\"!\n",
+		},
+		{
+			name:         "complete",
+			args:         []string{"things", "project", "complete", "--name", "Q3 Launch"},
+			output:       `SUCCESS: 1 ["P1"]`,
+			expectOutput: "Project \"Q3 Launch\" completed!\n",
+		},
+		{
+			name:       "rename a missing project",
+			args:       []string{"things", "project", "rename", "--name", "Nowhere", "--new-name", "Q4 Launch"},
+			output:     "ERROR: Project not found",
+			expectErr:  `ERROR: Project "Nowhere" not found`,
+			expectCode: exitNotFound,
+		},
+		{
+			name:       "rename to an empty name",
+			args:       []string{"things", "project", "rename", "--name", "Q3 Launch", "--new-name", " "},
+			expectErr:  "ERROR: --new-name can't be empty",
+			expectCode: exitFailure,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cleanup := setupMockExecutorIntegration(tt.output, nil)
+			defer cleanup()
+
+			var out strings.Builder
+			app := createTestAppWithWriters(&out, io.Discard)
+			err := app.Run(context.Background(), tt.args)
+			if tt.expectErr != "" {
+				exitErr, ok := err.(cli.ExitCoder)
+				if !ok || err.Error() != tt.expectErr || exitErr.ExitCode() != tt.expectCode {
+					t.Errorf("expected error %q with exit code %d, got %v", tt.expectErr, tt.expectCode, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if out.String() != tt.expectOutput {
+				t.Errorf("expected %q, got %q", tt.expectOutput, out.String())
+			}
+		})
+	}
+}
+
+func TestAreaCommand(t *testing.T) {
+	tests := []struct {
+		name         string
+		args         []string
+		output       string
+		expectOutput string
+		expectErr    string
+		expectCode   int
+	}{
+		{
+			name:         "create",
+			args:         []string{"things", "area", "create", "--name", "Home"},
+			output:       `SUCCESS: 1 ["A1"]`,
+			expectOutput: "Area \"Home\" created!\n",
+		},
+		{
+			name:       "create a duplicate",
+			args:       []string{"things", "area", "create", "--name", "Home"},
+			output:     "ERROR: Area already exists",
+			expectErr:  `ERROR: Area "Home" already exists`,
+			expectCode: exitFailure,
+		},
+		{
+			name:       "rename a missing area",
+			args:       []string{"things", "area", "rename", "--name", "Nowhere", "--new-name", "Personal"},
+			output:     "ERROR: Area not found",
+			expectErr:  `ERROR: Area "Nowhere" not found`,
+			expectCode: exitNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cleanup := setupMockExecutorIntegration(tt.output, nil)
+			defer cleanup()
+
+			var out strings.Builder
+			app := createTestAppWithWriters(&out, io.Discard)
+			err := app.Run(context.Background(), tt.args)
+			if tt.expectErr != "" {
+				exitErr, ok := err.(cli.ExitCoder)
+				if !ok || err.Error() != tt.expectErr || exitErr.ExitCode() != tt.expectCode {
+					t.Errorf("expected error %q with exit code %d, got %v", tt.expectErr, tt.expectCode, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if out.String() != tt.expectOutput {
+				t.Errorf("expected %q, got %q", tt.expectOutput, out.String())
+			}
+		})
+	}
+}
+
+func TestWatchTodos(t *testing.T) {
+	cleanup := setupMockExecutorIntegration(`[{"name":"Task","status":"open"}]`, nil)
+	defer cleanup()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ticks := make(chan time.Time)
+	var out strings.Builder
+	done := make(chan error)
+	go func() {
+		done <- watchTodos(ctx, &out, ticks, func(ctx context.Context) error {
+			todos, err := getTodosFromList(ctx, "Today")
+			if err != nil {
+				return err
+			}
+			return writeTodos(&out, todos, outputOptions{jsonl: true})
+		})
+	}()
+
+	// Each tick is only received once the frame before it is done, so two ticks mean three frames
+	for range 2 {
+		select {
+		case ticks <- time.Now():
+		case err := <-done:
+			t.Fatalf("watch ended early: %v", err)
+		}
+	}
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if calls := len(executor.(*MockExecutor).calls); calls != 3 {
+		t.Errorf("expected the list to be fetched 3 times, got %d", calls)
+	}
+	if frames := strings.Count(out.String(), ansiClearScreen); frames != 3 {
+		t.Errorf("expected 3 frames, got %d:\n%q", frames, out.String())
+	}
+}
+
+func TestWatchTodos_Error(t *testing.T) {
+	ticks := make(chan time.Time)
+	err := watchTodos(context.Background(), io.Discard, ticks, func(ctx context.Context) error {
+		return cli.Exit("ERROR: List \"Nowhere\" not found", exitNotFound)
+	})
+	if err == nil || err.Error() != `ERROR: List "Nowhere" not found` {
+		t.Errorf("expected the update's error, got %v", err)
+	}
+}
+
+func TestShowCommand_WatchFlags(t *testing.T) {
+	tests := []struct {
+		name      string
+		args      []string
+		expectErr string
+	}{
+		{"interval without watch", []string{"things", "show", "--list", "Today", "--interval", "1s"}, "ERROR: --interval requires --watch"},
+		{"zero interval", []string{"things", "show", "--list", "Today", "--watch", "--interval", "0s"}, "ERROR: --interval must be greater than 0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cleanup := setupMockExecutorIntegration("[]", nil)
+			defer cleanup()
+
+			app := createTestApp()
+			err := app.Run(context.Background(), tt.args)
+			if err == nil || err.Error() != tt.expectErr {
+				t.Errorf("expected error %q, got %v", tt.expectErr, err)
+			}
+			if calls := len(executor.(*MockExecutor).calls); calls != 0 {
+				t.Errorf("expected no script to run, got %d calls", calls)
+			}
+		})
+	}
+}
+
+func TestExitCodes(t *testing.T) {
+	tests := []struct {
+		name       string
+		args       []string
+		output     string
+		outputErr  error
+		expectCode int
+	}{
+		{"delete not found", []string{"things", "delete", "--yes", "--list", "Inbox", "--name", "Missing"}, "ERROR: To-do not found in list", nil, exitNotFound},
+		{"delete by id not found", []string{"things", "delete", "--yes", "--id", "missing"}, "ERROR: To-do not found", nil, exitNotFound},
+		{"complete in missing list", []string{"things", "complete", "--list", "Nowhere", "--name", "Task"}, "ERROR: List not found: Can't get object.", nil, exitNotFound},
+		{"move matching no regex", []string{"things", "move", "--yes", "--from", "Inbox", "--to", "Today", "--name-regex", "^x"}, "[]", nil, exitNotFound},
+		{"delete exec failure", []string{"things", "delete", "--yes", "--list", "Inbox", "--name", "Task"}, "", errors.New("exit status 1"), exitFailure},
+		{"invalid flag value", []string{"things", "log", "--date", "someday"}, "", nil, exitFailure},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cleanup := setupMockExecutorIntegration(tt.output, tt.outputErr)
+			defer cleanup()
+
+			app := createTestApp()
+			err := app.Run(context.Background(), tt.args)
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+			// main exits with exitFailure for errors that don't carry their own code
+			code := exitFailure
+			if exitErr, ok := err.(cli.ExitCoder); ok {
+				code = exitErr.ExitCode()
+			}
+			if code != tt.expectCode {
+				t.Errorf("expected exit code %d, got %d (%v)", tt.expectCode, code, err)
+			}
+		})
+	}
+}
+
 func TestAddCommand_Success(t *testing.T) {
 	cleanup := setupMockExecutorIntegration(`To-do added successfully to list "inbox"!`, nil)
 	defer cleanup()
@@ -418,7 +510,7 @@ func TestDeleteCommand_Success(t *testing.T) {
 	defer cleanup()
 
 	app := createTestApp()
-	err := app.Run(context.Background(), []string{"things", "delete", "--list", "Inbox", "--name", "Test Todo"})
+	err := app.Run(context.Background(), []string{"things", "delete", "--yes", "--list", "Inbox", "--name", "Test Todo"})
 	if err != nil {
 		t.Errorf("unexpected error: %v", err)
 	}
@@ -429,7 +521,7 @@ func TestDeleteCommand_Error(t *testing.T) {
 	defer cleanup()
 
 	app := createTestApp()
-	err := app.Run(context.Background(), []string{"things", "delete", "--list", "Inbox", "--name", "NonExistent"})
+	err := app.Run(context.Background(), []string{"things", "delete", "--yes", "--list", "Inbox", "--name", "NonExistent"})
 
 	// Should return cli.Exit error
 	if err == nil {
@@ -437,8 +529,8 @@ func TestDeleteCommand_Error(t *testing.T) {
 	}
 
 	if exitErr, ok := err.(cli.ExitCoder); ok {
-		if exitErr.ExitCode() != 1 {
-			t.Errorf("expected exit code 1, got %d", exitErr.ExitCode())
+		if exitErr.ExitCode() != exitNotFound {
+			t.Errorf("expected exit code %d, got %d", exitNotFound, exitErr.ExitCode())
 		}
 	} else {
 		t.Errorf("expected cli.ExitCoder, got %T", err)
@@ -453,8 +545,9 @@ func TestCommandAliases(t *testing.T) {
 	}{
 		{"show alias", []string{"things", "s", "--list", "Work"}, []string{`[{"name":"Test","status":"open"}]`}},
 		{"add alias", []string{"things", "a", "--name", "Test"}, []string{`To-do added successfully to list "inbox"!`}},
-		{"delete alias", []string{"things", "d", "--list", "Inbox", "--name", "Test"}, []string{`To-do "Test" deleted successfully from list "Inbox"!`}},
-		{"move alias", []string{"things", "m", "--from", "Inbox", "--to", "Work", "--name", "Test"}, []string{`To-do "Test" moved successfully from list "Inbox" to list "Work"!`}},
+		{"delete alias", []string{"things", "d", "--yes", "--list", "Inbox", "--name", "Test"}, []string{`To-do "Test" deleted successfully from list "Inbox"!`}},
+		{"move alias", []string{"things", "m", "--yes", "--from", "Inbox", "--to", "Work", "--name", "Test"}, []string{`To-do "Test" moved successfully from list "Inbox" to list "Work"!`}},
+		{"complete alias", []string{"things", "c", "--list", "Inbox", "--name", "Test"}, []string{"SUCCESS: 1"}},
 		{"rename alias", []string{"things", "r", "--list", "Inbox", "--name", "Old", "--new-name", "New"}, []string{"SUCCESS"}},
 		{"log alias", []string{"things", "lg", "--date", "today"}, []string{"SUCCESS", `[{"name":"Completed task","status":"completed"}]`}},
 	}
@@ -479,7 +572,7 @@ func TestMoveCommand_Success(t *testing.T) {
 	defer cleanup()
 
 	app := createTestApp()
-	err := app.Run(context.Background(), []string{"things", "move", "--from", "Inbox", "--to", "Work", "--name", "Test Todo"})
+	err := app.Run(context.Background(), []string{"things", "move", "--yes", "--from", "Inbox", "--to", "Work", "--name", "Test Todo"})
 	if err != nil {
 		t.Errorf("unexpected error: %v", err)
 	}
@@ -490,7 +583,7 @@ func TestMoveCommand_Error(t *testing.T) {
 	defer cleanup()
 
 	app := createTestApp()
-	err := app.Run(context.Background(), []string{"things", "move", "--from", "Inbox", "--to", "Work", "--name", "NonExistent"})
+	err := app.Run(context.Background(), []string{"things", "move", "--yes", "--from", "Inbox", "--to", "Work", "--name", "NonExistent"})
 
 	// Should return cli.Exit error
 	if err == nil {
@@ -498,8 +591,8 @@ func TestMoveCommand_Error(t *testing.T) {
 	}
 
 	if exitErr, ok := err.(cli.ExitCoder); ok {
-		if exitErr.ExitCode() != 1 {
-			t.Errorf("expected exit code 1, got %d", exitErr.ExitCode())
+		if exitErr.ExitCode() != exitNotFound {
+			t.Errorf("expected exit code %d, got %d", exitNotFound, exitErr.ExitCode())
 		}
 	} else {
 		t.Errorf("expected cli.ExitCoder, got %T", err)
@@ -511,7 +604,7 @@ func TestMoveCommand_TodayToInbox(t *testing.T) {
 	defer cleanup()
 
 	app := createTestApp()
-	err := app.Run(context.Background(), []string{"things", "move", "--from", "today", "--to", "inbox", "--name", "Make a small plan for how to help cutter"})
+	err := app.Run(context.Background(), []string{"things", "move", "--yes", "--from", "today", "--to", "inbox", "--name", "Make a small plan for how to help cutter"})
 	if err != nil {
 		t.Errorf("unexpected error: %v", err)
 	}
@@ -591,8 +684,8 @@ func TestRenameCommand_Error(t *testing.T) {
 	}
 
 	if exitErr, ok := err.(cli.ExitCoder); ok {
-		if exitErr.ExitCode() != 1 {
-			t.Errorf("expected exit code 1, got %d", exitErr.ExitCode())
+		if exitErr.ExitCode() != exitNotFound {
+			t.Errorf("expected exit code %d, got %d", exitNotFound, exitErr.ExitCode())
 		}
 	} else {
 		t.Errorf("expected cli.ExitCoder, got %T", err)
@@ -624,6 +717,71 @@ func TestRenameCommand_MissingFlags(t *testing.T) {
 	}
 }
 
+func TestReopenCommand(t *testing.T) {
+	tests := []struct {
+		name       string
+		args       []string
+		output     string
+		expectErr  bool
+		expectList string
+	}{
+		{"from the Logbook by default", []string{"things", "reopen", "--name", "Buy milk"}, `SUCCESS: 1 ["ABC123"]`, false, "byName('Logbook')"},
+		{"from another list", []string{"things", "reopen", "--list", "Today", "--name", "Buy milk"}, `SUCCESS: 1 ["ABC123"]`, false, "byName('Today')"},
+		{"not found", []string{"things", "reopen", "--name", "Missing"}, "ERROR: To-do not found in list", true, "byName('Logbook')"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cleanup := setupMockExecutorIntegration(tt.output, nil)
+			defer cleanup()
+
+			var out strings.Builder
+			app := createTestAppWithWriters(&out, io.Discard)
+			err := app.Run(context.Background(), tt.args)
+			if tt.expectErr != (err != nil) {
+				t.Fatalf("expected error %v, got %v", tt.expectErr, err)
+			}
+			if !tt.expectErr && out.String() != "To-do \"Buy milk\" reopened!\n" {
+				t.Errorf("unexpected output: %q", out.String())
+			}
+			if script := executor.(*MockExecutor).lastScript(); !strings.Contains(script, tt.expectList) {
+				t.Errorf("expected script to contain %q", tt.expectList)
+			}
+		})
+	}
+}
+
+func TestRestoreCommand(t *testing.T) {
+	tests := []struct {
+		name      string
+		args      []string
+		output    string
+		expectErr bool
+	}{
+		{"by name", []string{"things", "restore", "--name", "Buy milk"}, `SUCCESS: 1 ["ABC123"]`, false},
+		{"by id", []string{"things", "restore", "--id", "ABC123"}, "SUCCESS: Buy milk", false},
+		{"not found", []string{"things", "restore", "--name", "Missing"}, "ERROR: To-do not found in list", true},
+		{"missing name and id", []string{"things", "restore"}, "", true},
+		{"both name and id", []string{"things", "restore", "--name", "Buy milk", "--id", "ABC123"}, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cleanup := setupMockExecutorIntegration(tt.output, nil)
+			defer cleanup()
+
+			app := createTestApp()
+			err := app.Run(context.Background(), tt.args)
+			if tt.expectErr && err == nil {
+				t.Error("expected error but got none")
+			}
+			if !tt.expectErr && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
 func TestRenameCommand_Alias(t *testing.T) {
 	cleanup := setupMockExecutorIntegration("SUCCESS", nil)
 	defer cleanup()
@@ -675,93 +833,373 @@ func TestLogCommand_Success(t *testing.T) {
 	}
 }
 
-func TestLogCommand_WithFilters(t *testing.T) {
-	mockOutput := `[{"name":"Task 1","status":"completed","area":"Work"}]`
+func TestStatsCommand(t *testing.T) {
+	mockOutput := `[{"name":"Ship release","status":"completed","area":"Work","tagNames":["urgent"]},{"name":"Buy milk","status":"completed"},{"name":"Dropped idea","status":"canceled","area":"Work"}]`
 
 	tests := []struct {
-		name string
-		args []string
+		name     string
+		args     []string
+		expected string
 	}{
-		{
-			name: "log with area filter",
-			args: []string{"things", "log", "--date", "today", "--area", "Work"},
-		},
-		{
-			name: "log with project filter",
-			args: []string{"things", "log", "--date", "today", "--project", "Project A"},
-		},
-		{
-			name: "log with both filters",
-			args: []string{"things", "log", "--date", "today", "--area", "Work", "--project", "Project A"},
-		},
-		{
-			name: "log with area alias",
-			args: []string{"things", "log", "-d", "today", "-a", "Work"},
-		},
-		{
-			name: "log with project alias",
-			args: []string{"things", "log", "-d", "today", "-p", "Project A"},
-		},
+		{"default", []string{"things", "stats", "--date", "this week"}, "Completed: 2\n\nBy area:\n  (none)  1\n  Work    1\n\nBy project:\n  (none)  2\n\nBy tag:\n  urgent  1\n"},
+		{"jsonl", []string{"things", "stats", "--date", "today", "--jsonl"}, `{"completed":2,"byArea":{"(none)":1,"Work":1},"byProject":{"(none)":2},"byTag":{"urgent":1}}` + "\n"},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			// Mock both logCompletedNow() and getTodosFromListWithFilter() calls
 			cleanup := setupMockExecutorIntegrationMulti([]string{"SUCCESS", mockOutput}, []error{nil, nil})
 			defer cleanup()
 
-			app := createTestApp()
-			err := app.Run(context.Background(), tt.args)
-			if err != nil {
-				t.Errorf("unexpected error: %v", err)
+			var out strings.Builder
+			app := createTestAppWithWriters(&out, io.Discard)
+			if err := app.Run(context.Background(), tt.args); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if out.String() != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, out.String())
 			}
 		})
 	}
 }
 
-func TestLogCommand_InvalidDateFilter(t *testing.T) {
+func TestStatsCommand_InvalidDate(t *testing.T) {
 	cleanup := setupMockExecutorIntegration("", nil)
 	defer cleanup()
 
 	app := createTestApp()
-	err := app.Run(context.Background(), []string{"things", "log", "--date", "yesterday"})
-
-	if err == nil {
-		t.Error("expected error for invalid date filter")
-	}
-
-	if exitErr, ok := err.(cli.ExitCoder); ok {
-		if exitErr.ExitCode() != 1 {
-			t.Errorf("expected exit code 1, got %d", exitErr.ExitCode())
-		}
-		if !strings.Contains(err.Error(), "ERROR:") {
-			t.Error("error should contain ERROR message")
-		}
-	} else {
-		t.Errorf("expected cli.ExitCoder, got %T", err)
+	if err := app.Run(context.Background(), []string{"things", "stats", "--date", "someday"}); err == nil {
+		t.Error("expected error for invalid --date")
 	}
 }
 
-func TestLogCommand_WithSpecificDate(t *testing.T) {
-	mockOutput := `[{"name":"Task from specific date","status":"completed","completionDate":"2024-01-15T10:00:00Z"}]`
+func TestLogCommand_Status(t *testing.T) {
+	mockOutput := `[{"name":"Shipped","status":"completed"},{"name":"Dropped","status":"canceled"},{"name":"Abandoned","status":"cancelled"}]`
 
 	tests := []struct {
-		name string
-		date string
+		name      string
+		args      []string
+		expected  []string
+		expectErr bool
 	}{
-		{
-			name: "log with YYYY-MM-DD date",
-			date: "2024-01-15",
-		},
-		{
-			name: "log with different date",
-			date: "2023-12-25",
-		},
+		{"completed only by default", []string{"things", "log", "--date", "today"}, []string{"Shipped"}, false},
+		{"canceled only", []string{"things", "log", "--date", "today", "--status", "canceled"}, []string{"Dropped", "Abandoned"}, false},
+		{"all", []string{"things", "log", "--date", "today", "--status", "all"}, []string{"Shipped", "Dropped", "Abandoned"}, false},
+		{"include canceled", []string{"things", "log", "--date", "today", "--include-canceled"}, []string{"Shipped", "Dropped", "Abandoned"}, false},
+		{"open is not a Logbook status", []string{"things", "log", "--date", "today", "--status", "open"}, nil, true},
+		{"include canceled with another status", []string{"things", "log", "--date", "today", "--include-canceled", "--status", "completed"}, nil, true},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			// Mock both logCompletedNow() and getTodosFromListWithFilter() calls
+			cleanup := setupMockExecutorIntegrationMulti([]string{"SUCCESS", mockOutput}, []error{nil, nil})
+			defer cleanup()
+
+			var out strings.Builder
+			app := createTestAppWithWriters(&out, io.Discard)
+			err := app.Run(context.Background(), append(tt.args, "--json"))
+			if tt.expectErr {
+				if err == nil {
+					t.Error("expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			var todos []Todo
+			if err := json.Unmarshal([]byte(out.String()), &todos); err != nil {
+				t.Fatalf("invalid JSON output %q: %v", out.String(), err)
+			}
+			assertTodoNames(t, todos, tt.expected)
+		})
+	}
+}
+
+func TestLogCommand_NoFlush(t *testing.T) {
+	mockOutput := `[{"name":"Completed task 1","status":"completed"}]`
+	cleanup := setupMockExecutorIntegration(mockOutput, nil)
+	defer cleanup()
+
+	var out strings.Builder
+	app := createTestAppWithWriters(&out, io.Discard)
+	if err := app.Run(context.Background(), []string{"things", "log", "--date", "today", "--no-flush"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(out.String(), "Completed task 1") {
+		t.Errorf("expected the Logbook to-do in output, got %q", out.String())
+	}
+
+	calls := executor.(*MockExecutor).calls
+	if len(calls) != 1 {
+		t.Fatalf("expected only the Logbook to be read, got %d calls", len(calls))
+	}
+	if strings.Contains(calls[0][len(calls[0])-1], "logCompletedNow") {
+		t.Error("expected logCompletedNow to be skipped")
+	}
+}
+
+func TestLogCommand_ShowAge(t *testing.T) {
+	mockOutput := `[{"name":"Ship release","status":"completed","creationDate":"2026-10-12T06:00:00Z","completionDate":"2026-10-15T10:00:00Z"},{"name":"Imported","status":"completed","completionDate":"2026-10-15T11:00:00Z"}]`
+	cleanup := setupMockExecutorIntegration(mockOutput, nil)
+	defer cleanup()
+
+	var out strings.Builder
+	app := createTestAppWithWriters(&out, io.Discard)
+	if err := app.Run(context.Background(), []string{"things", "log", "--date", "2026-10-15", "--timezone", "UTC", "--no-flush", "--show-age"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 to-dos, got %q", out.String())
+	}
+	if !strings.HasSuffix(lines[0], "  open 3d 4h") {
+		t.Errorf("expected the age after the completion time, got %q", lines[0])
+	}
+	if strings.Contains(lines[1], "open") {
+		t.Errorf("expected no age without a creation date, got %q", lines[1])
+	}
+}
+
+func TestLogCommand_WithFilters(t *testing.T) {
+	mockOutput := `[{"name":"Task 1","status":"completed","area":"Work"}]`
+
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{
+			name: "log with area filter",
+			args: []string{"things", "log", "--date", "today", "--area", "Work"},
+		},
+		{
+			name: "log with project filter",
+			args: []string{"things", "log", "--date", "today", "--project", "Project A"},
+		},
+		{
+			name: "log with both filters",
+			args: []string{"things", "log", "--date", "today", "--area", "Work", "--project", "Project A"},
+		},
+		{
+			name: "log with area alias",
+			args: []string{"things", "log", "-d", "today", "-a", "Work"},
+		},
+		{
+			name: "log with project alias",
+			args: []string{"things", "log", "-d", "today", "-p", "Project A"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Mock both logCompletedNow() and getTodosFromListWithFilter() calls
+			cleanup := setupMockExecutorIntegrationMulti([]string{"SUCCESS", mockOutput}, []error{nil, nil})
+			defer cleanup()
+
+			app := createTestApp()
+			err := app.Run(context.Background(), tt.args)
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestLogCommand_InvalidDateFilter(t *testing.T) {
+	cleanup := setupMockExecutorIntegration("", nil)
+	defer cleanup()
+
+	app := createTestApp()
+	err := app.Run(context.Background(), []string{"things", "log", "--date", "tomorrow"})
+
+	if err == nil {
+		t.Error("expected error for invalid date filter")
+	}
+
+	if exitErr, ok := err.(cli.ExitCoder); ok {
+		if exitErr.ExitCode() != 1 {
+			t.Errorf("expected exit code 1, got %d", exitErr.ExitCode())
+		}
+		if !strings.Contains(err.Error(), "ERROR:") {
+			t.Error("error should contain ERROR message")
+		}
+	} else {
+		t.Errorf("expected cli.ExitCoder, got %T", err)
+	}
+}
+
+func TestLogCommand_Timezone(t *testing.T) {
+	tests := []struct {
+		name      string
+		args      []string
+		expectErr bool
+	}{
+		{"utc", []string{"things", "log", "--date", "today", "--timezone", "UTC"}, false},
+		{"iana name", []string{"things", "log", "--date", "2024-01-15", "--timezone", "America/New_York"}, false},
+		{"invalid", []string{"things", "log", "--date", "today", "--timezone", "Nowhere/Special"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cleanup := setupMockExecutorIntegrationMulti([]string{"SUCCESS", "[]"}, []error{nil, nil})
+			defer cleanup()
+
+			app := createTestApp()
+			err := app.Run(context.Background(), tt.args)
+			if tt.expectErr {
+				if err == nil || !strings.Contains(err.Error(), "ERROR: --timezone") {
+					t.Errorf("expected --timezone error, got %v", err)
+				}
+				if calls := executor.(*MockExecutor).calls; len(calls) != 0 {
+					t.Errorf("expected no calls, got %d", len(calls))
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestShowCommand_DueFilters(t *testing.T) {
+	mockOutput := `[{"name":"Late","status":"open","dueDate":"2024-01-14"},{"name":"On time","status":"open","dueDate":"2024-01-15"},{"name":"Whenever","status":"open"}]`
+	setNow(t, time.Date(2024, 1, 15, 9, 30, 0, 0, time.Local))
+
+	tests := []struct {
+		name         string
+		args         []string
+		expectErr    bool
+		expectOutput string
+	}{
+		{
+			name:         "overdue",
+			args:         []string{"things", "show", "--list", "Anytime", "--overdue"},
+			expectOutput: "○ Late\n",
+		},
+		{
+			name:         "without overdue",
+			args:         []string{"things", "show", "--list", "Anytime"},
+			expectOutput: "○ Late\n○ On time\n○ Whenever\n",
+		},
+		{
+			name:      "invalid timezone",
+			args:      []string{"things", "show", "--list", "Anytime", "--overdue", "--timezone", "Nowhere/Special"},
+			expectErr: true,
+		},
+		{
+			name:         "due before",
+			args:         []string{"things", "show", "--list", "Anytime", "--due-before", "2024-01-15"},
+			expectOutput: "○ Late\n",
+		},
+		{
+			name:         "due after",
+			args:         []string{"things", "show", "--list", "Anytime", "--due-after", "2024-01-14"},
+			expectOutput: "○ On time\n",
+		},
+		{
+			name:      "invalid due date",
+			args:      []string{"things", "show", "--list", "Anytime", "--due-before", "soon"},
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cleanup := setupMockExecutorIntegration(mockOutput, nil)
+			defer cleanup()
+
+			var out strings.Builder
+			app := createTestAppWithWriters(&out, io.Discard)
+			err := app.Run(context.Background(), append(tt.args, "--color", "never"))
+			if tt.expectErr {
+				if _, ok := err.(cli.ExitCoder); !ok {
+					t.Errorf("expected cli.ExitCoder, got %T (%v)", err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if out.String() != tt.expectOutput {
+				t.Errorf("expected %q, got %q", tt.expectOutput, out.String())
+			}
+		})
+	}
+}
+
+func TestLogCommand_TimeOfDay(t *testing.T) {
+	mockOutput := `[{"name":"Morning","status":"completed","completionDate":"2024-01-15T08:00:00Z"},{"name":"Evening","status":"completed","completionDate":"2024-01-15T20:00:00Z"}]`
+
+	tests := []struct {
+		name         string
+		args         []string
+		expectErr    bool
+		expectOutput string
+	}{
+		{
+			name:         "morning window",
+			args:         []string{"things", "log", "--date", "2024-01-15", "--timezone", "UTC", "--from-time", "06:00", "--to-time", "12:00"},
+			expectOutput: "✔︎ Morning\n",
+		},
+		{
+			name:         "from only",
+			args:         []string{"things", "log", "--date", "2024-01-15", "--timezone", "UTC", "--from-time", "12:00"},
+			expectOutput: "✔︎ Evening\n",
+		},
+		{
+			name:      "malformed time",
+			args:      []string{"things", "log", "--date", "2024-01-15", "--to-time", "noon"},
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cleanup := setupMockExecutorIntegrationMulti([]string{"SUCCESS", mockOutput}, []error{nil, nil})
+			defer cleanup()
+
+			var out strings.Builder
+			app := createTestAppWithWriters(&out, io.Discard)
+			err := app.Run(context.Background(), append(tt.args, "--color", "never"))
+			if tt.expectErr {
+				if err == nil || !strings.Contains(err.Error(), "ERROR: --to-time") {
+					t.Errorf("expected --to-time error, got %v", err)
+				}
+				if calls := executor.(*MockExecutor).calls; len(calls) != 0 {
+					t.Errorf("expected no calls, got %d", len(calls))
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if out.String() != tt.expectOutput {
+				t.Errorf("expected %q, got %q", tt.expectOutput, out.String())
+			}
+		})
+	}
+}
+
+func TestLogCommand_WithSpecificDate(t *testing.T) {
+	mockOutput := `[{"name":"Task from specific date","status":"completed","completionDate":"2024-01-15T10:00:00Z"}]`
+
+	tests := []struct {
+		name string
+		date string
+	}{
+		{
+			name: "log with YYYY-MM-DD date",
+			date: "2024-01-15",
+		},
+		{
+			name: "log with different date",
+			date: "2023-12-25",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Mock both logCompletedNow() and getTodosFromListWithFilter() calls
 			cleanup := setupMockExecutorIntegrationMulti([]string{"SUCCESS", mockOutput}, []error{nil, nil})
 			defer cleanup()
 
@@ -774,6 +1212,52 @@ func TestLogCommand_WithSpecificDate(t *testing.T) {
 	}
 }
 
+func TestLogCommand_RelativeKeywords(t *testing.T) {
+	for _, date := range []string{"yesterday", "last week", "last month"} {
+		t.Run(date, func(t *testing.T) {
+			cleanup := setupMockExecutorIntegrationMulti([]string{"SUCCESS", `[]`}, []error{nil, nil})
+			defer cleanup()
+
+			app := createTestApp()
+			err := app.Run(context.Background(), []string{"things", "log", "--date", date})
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestLogCommand_DateRange(t *testing.T) {
+	mockOutput := `[{"name":"Task in range","status":"completed","completionDate":"2024-01-12T10:00:00Z"}]`
+	cleanup := setupMockExecutorIntegrationMulti([]string{"SUCCESS", mockOutput}, []error{nil, nil})
+	defer cleanup()
+
+	app := createTestApp()
+	err := app.Run(context.Background(), []string{"things", "log", "--date", "2024-01-10..2024-01-15"})
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestLogCommand_InvertedDateRange(t *testing.T) {
+	cleanup := setupMockExecutorIntegration("", nil)
+	defer cleanup()
+
+	app := createTestApp()
+	err := app.Run(context.Background(), []string{"things", "log", "--date", "2024-01-15..2024-01-10"})
+
+	exitErr, ok := err.(cli.ExitCoder)
+	if !ok {
+		t.Fatalf("expected cli.ExitCoder, got %T (%v)", err, err)
+	}
+	if exitErr.ExitCode() != 1 {
+		t.Errorf("expected exit code 1, got %d", exitErr.ExitCode())
+	}
+	if !strings.Contains(err.Error(), "is before start") {
+		t.Errorf("expected inverted range error, got %q", err.Error())
+	}
+}
+
 func TestLogCommand_InvalidDateFormat(t *testing.T) {
 	tests := []struct {
 		name       string
@@ -781,7 +1265,7 @@ func TestLogCommand_InvalidDateFormat(t *testing.T) {
 	}{
 		{
 			name:       "invalid keyword",
-			dateFilter: "yesterday",
+			dateFilter: "tomorrow",
 		},
 		{
 			name:       "invalid date format",
@@ -869,3 +1353,2879 @@ func TestJSONLOutput_Log(t *testing.T) {
 		t.Errorf("unexpected error: %v", err)
 	}
 }
+
+func TestIgnoreCaseFlag(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{"delete ignore-case", []string{"things", "delete", "--yes", "--list", "Inbox", "--name", "test", "--ignore-case"}},
+		{"move ignore-case", []string{"things", "move", "--yes", "--from", "Inbox", "--to", "Work", "--name", "test", "-i"}},
+		{"rename ignore-case", []string{"things", "rename", "--list", "Inbox", "--name", "test", "--new-name", "New", "-i"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cleanup := setupMockExecutorIntegration("SUCCESS: 2", nil)
+			defer cleanup()
+
+			var out strings.Builder
+			app := createTestAppWithWriters(&out, io.Discard)
+			if err := app.Run(context.Background(), tt.args); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !strings.Contains(out.String(), "2 to-dos matched") {
+				t.Errorf("expected match count in output, got %q", out.String())
+			}
+		})
+	}
+}
+
+func TestAllFlag(t *testing.T) {
+	cleanup := setupMockExecutorIntegration("SUCCESS: 3", nil)
+	defer cleanup()
+
+	var out strings.Builder
+	app := createTestAppWithWriters(&out, io.Discard)
+	err := app.Run(context.Background(), []string{"things", "delete", "--yes", "--list", "Inbox", "--name", "Dup", "--all"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(out.String(), `Deleted 3 to-dos named "Dup" from list "Inbox"!`) {
+		t.Errorf("unexpected output: %q", out.String())
+	}
+}
+
+func TestIndexFlag_Validation(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{"all with index", []string{"things", "delete", "--list", "Inbox", "--name", "Dup", "--all", "--index", "1"}},
+		{"negative index", []string{"things", "rename", "--list", "Inbox", "--name", "Dup", "--new-name", "New", "--index", "-1"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cleanup := setupMockExecutorIntegration("SUCCESS: 2", nil)
+			defer cleanup()
+
+			app := createTestApp()
+			err := app.Run(context.Background(), tt.args)
+			if _, ok := err.(cli.ExitCoder); !ok {
+				t.Errorf("expected cli.ExitCoder, got %T (%v)", err, err)
+			}
+		})
+	}
+}
+
+func TestSearchCommand(t *testing.T) {
+	mockOutput := `[{"name":"Write report","status":"open","area":"Work"},{"name":"Report bug","status":"open","project":"App"}]`
+
+	tests := []struct {
+		name      string
+		args      []string
+		expectErr bool
+	}{
+		{"search by query", []string{"things", "search", "--query", "report"}, false},
+		{"search with alias and jsonl", []string{"things", "f", "-q", "report", "--jsonl"}, false},
+		{"search with status", []string{"things", "search", "-q", "report", "--status", "open"}, false},
+		{"search with invalid status", []string{"things", "search", "-q", "report", "--status", "done"}, true},
+		{"search missing query", []string{"things", "search"}, true},
+		{"search with concurrency", []string{"things", "search", "-q", "report", "--concurrency", "2"}, false},
+		{"search with invalid concurrency", []string{"things", "search", "-q", "report", "--concurrency", "0"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cleanup := setupMockExecutorIntegrationMulti([]string{`["Inbox"]`, mockOutput}, []error{nil, nil})
+			defer cleanup()
+
+			app := createTestApp()
+			err := app.Run(context.Background(), tt.args)
+			if tt.expectErr && err == nil {
+				t.Error("expected error but got none")
+			}
+			if !tt.expectErr && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestSearchCommand_StatusSpelling(t *testing.T) {
+	mockOutput := `[{"name":"Old report","status":"canceled"},{"name":"Write report","status":"open"}]`
+
+	for _, status := range []string{"canceled", "cancelled", "Cancelled"} {
+		t.Run(status, func(t *testing.T) {
+			cleanup := setupMockExecutorIntegrationMulti([]string{`["Inbox"]`, mockOutput}, []error{nil, nil})
+			defer cleanup()
+
+			var out strings.Builder
+			app := createTestAppWithWriters(&out, io.Discard)
+			if err := app.Run(context.Background(), []string{"things", "search", "-q", "report", "--status", status, "--jsonl"}); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if expected := `{"name":"Old report","status":"canceled"}` + "\n"; out.String() != expected {
+				t.Errorf("expected %q, got %q", expected, out.String())
+			}
+		})
+	}
+}
+
+func TestSearchCommand_JSONLOutput(t *testing.T) {
+	cleanup := setupMockExecutorIntegrationMulti([]string{`["Inbox"]`, `[{"name":"Write report","status":"open","area":"Work"}]`}, []error{nil, nil})
+	defer cleanup()
+
+	var out strings.Builder
+	app := createTestAppWithWriters(&out, io.Discard)
+	if err := app.Run(context.Background(), []string{"things", "search", "-q", "report", "--jsonl"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := `{"name":"Write report","status":"open","area":"Work"}` + "\n"
+	if out.String() != expected {
+		t.Errorf("expected %q, got %q", expected, out.String())
+	}
+}
+
+func TestLongOutput(t *testing.T) {
+	mockOutput := `[{"name":"Review PR","status":"open","tagNames":["Work"]}]`
+
+	tests := []struct {
+		name        string
+		args        []string
+		mockOutputs []string
+	}{
+		{"show long", []string{"things", "show", "--list", "Work", "--long"}, []string{mockOutput}},
+		{"show long alias", []string{"things", "show", "--list", "Work", "-L"}, []string{mockOutput}},
+		{"log long", []string{"things", "log", "--date", "today", "--long", "--status", "all"}, []string{"SUCCESS", mockOutput}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cleanup := setupMockExecutorIntegrationMulti(tt.mockOutputs, make([]error, len(tt.mockOutputs)))
+			defer cleanup()
+
+			var out strings.Builder
+			app := createTestAppWithWriters(&out, io.Discard)
+			if err := app.Run(context.Background(), tt.args); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if out.String() != "○ Review PR  #Work\n" {
+				t.Errorf("unexpected output: %q", out.String())
+			}
+		})
+	}
+}
+
+func TestCSVOutput_Show(t *testing.T) {
+	cleanup := setupMockExecutorIntegration(`[{"name":"Task, with comma","status":"open"}]`, nil)
+	defer cleanup()
+
+	var out strings.Builder
+	app := createTestAppWithWriters(&out, io.Discard)
+	if err := app.Run(context.Background(), []string{"things", "show", "--list", "Work", "--csv"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := "name,status,notes,due,tags,area,project\n\"Task, with comma\",open,,,,,\n"
+	if out.String() != expected {
+		t.Errorf("expected %q, got %q", expected, out.String())
+	}
+}
+
+func TestColorFlag(t *testing.T) {
+	mockOutput := `[{"name":"Done task","status":"completed"}]`
+
+	tests := []struct {
+		name      string
+		color     string
+		expected  string
+		expectErr bool
+	}{
+		{"auto on non-terminal", "auto", "✔︎ Done task\n", false},
+		{"always", "always", "\x1b[32m✔︎\x1b[0m Done task\n", false},
+		{"never", "never", "✔︎ Done task\n", false},
+		{"invalid", "sometimes", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cleanup := setupMockExecutorIntegration(mockOutput, nil)
+			defer cleanup()
+
+			var out strings.Builder
+			app := createTestAppWithWriters(&out, io.Discard)
+			err := app.Run(context.Background(), []string{"things", "show", "--list", "Work", "--color", tt.color})
+			if tt.expectErr {
+				if _, ok := err.(cli.ExitCoder); !ok {
+					t.Errorf("expected cli.ExitCoder, got %T (%v)", err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if out.String() != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, out.String())
+			}
+		})
+	}
+}
+
+func TestShowCommand_TagFilter(t *testing.T) {
+	mockOutput := `[{"name":"Task 1","status":"open","tagNames":["Important"]},{"name":"Task 2","status":"open","tagNames":["Home"]},{"name":"Task 3","status":"open"}]`
+
+	tests := []struct {
+		name     string
+		args     []string
+		expected string
+	}{
+		{"single tag", []string{"things", "show", "--list", "Work", "--tag", "Important"}, "○ Task 1\n"},
+		{"repeated tags", []string{"things", "show", "--list", "Work", "--tag", "Important", "--tag", "Home"}, "○ Task 1\n○ Task 2\n"},
+		{"no matching tag", []string{"things", "show", "--list", "Work", "--tag", "Errands"}, "\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cleanup := setupMockExecutorIntegration(mockOutput, nil)
+			defer cleanup()
+
+			var out strings.Builder
+			app := createTestAppWithWriters(&out, io.Discard)
+			if err := app.Run(context.Background(), tt.args); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if out.String() != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, out.String())
+			}
+		})
+	}
+}
+
+func TestShowCommand_StatusFilter(t *testing.T) {
+	mockOutput := `[{"name":"Task 1","status":"open"},{"name":"Task 2","status":"completed"},{"name":"Task 3","status":"canceled"}]`
+
+	tests := []struct {
+		name      string
+		status    string
+		expected  string
+		expectErr bool
+	}{
+		{"open", "open", "○ Task 1\n", false},
+		{"completed", "completed", "✔︎ Task 2\n", false},
+		{"canceled", "canceled", "✕ Task 3\n", false},
+		{"all", "all", "○ Task 1\n✔︎ Task 2\n✕ Task 3\n", false},
+		{"unknown status rejected", "done", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cleanup := setupMockExecutorIntegration(mockOutput, nil)
+			defer cleanup()
+
+			var out strings.Builder
+			app := createTestAppWithWriters(&out, io.Discard)
+			err := app.Run(context.Background(), []string{"things", "show", "--list", "Work", "--status", tt.status})
+			if tt.expectErr {
+				if err == nil || !strings.Contains(err.Error(), "ERROR:") {
+					t.Errorf("expected ERROR: exit, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if out.String() != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, out.String())
+			}
+		})
+	}
+}
+
+func TestShowCommand_DefaultStatusShowsAll(t *testing.T) {
+	cleanup := setupMockExecutorIntegration(`[{"name":"Task 1","status":"open"},{"name":"Task 2","status":"completed"}]`, nil)
+	defer cleanup()
+
+	var out strings.Builder
+	app := createTestAppWithWriters(&out, io.Discard)
+	if err := app.Run(context.Background(), []string{"things", "show", "--list", "Work"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out.String() != "○ Task 1\n✔︎ Task 2\n" {
+		t.Errorf("unexpected output: %q", out.String())
+	}
+}
+
+func TestLimitFlag(t *testing.T) {
+	mockOutput := `[{"name":"Task 1","status":"completed"},{"name":"Task 2","status":"completed"},{"name":"Task 3","status":"completed"}]`
+
+	tests := []struct {
+		name        string
+		args        []string
+		mockOutputs []string
+	}{
+		{"show limit", []string{"things", "show", "--list", "Work", "--limit", "2"}, []string{mockOutput}},
+		{"log limit", []string{"things", "log", "--date", "today", "--limit", "2"}, []string{"SUCCESS", mockOutput}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cleanup := setupMockExecutorIntegrationMulti(tt.mockOutputs, make([]error, len(tt.mockOutputs)))
+			defer cleanup()
+
+			var out strings.Builder
+			app := createTestAppWithWriters(&out, io.Discard)
+			if err := app.Run(context.Background(), tt.args); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if out.String() != "✔︎ Task 1\n✔︎ Task 2\n" {
+				t.Errorf("unexpected output: %q", out.String())
+			}
+		})
+	}
+}
+
+func TestLogCommand_WeekStart(t *testing.T) {
+	tests := []struct {
+		name      string
+		weekStart string
+		expectErr bool
+	}{
+		{"sunday", "sunday", false},
+		{"monday", "monday", false},
+		{"invalid", "friday", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cleanup := setupMockExecutorIntegrationMulti([]string{"SUCCESS", `[]`}, []error{nil, nil})
+			defer cleanup()
+
+			app := createTestApp()
+			err := app.Run(context.Background(), []string{"things", "log", "--date", "this week", "--week-start", tt.weekStart})
+			if tt.expectErr {
+				if _, ok := err.(cli.ExitCoder); !ok {
+					t.Errorf("expected cli.ExitCoder, got %T (%v)", err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+// deadlineExecutor records the deadline of the context each command is run with
+type deadlineExecutor struct {
+	MockExecutor
+	deadlines []time.Time
+}
+
+func (d *deadlineExecutor) ExecuteContext(ctx context.Context, name string, args ...string) ([]byte, error) {
+	deadline, _ := ctx.Deadline()
+	d.deadlines = append(d.deadlines, deadline)
+	return d.MockExecutor.ExecuteContext(ctx, name, args...)
+}
+
+func TestTimeoutFlag(t *testing.T) {
+	tests := []struct {
+		name           string
+		args           []string
+		expectDeadline bool
+		expectWithin   time.Duration
+	}{
+		{
+			name:           "default timeout",
+			args:           []string{"things", "show", "--list", "Today"},
+			expectDeadline: true,
+			expectWithin:   defaultTimeout,
+		},
+		{
+			name:           "custom timeout",
+			args:           []string{"things", "--timeout", "5s", "show", "--list", "Today"},
+			expectDeadline: true,
+			expectWithin:   5 * time.Second,
+		},
+		{
+			name:           "timeout disabled",
+			args:           []string{"things", "--timeout", "0", "show", "--list", "Today"},
+			expectDeadline: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cleanup := setupMockExecutorIntegration(`[]`, nil)
+			defer cleanup()
+			recorder := &deadlineExecutor{MockExecutor: MockExecutor{outputs: [][]byte{[]byte(`[]`)}, errors: []error{nil}}}
+			executor = recorder
+
+			start := time.Now()
+			app := createTestApp()
+			if err := app.Run(context.Background(), tt.args); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			end := time.Now()
+
+			if len(recorder.deadlines) != 1 {
+				t.Fatalf("expected 1 call, got %d", len(recorder.deadlines))
+			}
+			deadline := recorder.deadlines[0]
+			if !tt.expectDeadline {
+				if !deadline.IsZero() {
+					t.Errorf("expected no deadline, got %v", deadline)
+				}
+				return
+			}
+			if deadline.Before(start.Add(tt.expectWithin)) || deadline.After(end.Add(tt.expectWithin)) {
+				t.Errorf("expected deadline %v after the run started, got %v", tt.expectWithin, deadline)
+			}
+		})
+	}
+}
+
+func TestAddCommand_Batch(t *testing.T) {
+	tests := []struct {
+		name         string
+		args         []string
+		stdin        string
+		output       string
+		expectErr    bool
+		expectOutput string
+		expectCalls  int
+	}{
+		{
+			name:         "repeated names",
+			args:         []string{"things", "add", "--batch", "--list", "Work", "--name", "One", "--name", "Two, with a comma"},
+			output:       `[{"success":true},{"success":true}]`,
+			expectOutput: "To-do \"One\" added successfully to list \"Work\"!\nTo-do \"Two, with a comma\" added successfully to list \"Work\"!\n",
+			expectCalls:  1,
+		},
+		{
+			name:         "names from stdin",
+			args:         []string{"things", "add", "--batch"},
+			stdin:        "One\n\n  Two  \n",
+			output:       `[{"success":true},{"success":true}]`,
+			expectOutput: "To-do \"One\" added successfully to list \"inbox\"!\nTo-do \"Two\" added successfully to list \"inbox\"!\n",
+			expectCalls:  1,
+		},
+		{
+			name:         "partial failure",
+			args:         []string{"things", "add", "--batch", "--name", "One", "--name", "Two"},
+			output:       `[{"success":true},{"success":false,"message":"ERROR: nope"}]`,
+			expectErr:    true,
+			expectOutput: "To-do \"One\" added successfully to list \"inbox\"!\n",
+			expectCalls:  1,
+		},
+		{
+			name:      "empty stdin",
+			args:      []string{"things", "add", "--batch"},
+			expectErr: true,
+		},
+		{
+			name:      "several names without batch",
+			args:      []string{"things", "add", "--name", "One", "--name", "Two"},
+			expectErr: true,
+		},
+		{
+			name:      "no name without batch",
+			args:      []string{"things", "add"},
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cleanup := setupMockExecutorIntegration(tt.output, nil)
+			defer cleanup()
+
+			var out strings.Builder
+			app := createTestAppWithWriters(&out, io.Discard)
+			app.Reader = strings.NewReader(tt.stdin)
+			err := app.Run(context.Background(), tt.args)
+
+			if tt.expectErr {
+				if _, ok := err.(cli.ExitCoder); !ok {
+					t.Errorf("expected cli.ExitCoder, got %T (%v)", err, err)
+				}
+			} else if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if out.String() != tt.expectOutput {
+				t.Errorf("expected output %q, got %q", tt.expectOutput, out.String())
+			}
+			if calls := len(executor.(*MockExecutor).calls); calls != tt.expectCalls {
+				t.Errorf("expected %d osascript calls, got %d", tt.expectCalls, calls)
+			}
+		})
+	}
+}
+
+func TestAddCommand_Stdin(t *testing.T) {
+	tests := []struct {
+		name         string
+		args         []string
+		stdin        string
+		output       string
+		expectErr    bool
+		expectOutput string
+	}{
+		{
+			name:         "one to-do per line",
+			args:         []string{"things", "add", "--list", "Work", "--stdin"},
+			stdin:        "Buy milk\n\nCall Sam\n   \nFile taxes\n",
+			output:       `[{"success":true},{"success":true},{"success":true}]`,
+			expectOutput: "Added 3 to-dos to list \"Work\"\n",
+		},
+		{
+			name:         "name is ignored",
+			args:         []string{"things", "add", "--list", "Work", "--stdin", "--name", "Ignored"},
+			stdin:        "Only line\n",
+			output:       `[{"success":true}]`,
+			expectOutput: "Added 1 to-dos to list \"Work\"\n",
+		},
+		{
+			name:         "failures are excluded from the count",
+			args:         []string{"things", "add", "--list", "Work", "--stdin"},
+			stdin:        "One\nTwo\n",
+			output:       `[{"success":true},{"success":false,"message":"ERROR: nope"}]`,
+			expectErr:    true,
+			expectOutput: "Added 1 to-dos to list \"Work\"\n",
+		},
+		{
+			name:      "only blank lines",
+			args:      []string{"things", "add", "--list", "Work", "--stdin"},
+			stdin:     "\n  \n",
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cleanup := setupMockExecutorIntegration(tt.output, nil)
+			defer cleanup()
+
+			var out strings.Builder
+			app := createTestAppWithWriters(&out, io.Discard)
+			app.Reader = strings.NewReader(tt.stdin)
+			err := app.Run(context.Background(), tt.args)
+
+			if tt.expectErr {
+				if _, ok := err.(cli.ExitCoder); !ok {
+					t.Errorf("expected cli.ExitCoder, got %T (%v)", err, err)
+				}
+			} else if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if out.String() != tt.expectOutput {
+				t.Errorf("expected output %q, got %q", tt.expectOutput, out.String())
+			}
+		})
+	}
+}
+
+func TestAddCommand_StdinJSONL(t *testing.T) {
+	cleanup := setupMockExecutorIntegration(`[{"success":true,"id":"T1"},{"success":false,"message":"ERROR: nope"},{"success":true,"id":"T3"}]`, nil)
+	defer cleanup()
+
+	var out strings.Builder
+	app := createTestAppWithWriters(&out, io.Discard)
+	app.Reader = strings.NewReader("Buy milk\n\nCall Sam\nFile taxes\n")
+	err := app.Run(context.Background(), []string{"things", "add", "--list", "Work", "--stdin", "--jsonl"})
+	if exitErr, ok := err.(cli.ExitCoder); !ok || exitErr.ExitCode() != exitFailure {
+		t.Errorf("expected exit code %d for the failed to-do, got %v", exitFailure, err)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+	expected := []struct {
+		name    string
+		success bool
+		ids     []string
+	}{
+		{name: "Buy milk", success: true, ids: []string{"T1"}},
+		{name: "Call Sam", success: false},
+		{name: "File taxes", success: true, ids: []string{"T3"}},
+	}
+	if len(lines) != len(expected) {
+		t.Fatalf("expected %d JSON lines, got %d:\n%s", len(expected), len(lines), out.String())
+	}
+	for i, line := range lines {
+		var result struct {
+			Name    string   `json:"name"`
+			Success bool     `json:"success"`
+			Message string   `json:"message"`
+			IDs     []string `json:"ids"`
+		}
+		if err := json.Unmarshal([]byte(line), &result); err != nil {
+			t.Fatalf("line %d isn't JSON: %v\n%s", i+1, err, line)
+		}
+		if result.Name != expected[i].name || result.Success != expected[i].success || !slices.Equal(result.IDs, expected[i].ids) {
+			t.Errorf("line %d: expected %+v, got %+v", i+1, expected[i], result)
+		}
+	}
+	if !strings.Contains(lines[1], `"message":"ERROR: nope (to-do \"Call Sam\")"`) {
+		t.Errorf("expected the failure's message on its line, got %s", lines[1])
+	}
+}
+
+func TestAddCommand_JSONLFlags(t *testing.T) {
+	cleanup := setupMockExecutorIntegration(`[{"success":true}]`, nil)
+	defer cleanup()
+
+	app := createTestAppWithWriters(io.Discard, io.Discard)
+	err := app.Run(context.Background(), []string{"things", "add", "--name", "Task", "--jsonl"})
+	expected := "ERROR: --jsonl requires --batch or --stdin; use --json for a single to-do"
+	if err == nil || err.Error() != expected {
+		t.Errorf("expected error %q, got %v", expected, err)
+	}
+}
+
+func TestReadTodoNames(t *testing.T) {
+	names, err := readTodoNames(strings.NewReader("  One  \n\nTwo\r\n\t\nThree"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []string{"One", "Two", "Three"}
+	if strings.Join(names, "|") != strings.Join(expected, "|") {
+		t.Errorf("expected %v, got %v", expected, names)
+	}
+}
+
+func TestImportCommand(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "todos.jsonl")
+	if err := os.WriteFile(path, []byte("{\"name\":\"One\"}\nbroken\n{\"name\":\"Two\"}\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name         string
+		args         []string
+		stdin        string
+		expectErr    bool
+		expectOutput string
+	}{
+		{
+			name:         "from file",
+			args:         []string{"things", "import", "--file", path, "--list", "Work"},
+			expectOutput: "Imported 2 to-dos to list \"Work\" (1 skipped)\n",
+		},
+		{
+			name:         "from stdin",
+			stdin:        "{\"name\":\"One\"}\n{\"name\":\"Two\"}\n",
+			args:         []string{"things", "import"},
+			expectOutput: "Imported 2 to-dos to list \"inbox\" (0 skipped)\n",
+		},
+		{
+			name:      "strict",
+			args:      []string{"things", "import", "--file", path, "--strict"},
+			expectErr: true,
+		},
+		{
+			name:      "missing file",
+			args:      []string{"things", "import", "--file", filepath.Join(t.TempDir(), "missing.jsonl")},
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cleanup := setupMockExecutorIntegration(`[{"success":true},{"success":true}]`, nil)
+			defer cleanup()
+
+			var out strings.Builder
+			app := createTestAppWithWriters(&out, io.Discard)
+			app.Reader = strings.NewReader(tt.stdin)
+			err := app.Run(context.Background(), tt.args)
+
+			if tt.expectErr {
+				if _, ok := err.(cli.ExitCoder); !ok {
+					t.Errorf("expected cli.ExitCoder, got %T (%v)", err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if out.String() != tt.expectOutput {
+				t.Errorf("expected output %q, got %q", tt.expectOutput, out.String())
+			}
+		})
+	}
+}
+
+func TestAddCommand_Via(t *testing.T) {
+	tests := []struct {
+		name          string
+		args          []string
+		expectErr     bool
+		expectCommand string
+	}{
+		{"script by default", []string{"things", "add", "--name", "Task"}, false, "osascript"},
+		{"url", []string{"things", "add", "--name", "Task", "--via", "url"}, false, "open"},
+		{"invalid backend", []string{"things", "add", "--name", "Task", "--via", "carrier-pigeon"}, true, ""},
+		{"url with batch", []string{"things", "add", "--name", "Task", "--via", "url", "--batch"}, true, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cleanup := setupMockExecutorIntegration("SUCCESS", nil)
+			defer cleanup()
+
+			app := createTestApp()
+			err := app.Run(context.Background(), tt.args)
+
+			calls := executor.(*MockExecutor).calls
+			if tt.expectErr {
+				if _, ok := err.(cli.ExitCoder); !ok {
+					t.Errorf("expected cli.ExitCoder, got %T (%v)", err, err)
+				}
+				if len(calls) != 0 {
+					t.Errorf("expected no calls, got %d", len(calls))
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(calls) != 1 || calls[0][0] != tt.expectCommand {
+				t.Errorf("expected one %s call, got %v", tt.expectCommand, calls)
+			}
+		})
+	}
+}
+
+func TestAddCommand_Project(t *testing.T) {
+	tests := []struct {
+		name      string
+		args      []string
+		expectErr bool
+	}{
+		{"project", []string{"things", "add", "--name", "Task", "--project", "Redesign"}, false},
+		{"project alias", []string{"things", "add", "-n", "Task", "-p", "Redesign"}, false},
+		{"with list", []string{"things", "add", "--name", "Task", "--list", "Today", "--project", "Redesign"}, true},
+		{"with batch", []string{"things", "add", "--name", "Task", "--project", "Redesign", "--batch"}, true},
+		{"with url", []string{"things", "add", "--name", "Task", "--project", "Redesign", "--via", "url"}, true},
+		{"with heading", []string{"things", "add", "--name", "Task", "--project", "Redesign", "--heading", "Drafts"}, false},
+		{"heading without project", []string{"things", "add", "--name", "Task", "--heading", "Drafts"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cleanup := setupMockExecutorIntegration("SUCCESS", nil)
+			defer cleanup()
+
+			var out strings.Builder
+			app := createTestAppWithWriters(&out, io.Discard)
+			err := app.Run(context.Background(), tt.args)
+
+			mock := executor.(*MockExecutor)
+			if tt.expectErr {
+				if _, ok := err.(cli.ExitCoder); !ok {
+					t.Errorf("expected cli.ExitCoder, got %T (%v)", err, err)
+				}
+				if len(mock.calls) != 0 {
+					t.Errorf("expected no calls, got %d", len(mock.calls))
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !strings.Contains(mock.lastScript(), "app.projects.byName('Redesign')") {
+				t.Errorf("expected the project to be targeted, got:\n%s", mock.lastScript())
+			}
+			if !strings.Contains(out.String(), `To-do added successfully to `) || !strings.Contains(out.String(), `project "Redesign"!`) {
+				t.Errorf("expected project confirmation, got %q", out.String())
+			}
+		})
+	}
+}
+
+func TestOpenCommand(t *testing.T) {
+	cleanup := setupMockExecutorIntegrationMulti([]string{"SUCCESS: 1 ABC123", ""}, []error{nil, nil})
+	defer cleanup()
+
+	var out strings.Builder
+	app := createTestAppWithWriters(&out, io.Discard)
+	if err := app.Run(context.Background(), []string{"things", "open", "--list", "Today", "--name", "Task"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(out.String(), `Opened to-do "Task"`) {
+		t.Errorf("expected open confirmation, got %q", out.String())
+	}
+}
+
+func TestOpenCommand_NotFound(t *testing.T) {
+	cleanup := setupMockExecutorIntegration("ERROR: To-do not found in list", nil)
+	defer cleanup()
+
+	app := createTestApp()
+	err := app.Run(context.Background(), []string{"things", "open", "--list", "Today", "--name", "Missing"})
+	if _, ok := err.(cli.ExitCoder); !ok {
+		t.Errorf("expected cli.ExitCoder, got %T (%v)", err, err)
+	}
+}
+
+func TestParseChecklist(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected []string
+	}{
+		{"", nil},
+		{"Eggs", []string{"Eggs"}},
+		{"Eggs|Milk", []string{"Eggs", "Milk"}},
+		{"Eggs\nMilk | Bread\n\n", []string{"Eggs", "Milk", "Bread"}},
+		{" | ", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			result := parseChecklist(tt.value)
+			if strings.Join(result, "|") != strings.Join(tt.expected, "|") || len(result) != len(tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestAddCommand_Checklist(t *testing.T) {
+	cleanup := setupMockExecutorIntegration("SUCCESS", nil)
+	defer cleanup()
+
+	app := createTestApp()
+	err := app.Run(context.Background(), []string{"things", "add", "--name", "Groceries", "--checklist", "Eggs|Milk"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	script := executor.(*MockExecutor).lastScript()
+	if !strings.Contains(script, "checklistItems: ['Eggs', 'Milk']") {
+		t.Errorf("expected checklist items in script:\n%s", script)
+	}
+}
+
+func TestAddCommand_Someday(t *testing.T) {
+	tests := []struct {
+		name         string
+		args         []string
+		output       string
+		expectErr    string
+		expectOutput string
+		expectCall   string
+	}{
+		{
+			name:         "script",
+			args:         []string{"things", "add", "--name", "Learn Rust", "--someday"},
+			output:       `SUCCESS: 1 ["T1"]`,
+			expectOutput: "To-do added successfully to list \"Someday\"!\n",
+			expectCall:   "app.lists.byName('Someday')",
+		},
+		{
+			name:         "url",
+			args:         []string{"things", "add", "--name", "Learn Rust", "--someday", "--via", "url"},
+			expectOutput: "To-do sent to list \"Someday\" via the Things URL scheme!\n",
+			expectCall:   "things:///add?title=Learn%20Rust&when=someday",
+		},
+		{
+			name:      "with list",
+			args:      []string{"things", "add", "--name", "Learn Rust", "--someday", "--list", "Today"},
+			expectErr: "ERROR: --someday can't be combined with --list or --project",
+		},
+		{
+			name:      "with a scheduled date",
+			args:      []string{"things", "add", "--someday", "--input-json", `{"name":"Learn Rust","activationDate":"2026-11-01T09:00:00Z"}`},
+			expectErr: "ERROR: --someday can't be combined with an activationDate in --input-json",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cleanup := setupMockExecutorIntegration(tt.output, nil)
+			defer cleanup()
+
+			var out strings.Builder
+			app := createTestAppWithWriters(&out, io.Discard)
+			err := app.Run(context.Background(), tt.args)
+			if tt.expectErr != "" {
+				if err == nil || err.Error() != tt.expectErr {
+					t.Errorf("expected error %q, got %v", tt.expectErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if out.String() != tt.expectOutput {
+				t.Errorf("expected %q, got %q", tt.expectOutput, out.String())
+			}
+			if call := executor.(*MockExecutor).lastScript(); !strings.Contains(call, tt.expectCall) {
+				t.Errorf("expected %q in the last call, got:\n%s", tt.expectCall, call)
+			}
+		})
+	}
+}
+
+func TestAddCommand_DefaultListEnv(t *testing.T) {
+	tests := []struct {
+		name       string
+		config     string
+		args       []string
+		expectCall string
+	}{
+		{
+			name:       "replaces inbox",
+			args:       []string{"things", "add", "--name", "Task"},
+			expectCall: "app.lists.byName('Work')",
+		},
+		{
+			name:       "beats the config file",
+			config:     `{"list": "Errands"}`,
+			args:       []string{"things", "add", "--name", "Task"},
+			expectCall: "app.lists.byName('Work')",
+		},
+		{
+			name:       "loses to --list",
+			args:       []string{"things", "add", "--name", "Task", "--list", "Today"},
+			expectCall: "app.lists.byName('Today')",
+		},
+		{
+			name:       "loses to --someday",
+			args:       []string{"things", "add", "--name", "Task", "--someday"},
+			expectCall: "app.lists.byName('Someday')",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("THINGS_DEFAULT_LIST", "Work")
+			if tt.config != "" {
+				useConfig(t, tt.config)
+			}
+			cleanup := setupMockExecutorIntegration(`SUCCESS: 1 ["T1"]`, nil)
+			defer cleanup()
+
+			app := createTestAppWithWriters(io.Discard, io.Discard)
+			if err := app.Run(context.Background(), tt.args); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if call := executor.(*MockExecutor).lastScript(); !strings.Contains(call, tt.expectCall) {
+				t.Errorf("expected %q in the last call, got:\n%s", tt.expectCall, call)
+			}
+		})
+	}
+}
+
+func TestAddCommand_InputJSON(t *testing.T) {
+	spec := `{"name":"Plan trip","notes":"Book \"early\"","tagNames":["Travel","Home, Garden"],"dueDate":"2026-11-01","activationDate":"2026-10-20T09:00:00Z","checklistItems":[{"name":"Flights","completed":true},{"name":"Hotel"}]}`
+
+	tests := []struct {
+		name      string
+		args      []string
+		stdin     string
+		expectErr string
+	}{
+		{name: "spec on the flag", args: []string{"things", "add", "--list", "Work", "--input-json", spec}},
+		{name: "spec on stdin", args: []string{"things", "add", "--list", "Work", "--input-json", "-"}, stdin: spec},
+		{name: "missing name", args: []string{"things", "add", "--input-json", `{"notes":"No name"}`}, expectErr: `ERROR: --input-json needs a non-empty "name"`},
+		{name: "invalid JSON", args: []string{"things", "add", "--input-json", `{"name":`}, expectErr: "ERROR: invalid --input-json: unexpected end of JSON input"},
+		{name: "with name", args: []string{"things", "add", "--input-json", spec, "--name", "Other"}, expectErr: "ERROR: --input-json can't be combined with --name, --tags, --tag, or --checklist"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cleanup := setupMockExecutorIntegration(`[{"success":true}]`, nil)
+			defer cleanup()
+
+			var out strings.Builder
+			app := createTestAppWithWriters(&out, io.Discard)
+			app.Reader = strings.NewReader(tt.stdin)
+			err := app.Run(context.Background(), tt.args)
+
+			if tt.expectErr != "" {
+				if err == nil || err.Error() != tt.expectErr {
+					t.Fatalf("expected error %q, got %v", tt.expectErr, err)
+				}
+				if calls := len(executor.(*MockExecutor).calls); calls != 0 {
+					t.Errorf("expected no script to run, got %d calls", calls)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if expected := "To-do \"Plan trip\" added successfully to list \"Work\"!\n"; out.String() != expected {
+				t.Errorf("expected %q, got %q", expected, out.String())
+			}
+
+			script := executor.(*MockExecutor).lastScript()
+			for _, want := range []string{
+				`"name":"Plan trip"`,
+				`"tags":["Travel","Home, Garden"]`,
+				`"notes":"Book \"early\""`,
+				`"checklistItems":["Flights","Hotel"]`,
+				`"dueDate":"2026-11-01T00:00:00`,
+				`"activationDate":"2026-10-20T09:00:00Z"`,
+			} {
+				if !strings.Contains(script, want) {
+					t.Errorf("expected script to contain %s:\n%s", want, script)
+				}
+			}
+		})
+	}
+}
+
+func TestIDFlag(t *testing.T) {
+	tests := []struct {
+		name      string
+		args      []string
+		output    string
+		expectErr bool
+	}{
+		{"delete by id", []string{"things", "delete", "--yes", "--id", "ABC123"}, "SUCCESS: Task", false},
+		{"complete by id", []string{"things", "complete", "--id", "ABC123"}, "SUCCESS: Task", false},
+		{"rename by id", []string{"things", "rename", "--id", "ABC123", "--new-name", "New"}, "SUCCESS: Task", false},
+		{"complete by name", []string{"things", "complete", "--list", "Today", "--name", "Task"}, "SUCCESS: 1", false},
+		{"invalid id", []string{"things", "delete", "--yes", "--id", "missing"}, "ERROR: To-do not found", true},
+		{"no id, list, or name", []string{"things", "complete"}, "", true},
+		{"name without list", []string{"things", "delete", "--name", "Task"}, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cleanup := setupMockExecutorIntegration(tt.output, nil)
+			defer cleanup()
+
+			app := createTestApp()
+			err := app.Run(context.Background(), tt.args)
+			if tt.expectErr {
+				if _, ok := err.(cli.ExitCoder); !ok {
+					t.Errorf("expected cli.ExitCoder, got %T (%v)", err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestCompleteCommand_Log(t *testing.T) {
+	tests := []struct {
+		name          string
+		args          []string
+		outputs       []string
+		expectErr     string
+		expectOutput  string
+		expectScripts []string
+	}{
+		{
+			name:          "complete then log",
+			args:          []string{"things", "complete", "--list", "Today", "--name", "Task", "--log"},
+			outputs:       []string{"SUCCESS: 1", "SUCCESS"},
+			expectOutput:  "Completed to-dos moved to the Logbook!",
+			expectScripts: []string{"todos[i].status = 'completed'", "app.logCompletedNow();"},
+		},
+		{
+			name:          "complete by id then log",
+			args:          []string{"things", "complete", "--id", "ABC123", "--log"},
+			outputs:       []string{"SUCCESS: Task", "SUCCESS"},
+			expectOutput:  "Completed to-dos moved to the Logbook!",
+			expectScripts: []string{"todo.status = 'completed';", "app.logCompletedNow();"},
+		},
+		{
+			name:          "log fails after completing",
+			args:          []string{"things", "complete", "--list", "Today", "--name", "Task", "--log"},
+			outputs:       []string{"SUCCESS: 1", "ERROR: Logbook unavailable"},
+			expectErr:     "ERROR: To-do completed, but moving it to the Logbook failed: Logbook unavailable",
+			expectOutput:  `To-do "Task" completed`,
+			expectScripts: []string{"todos[i].status = 'completed'", "app.logCompletedNow();"},
+		},
+		{
+			name:          "complete fails",
+			args:          []string{"things", "complete", "--list", "Today", "--name", "Task", "--log"},
+			outputs:       []string{"ERROR: To-do not found in list"},
+			expectErr:     `ERROR: To-do "Task" not found in list "Today"`,
+			expectScripts: []string{"todos[i].status = 'completed'"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cleanup := setupMockExecutorIntegrationMulti(tt.outputs, make([]error, len(tt.outputs)))
+			defer cleanup()
+
+			var out strings.Builder
+			app := createTestAppWithWriters(&out, io.Discard)
+			err := app.Run(context.Background(), tt.args)
+			if tt.expectErr != "" {
+				if err == nil || err.Error() != tt.expectErr {
+					t.Errorf("expected error %q, got %v", tt.expectErr, err)
+				}
+			} else if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !strings.Contains(out.String(), tt.expectOutput) {
+				t.Errorf("expected output to contain %q, got %q", tt.expectOutput, out.String())
+			}
+
+			calls := executor.(*MockExecutor).calls
+			if len(calls) != len(tt.expectScripts) {
+				t.Fatalf("expected %d scripts to run, got %d", len(tt.expectScripts), len(calls))
+			}
+			for i, expected := range tt.expectScripts {
+				if script := calls[i][len(calls[i])-1]; !strings.Contains(script, expected) {
+					t.Errorf("script %d: expected %q, got:\n%s", i, expected, script)
+				}
+			}
+		})
+	}
+}
+
+func TestMoveCommand_ToProject(t *testing.T) {
+	tests := []struct {
+		name      string
+		args      []string
+		expectErr bool
+	}{
+		{"to project", []string{"things", "move", "--yes", "--from", "Inbox", "--to-project", "Redesign", "--name", "Task"}, false},
+		{"to list", []string{"things", "move", "--yes", "--from", "Inbox", "--to", "Today", "--name", "Task"}, false},
+		{"both destinations", []string{"things", "move", "--from", "Inbox", "--to", "Today", "--to-project", "Redesign", "--name", "Task"}, true},
+		{"no destination", []string{"things", "move", "--from", "Inbox", "--name", "Task"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cleanup := setupMockExecutorIntegration("SUCCESS: 1", nil)
+			defer cleanup()
+
+			app := createTestApp()
+			err := app.Run(context.Background(), tt.args)
+			if tt.expectErr {
+				if _, ok := err.(cli.ExitCoder); !ok {
+					t.Errorf("expected cli.ExitCoder, got %T (%v)", err, err)
+				}
+				if calls := len(executor.(*MockExecutor).calls); calls != 0 {
+					t.Errorf("expected no osascript calls, got %d", calls)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestTodayCommand(t *testing.T) {
+	mockOutput := `[{"name":"Task 1","status":"open"},{"name":"Task 2","status":"completed"}]`
+
+	tests := []struct {
+		name     string
+		args     []string
+		output   string
+		expected string
+	}{
+		{"with header", []string{"things", "today"}, mockOutput, "Today (2 to-dos):\n○ Task 1\n✔︎ Task 2\n"},
+		{"alias", []string{"things", "t"}, mockOutput, "Today (2 to-dos):\n○ Task 1\n✔︎ Task 2\n"},
+		{"empty list", []string{"things", "today"}, `[]`, "Today (no to-dos)\n"},
+		{"jsonl has no header", []string{"things", "today", "--jsonl"}, `[{"name":"Task 1","status":"open"}]`, `{"name":"Task 1","status":"open"}` + "\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cleanup := setupMockExecutorIntegration(tt.output, nil)
+			defer cleanup()
+
+			var out strings.Builder
+			app := createTestAppWithWriters(&out, io.Discard)
+			if err := app.Run(context.Background(), tt.args); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if out.String() != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, out.String())
+			}
+			if script := executor.(*MockExecutor).lastScript(); !strings.Contains(script, "byName('Today')") {
+				t.Error("expected the Today list to be read")
+			}
+		})
+	}
+}
+
+func TestUpcomingCommand(t *testing.T) {
+	mockOutput := `[{"name":"Later","status":"open","activationDate":"2024-01-19T12:00:00Z"},{"name":"Sooner","status":"open","activationDate":"2024-01-18T12:00:00Z"}]`
+
+	tests := []struct {
+		name     string
+		args     []string
+		contains []string
+	}{
+		{"grouped", []string{"things", "upcoming"}, []string{"○ Sooner\n\n", "○ Later"}},
+		{"alias", []string{"things", "u"}, []string{"○ Sooner\n\n", "○ Later"}},
+		{"jsonl is ungrouped", []string{"things", "upcoming", "--jsonl"}, []string{`"name":"Later"`, `"name":"Sooner"`}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cleanup := setupMockExecutorIntegration(mockOutput, nil)
+			defer cleanup()
+
+			var out strings.Builder
+			app := createTestAppWithWriters(&out, io.Discard)
+			if err := app.Run(context.Background(), tt.args); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			for _, expected := range tt.contains {
+				if !strings.Contains(out.String(), expected) {
+					t.Errorf("expected output to contain %q, got %q", expected, out.String())
+				}
+			}
+			if script := executor.(*MockExecutor).lastScript(); !strings.Contains(script, "byName('Upcoming')") {
+				t.Error("expected the Upcoming list to be read")
+			}
+		})
+	}
+}
+
+func TestLogCommand_GroupBy(t *testing.T) {
+	mockOutput := `[{"name":"Ship it","status":"completed","area":"Work"},{"name":"Loose end","status":"completed"}]`
+
+	tests := []struct {
+		name      string
+		args      []string
+		expectErr bool
+		expected  string
+	}{
+		{"area", []string{"--group-by", "area"}, false, "Work:\n✔︎ Ship it\n\n(none):\n✔︎ Loose end\n"},
+		{"project", []string{"--group-by", "project"}, false, "(none):\n✔︎ Ship it\n✔︎ Loose end\n"},
+		{"color", []string{"--group-by", "area", "--color", "always"}, false, "Work:\n\x1b[32m✔︎\x1b[0m Ship it\n\n(none):\n\x1b[32m✔︎\x1b[0m Loose end\n"},
+		{"width", []string{"--group-by", "area", "--width", "8"}, false, "Work:\n✔︎ Ship\n  it\n\n(none):\n✔︎ Loose\n  end\n"},
+		{"invalid", []string{"--group-by", "tag"}, true, ""},
+		{"invalid color", []string{"--group-by", "area", "--color", "sometimes"}, true, ""},
+		{"invalid width", []string{"--group-by", "area", "--width", "wide"}, true, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cleanup := setupMockExecutorIntegrationMulti([]string{"SUCCESS", mockOutput}, []error{nil, nil})
+			defer cleanup()
+
+			var out strings.Builder
+			app := createTestAppWithWriters(&out, io.Discard)
+			err := app.Run(context.Background(), append([]string{"things", "log", "--date", "today"}, tt.args...))
+			if tt.expectErr {
+				if _, ok := err.(cli.ExitCoder); !ok {
+					t.Errorf("expected cli.ExitCoder, got %T (%v)", err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if out.String() != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, out.String())
+			}
+		})
+	}
+}
+
+func TestDryRunFlag(t *testing.T) {
+	tests := []struct {
+		name         string
+		args         []string
+		expectOutput []string
+	}{
+		{
+			name:         "add",
+			args:         []string{"things", "--dry-run", "add", "--list", "Work", "--name", "New task"},
+			expectOutput: []string{"app.lists.byName('Work')", `Dry run: would add to-do "New task" to list "Work"`},
+		},
+		{
+			name:         "add via url",
+			args:         []string{"things", "--dry-run", "add", "--list", "Today", "--name", "New task", "--via", "url"},
+			expectOutput: []string{"open things:///add?", `Dry run: would add to-do "New task" to list "Today"`},
+		},
+		{
+			name: "batch add",
+			args: []string{"things", "--dry-run", "add", "--list", "Work", "--name", "One", "--name", "Two", "--batch"},
+			expectOutput: []string{
+				`Dry run: would add to-do "One" to list "Work"`,
+				`Dry run: would add to-do "Two" to list "Work"`,
+			},
+		},
+		{
+			name:         "delete",
+			args:         []string{"things", "--dry-run", "delete", "--list", "Work", "--name", "Old task", "--all"},
+			expectOutput: []string{`Dry run: would delete every to-do named "Old task" from list "Work"`},
+		},
+		{
+			name:         "delete by id",
+			args:         []string{"things", "--dry-run", "delete", "--id", "abc123"},
+			expectOutput: []string{"findTodoById(app, 'abc123')", `Dry run: would delete the to-do with id "abc123"`},
+		},
+		{
+			name:         "move",
+			args:         []string{"things", "--dry-run", "move", "--from", "Inbox", "--name", "Task", "--to", "Today"},
+			expectOutput: []string{`Dry run: would move the to-do named "Task" from list "Inbox" to list "Today"`},
+		},
+		{
+			name:         "rename",
+			args:         []string{"things", "--dry-run", "rename", "--list", "Work", "--name", "Old", "--new-name", "New"},
+			expectOutput: []string{`Dry run: would rename the to-do named "Old" in list "Work" to "New"`},
+		},
+		{
+			name:         "complete",
+			args:         []string{"things", "--dry-run", "complete", "--list", "Work", "--name", "Task", "--index", "2"},
+			expectOutput: []string{`Dry run: would complete to-do 3 of those named "Task" in list "Work"`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cleanup := setupMockExecutorIntegration("SUCCESS", nil)
+			defer cleanup()
+			defer func() { dryRun = false }()
+
+			var out strings.Builder
+			app := createTestAppWithWriters(&out, io.Discard)
+			if err := app.Run(context.Background(), tt.args); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if calls := len(executor.(*MockExecutor).calls); calls != 0 {
+				t.Errorf("expected no executor calls, got %d", calls)
+			}
+			for _, expected := range tt.expectOutput {
+				if !strings.Contains(out.String(), expected) {
+					t.Errorf("expected output containing %q, got %q", expected, out.String())
+				}
+			}
+		})
+	}
+}
+
+func TestDeleteConfirmation(t *testing.T) {
+	lookup := `{"selected":["Test Todo"],"total":1}`
+	tests := []struct {
+		name         string
+		args         []string
+		input        string
+		outputs      []string
+		expectCalls  int
+		expectOutput []string
+		expectErr    string
+	}{
+		{
+			name:         "answer yes",
+			args:         []string{"things", "delete", "--list", "Inbox", "--name", "test todo", "-i"},
+			input:        "y\n",
+			outputs:      []string{lookup, "SUCCESS: 1"},
+			expectCalls:  2,
+			expectOutput: []string{`Delete "Test Todo"? [y/N]`, `deleted successfully`},
+		},
+		{
+			name:         "answer no",
+			args:         []string{"things", "delete", "--list", "Inbox", "--name", "Test Todo"},
+			input:        "n\n",
+			outputs:      []string{lookup},
+			expectCalls:  1,
+			expectOutput: []string{`Delete "Test Todo"? [y/N]`},
+			expectErr:    "Aborted; nothing was deleted",
+		},
+		{
+			name:        "no answer",
+			args:        []string{"things", "delete", "--list", "Inbox", "--name", "Test Todo"},
+			input:       "",
+			outputs:     []string{lookup},
+			expectCalls: 1,
+			expectErr:   "Aborted; nothing was deleted",
+		},
+		{
+			name:         "all matches",
+			args:         []string{"things", "delete", "--list", "Inbox", "--name", "Dup", "--all"},
+			input:        "yes\n",
+			outputs:      []string{`{"selected":["Dup","Dup"],"total":2}`, "SUCCESS: 2"},
+			expectCalls:  2,
+			expectOutput: []string{`Delete 2 to-dos named "Dup"? [y/N]`, "Deleted 2 to-dos"},
+		},
+		{
+			name:         "by id",
+			args:         []string{"things", "delete", "--id", "ABC123"},
+			input:        "y\n",
+			outputs:      []string{"SUCCESS: Task", "SUCCESS: Task"},
+			expectCalls:  2,
+			expectOutput: []string{`Delete "Task"? [y/N]`, `To-do "Task" deleted successfully!`},
+		},
+		{
+			name:         "yes flag skips the prompt",
+			args:         []string{"things", "delete", "--list", "Inbox", "--name", "Test Todo", "--yes"},
+			outputs:      []string{"SUCCESS: 1"},
+			expectCalls:  1,
+			expectOutput: []string{`deleted successfully`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := make([]error, len(tt.outputs))
+			cleanup := setupMockExecutorIntegrationMulti(tt.outputs, errs)
+			defer cleanup()
+
+			var out strings.Builder
+			app := createTestAppWithWriters(&out, io.Discard)
+			app.Reader = strings.NewReader(tt.input)
+			err := app.Run(context.Background(), tt.args)
+			if tt.expectErr != "" {
+				exitErr, ok := err.(cli.ExitCoder)
+				if !ok || exitErr.Error() != tt.expectErr || exitErr.ExitCode() != exitFailure {
+					t.Errorf("expected exit code %d with %q, got %v", exitFailure, tt.expectErr, err)
+				}
+			} else if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if calls := len(executor.(*MockExecutor).calls); calls != tt.expectCalls {
+				t.Errorf("expected %d executor calls, got %d", tt.expectCalls, calls)
+			}
+			for _, expected := range tt.expectOutput {
+				if !strings.Contains(out.String(), expected) {
+					t.Errorf("expected output containing %q, got %q", expected, out.String())
+				}
+			}
+		})
+	}
+}
+
+func TestConfirmation_NonInteractive(t *testing.T) {
+	tests := []struct {
+		name        string
+		args        []string
+		outputs     []string
+		expectCalls int
+	}{
+		{"delete", []string{"things", "delete", "--list", "Inbox", "--name", "Task"}, []string{`{"selected":["Task"],"total":1}`}, 1},
+		{"move", []string{"things", "move", "--from", "Inbox", "--to", "Work", "--name", "Task"}, []string{`{"selected":["Task"],"total":3}`}, 1},
+		{"complete all", []string{"things", "complete", "--list", "Today", "--all"}, []string{"SUCCESS: 2"}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cleanup := setupMockExecutorIntegrationMulti(tt.outputs, make([]error, len(tt.outputs)))
+			defer cleanup()
+
+			// A piped answer isn't taken as confirmation; only --yes is
+			stdin, w, err := os.Pipe()
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer stdin.Close()
+			io.WriteString(w, "y\n")
+			w.Close()
+
+			var out strings.Builder
+			app := createTestAppWithWriters(&out, io.Discard)
+			app.Reader = stdin
+			err = app.Run(context.Background(), tt.args)
+			exitErr, ok := err.(cli.ExitCoder)
+			if !ok || exitErr.ExitCode() != exitFailure || !strings.Contains(err.Error(), "pass --yes") {
+				t.Errorf("expected exit code %d with a hint to pass --yes, got %v", exitFailure, err)
+			}
+			if calls := len(executor.(*MockExecutor).calls); calls != tt.expectCalls {
+				t.Errorf("expected %d executor calls, got %d", tt.expectCalls, calls)
+			}
+			if strings.Contains(out.String(), "[y/N]") {
+				t.Errorf("expected no prompt, got %q", out.String())
+			}
+		})
+	}
+}
+
+func TestMoveConfirmation(t *testing.T) {
+	tests := []struct {
+		name         string
+		args         []string
+		input        string
+		outputs      []string
+		expectCalls  int
+		expectOutput []string
+		expectErr    string
+	}{
+		{
+			name:         "unique name is not confirmed",
+			args:         []string{"things", "move", "--from", "Inbox", "--to", "Work", "--name", "Task"},
+			outputs:      []string{`{"selected":["Task"],"total":1}`, "SUCCESS: 1"},
+			expectCalls:  2,
+			expectOutput: []string{"moved successfully"},
+		},
+		{
+			name:         "ambiguous name answered yes",
+			args:         []string{"things", "move", "--from", "Inbox", "--to", "Work", "--name", "Task"},
+			input:        "y\n",
+			outputs:      []string{`{"selected":["Task"],"total":3}`, "SUCCESS: 3"},
+			expectCalls:  2,
+			expectOutput: []string{`Move "Task"? 3 to-dos match; only the first will be moved [y/N]`, "moved successfully"},
+		},
+		{
+			name:        "ambiguous name answered no",
+			args:        []string{"things", "move", "--from", "Inbox", "--to", "Work", "--name", "Task"},
+			input:       "n\n",
+			outputs:     []string{`{"selected":["Task"],"total":3}`},
+			expectCalls: 1,
+			expectErr:   "Aborted; nothing was moved",
+		},
+		{
+			name:         "all skips the lookup",
+			args:         []string{"things", "move", "--from", "Inbox", "--to", "Work", "--name", "Task", "--all"},
+			outputs:      []string{"SUCCESS: 3"},
+			expectCalls:  1,
+			expectOutput: []string{"Moved 3 to-dos"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := make([]error, len(tt.outputs))
+			cleanup := setupMockExecutorIntegrationMulti(tt.outputs, errs)
+			defer cleanup()
+
+			var out strings.Builder
+			app := createTestAppWithWriters(&out, io.Discard)
+			app.Reader = strings.NewReader(tt.input)
+			err := app.Run(context.Background(), tt.args)
+			if tt.expectErr != "" {
+				exitErr, ok := err.(cli.ExitCoder)
+				if !ok || exitErr.Error() != tt.expectErr || exitErr.ExitCode() != exitFailure {
+					t.Errorf("expected exit code %d with %q, got %v", exitFailure, tt.expectErr, err)
+				}
+			} else if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if calls := len(executor.(*MockExecutor).calls); calls != tt.expectCalls {
+				t.Errorf("expected %d executor calls, got %d", tt.expectCalls, calls)
+			}
+			for _, expected := range tt.expectOutput {
+				if !strings.Contains(out.String(), expected) {
+					t.Errorf("expected output containing %q, got %q", expected, out.String())
+				}
+			}
+		})
+	}
+}
+
+func TestNameContainsFlag(t *testing.T) {
+	tests := []struct {
+		name         string
+		args         []string
+		input        string
+		outputs      []string
+		expectErr    string
+		expectOutput string
+		expectScript string
+	}{
+		{
+			name:         "delete unique match",
+			args:         []string{"things", "delete", "--list", "Inbox", "--name-contains", "milk"},
+			input:        "y\n",
+			outputs:      []string{`{"selected":["Buy milk"],"total":1}`, `{"selected":["Buy milk"],"total":1}`, `SUCCESS: 1 ["ABC123"]`},
+			expectOutput: `Delete "Buy milk"? [y/N]`,
+			expectScript: "todos[i].name().indexOf('milk') !== -1",
+		},
+		{
+			name:         "rename unique match",
+			args:         []string{"things", "rename", "--list", "Inbox", "--name-contains", "milk", "--new-name", "Buy oat milk"},
+			outputs:      []string{`{"selected":["Buy milk"],"total":1}`, `SUCCESS: 1 ["ABC123"]`},
+			expectOutput: "renamed",
+			expectScript: "todos[i].name().indexOf('milk') !== -1",
+		},
+		{
+			name:      "delete several matches without yes",
+			args:      []string{"things", "delete", "--list", "Inbox", "--name-contains", "milk"},
+			outputs:   []string{`{"selected":["Buy milk"],"total":2}`},
+			expectErr: `ERROR: 2 to-dos in list "Inbox" contain "milk"; pass --yes to go ahead anyway`,
+		},
+		{
+			name:      "rename several matches without yes",
+			args:      []string{"things", "rename", "--list", "Inbox", "--name-contains", "milk", "--new-name", "Milk"},
+			outputs:   []string{`{"selected":["Buy milk"],"total":2}`},
+			expectErr: `ERROR: 2 to-dos in list "Inbox" contain "milk"; pass --yes to go ahead anyway`,
+		},
+		{
+			name:         "rename several matches with yes",
+			args:         []string{"things", "rename", "--list", "Inbox", "--name-contains", "milk", "--new-name", "Milk", "--yes"},
+			outputs:      []string{`SUCCESS: 1 ["ABC123"]`},
+			expectOutput: "renamed",
+			expectScript: "todos[i].name().indexOf('milk') !== -1",
+		},
+		{
+			name:      "name and name-contains together",
+			args:      []string{"things", "rename", "--list", "Inbox", "--name", "Buy milk", "--name-contains", "milk", "--new-name", "Milk"},
+			expectErr: "ERROR: --name and --name-contains cannot be used together",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			outputs := tt.outputs
+			if outputs == nil {
+				outputs = []string{""}
+			}
+			cleanup := setupMockExecutorIntegrationMulti(outputs, make([]error, len(outputs)))
+			defer cleanup()
+
+			var out strings.Builder
+			app := createTestAppWithWriters(&out, io.Discard)
+			app.Reader = strings.NewReader(tt.input)
+			err := app.Run(context.Background(), tt.args)
+			if tt.expectErr != "" {
+				if err == nil || err.Error() != tt.expectErr {
+					t.Fatalf("expected error %q, got %v", tt.expectErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !strings.Contains(out.String(), tt.expectOutput) {
+				t.Errorf("expected output containing %q, got %q", tt.expectOutput, out.String())
+			}
+			if script := executor.(*MockExecutor).lastScript(); !strings.Contains(script, tt.expectScript) {
+				t.Errorf("expected script to contain %q", tt.expectScript)
+			}
+		})
+	}
+}
+
+func TestNameRegexFlag(t *testing.T) {
+	listOutput := `[{"id":"A1","name":"Buy milk"},{"id":"A2","name":"Buy eggs"},{"id":"A3","name":"Call mom"}]`
+
+	tests := []struct {
+		name         string
+		args         []string
+		outputs      []string
+		expectCalls  int
+		expectErr    string
+		expectOutput []string
+	}{
+		{
+			name:         "delete matching pattern",
+			args:         []string{"things", "delete", "--list", "Inbox", "--name-regex", "^Buy", "--all", "--yes"},
+			outputs:      []string{listOutput, "SUCCESS: Buy milk", "SUCCESS: Buy eggs"},
+			expectCalls:  3,
+			expectOutput: []string{`To-do "Buy milk" deleted`, `To-do "Buy eggs" deleted`},
+		},
+		{
+			name:         "move matching pattern",
+			args:         []string{"things", "move", "--from", "Inbox", "--to", "Work", "--name-regex", "(?i)MOM$"},
+			outputs:      []string{listOutput, "SUCCESS: Call mom"},
+			expectCalls:  2,
+			expectOutput: []string{`To-do "Call mom" moved to list "Work"!`},
+		},
+		{
+			name:         "rename matching pattern ignoring case",
+			args:         []string{"things", "rename", "--list", "Inbox", "--name-regex", "EGGS", "--ignore-case", "--new-name", "Buy a dozen eggs"},
+			outputs:      []string{listOutput, "SUCCESS: Buy eggs"},
+			expectCalls:  2,
+			expectOutput: []string{`To-do "Buy eggs" renamed to "Buy a dozen eggs"!`},
+		},
+		{
+			name:         "search matching pattern",
+			args:         []string{"things", "search", "--name-regex", "^Buy (milk|bread)$"},
+			outputs:      []string{`["Inbox"]`, listOutput},
+			expectCalls:  2,
+			expectOutput: []string{"Buy milk"},
+		},
+		{
+			name:        "non-matching pattern",
+			args:        []string{"things", "delete", "--list", "Inbox", "--name-regex", "^bread$", "--yes"},
+			outputs:     []string{listOutput},
+			expectCalls: 1,
+			expectErr:   `ERROR: No to-do matching /^bread$/ found in list "Inbox"`,
+		},
+		{
+			name:      "invalid pattern",
+			args:      []string{"things", "rename", "--list", "Inbox", "--name-regex", "Buy (milk", "--new-name", "Milk"},
+			expectErr: "ERROR: invalid --name-regex: error parsing regexp: missing closing ): `Buy (milk`",
+		},
+		{
+			name:      "invalid pattern in search",
+			args:      []string{"things", "search", "--name-regex", "[a-"},
+			expectErr: "ERROR: invalid --name-regex: error parsing regexp: missing closing ]: `[a-`",
+		},
+		{
+			name:      "name and name-regex together",
+			args:      []string{"things", "delete", "--list", "Inbox", "--name", "Buy milk", "--name-regex", "milk"},
+			expectErr: "ERROR: --name-regex cannot be used with --name or --name-contains",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			outputs := tt.outputs
+			if outputs == nil {
+				outputs = []string{""}
+			}
+			cleanup := setupMockExecutorIntegrationMulti(outputs, make([]error, len(outputs)))
+			defer cleanup()
+
+			var out strings.Builder
+			app := createTestAppWithWriters(&out, io.Discard)
+			err := app.Run(context.Background(), tt.args)
+			if calls := len(executor.(*MockExecutor).calls); calls != tt.expectCalls {
+				t.Errorf("expected %d executor calls, got %d", tt.expectCalls, calls)
+			}
+			if tt.expectErr != "" {
+				if err == nil || err.Error() != tt.expectErr {
+					t.Fatalf("expected error %q, got %v", tt.expectErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			for _, expected := range tt.expectOutput {
+				if !strings.Contains(out.String(), expected) {
+					t.Errorf("expected output containing %q, got %q", expected, out.String())
+				}
+			}
+		})
+	}
+}
+
+func TestFormatFlag(t *testing.T) {
+	mockOutput := `[{"name":"Ship release","status":"open","area":"Work"},{"name":"Call mom","status":"open"}]`
+
+	t.Run("template output", func(t *testing.T) {
+		cleanup := setupMockExecutorIntegration(mockOutput, nil)
+		defer cleanup()
+
+		var out strings.Builder
+		app := createTestAppWithWriters(&out, io.Discard)
+		if err := app.Run(context.Background(), []string{"things", "show", "--list", "Today", "--format", "{{.Name}} ({{.Area}})"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if expected := "Ship release (Work)\nCall mom ()\n"; out.String() != expected {
+			t.Errorf("expected %q, got %q", expected, out.String())
+		}
+	})
+
+	for name, args := range map[string][]string{
+		"show parse error": {"things", "show", "--list", "Today", "--format", "{{.Name"},
+		"log parse error":  {"things", "log", "--date", "today", "--format", "{{.Name"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			cleanup := setupMockExecutorIntegration(mockOutput, nil)
+			defer cleanup()
+
+			app := createTestApp()
+			err := app.Run(context.Background(), args)
+			if err == nil || !strings.HasPrefix(err.Error(), "ERROR: invalid --format template") {
+				t.Fatalf("expected template error, got %v", err)
+			}
+			if calls := len(executor.(*MockExecutor).calls); calls != 0 {
+				t.Errorf("expected no executor calls, got %d", calls)
+			}
+		})
+	}
+}
+
+func TestHeaderFlags(t *testing.T) {
+	mockOutput := `[{"name":"Buy milk","status":"open"}]`
+
+	tests := []struct {
+		name      string
+		args      []string
+		expected  string
+		expectErr bool
+	}{
+		{"csv header by default", []string{"things", "show", "--list", "Inbox", "--csv"}, "name,status,notes,due,tags,area,project\nBuy milk,open,,,,,\n", false},
+		{"csv without header", []string{"things", "show", "--list", "Inbox", "--csv", "--no-header"}, "Buy milk,open,,,,,\n", false},
+		{"table header by default", []string{"things", "show", "--list", "Inbox", "--table"}, "STATUS  NAME\nopen    Buy milk\n", false},
+		{"table without header", []string{"things", "show", "--list", "Inbox", "--table", "--no-header"}, "open  Buy milk\n", false},
+		{"count header on request", []string{"things", "show", "--list", "Inbox", "--header", "--color", "never", "--width", "0"}, "Inbox (1 to-do):\n○ Buy milk\n", false},
+		{"today title left out", []string{"things", "today", "--no-header", "--color", "never", "--width", "0"}, "○ Buy milk\n", false},
+		{"both flags", []string{"things", "show", "--list", "Inbox", "--header", "--no-header"}, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cleanup := setupMockExecutorIntegration(mockOutput, nil)
+			defer cleanup()
+
+			var out strings.Builder
+			app := createTestAppWithWriters(&out, io.Discard)
+			err := app.Run(context.Background(), tt.args)
+			if tt.expectErr {
+				if err == nil {
+					t.Error("expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if out.String() != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, out.String())
+			}
+		})
+	}
+}
+
+func TestShowCommand_Age(t *testing.T) {
+	setNow(t, time.Date(2024, 1, 31, 12, 0, 0, 0, time.UTC))
+	mockOutput := `[{"name":"Languishing","status":"open","creationDate":"2023-12-01T08:00:00Z"},{"name":"This week","status":"open","creationDate":"2024-01-29T08:00:00Z"},{"name":"Today","status":"open","creationDate":"2024-01-31T09:00:00Z"},{"name":"No creation date","status":"open"}]`
+
+	tests := []struct {
+		name      string
+		args      []string
+		expected  []string
+		expectErr string
+	}{
+		{"min age in days", []string{"--min-age", "30d"}, []string{"Languishing"}, ""},
+		{"max age in days", []string{"--max-age", "7d"}, []string{"This week", "Today"}, ""},
+		{"min age in hours", []string{"--min-age", "24h"}, []string{"Languishing", "This week"}, ""},
+		{"both", []string{"--min-age", "1d", "--max-age", "7d"}, []string{"This week"}, ""},
+		{"invalid", []string{"--min-age", "a month"}, nil, "ERROR: --min-age must be a number of days such as 30d or a duration such as 12h"},
+		{"min above max", []string{"--min-age", "30d", "--max-age", "7d"}, nil, "ERROR: --min-age can't be greater than --max-age"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cleanup := setupMockExecutorIntegration(mockOutput, nil)
+			defer cleanup()
+
+			var out strings.Builder
+			app := createTestAppWithWriters(&out, io.Discard)
+			err := app.Run(context.Background(), append([]string{"things", "show", "--list", "Inbox", "--jsonl"}, tt.args...))
+			if tt.expectErr != "" {
+				if err == nil || err.Error() != tt.expectErr {
+					t.Errorf("expected error %q, got %v", tt.expectErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			var names []string
+			for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+				var todo Todo
+				if err := json.Unmarshal([]byte(line), &todo); err != nil {
+					t.Fatalf("invalid JSONL line %q: %v", line, err)
+				}
+				names = append(names, todo.Name)
+			}
+			if !slices.Equal(names, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, names)
+			}
+		})
+	}
+}
+
+func TestShowCommand_ModifiedAfter(t *testing.T) {
+	mockOutput := `[{"name":"Stale","status":"open","modificationDate":"2024-01-10T08:00:00Z"},{"name":"Fresh","status":"open","modificationDate":"2024-01-20T08:00:00Z"},{"name":"Never modified","status":"open"}]`
+
+	tests := []struct {
+		name      string
+		value     string
+		expected  []string
+		expectErr bool
+	}{
+		{"date", "2024-01-15", []string{"Fresh"}, false},
+		{"timestamp", "2024-01-10T07:00:00Z", []string{"Stale", "Fresh"}, false},
+		{"invalid", "soon", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cleanup := setupMockExecutorIntegration(mockOutput, nil)
+			defer cleanup()
+
+			var out strings.Builder
+			app := createTestAppWithWriters(&out, io.Discard)
+			err := app.Run(context.Background(), []string{"things", "show", "--list", "Inbox", "--modified-after", tt.value, "--timezone", "UTC", "--jsonl"})
+			if tt.expectErr {
+				if err == nil {
+					t.Error("expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			var names []string
+			for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+				var todo Todo
+				if err := json.Unmarshal([]byte(line), &todo); err != nil {
+					t.Fatalf("invalid JSONL line %q: %v", line, err)
+				}
+				names = append(names, todo.Name)
+			}
+			if !slices.Equal(names, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, names)
+			}
+		})
+	}
+}
+
+func TestShowCommand_ModifiedInLongView(t *testing.T) {
+	mockOutput := `[{"name":"Fresh","status":"open","modificationDate":"2024-01-20T08:00:00Z"}]`
+
+	tests := []struct {
+		name         string
+		args         []string
+		showModified bool
+	}{
+		{"long alone", []string{"things", "show", "--list", "Inbox", "--long"}, false},
+		{"with --modified-after", []string{"things", "show", "--list", "Inbox", "--long", "--modified-after", "2024-01-15"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cleanup := setupMockExecutorIntegration(mockOutput, nil)
+			defer cleanup()
+
+			var out strings.Builder
+			app := createTestAppWithWriters(&out, io.Discard)
+			if err := app.Run(context.Background(), tt.args); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if shown := strings.Contains(out.String(), "  modified "); shown != tt.showModified {
+				t.Errorf("expected modification time shown: %v, got %q", tt.showModified, out.String())
+			}
+		})
+	}
+}
+
+func TestMoveCommand_AuthToken(t *testing.T) {
+	tests := []struct {
+		name   string
+		env    string
+		config string
+		expect string
+	}{
+		{name: "from the environment", env: "env-token", config: `{"auth-token": "config-token"}`, expect: "encodeURIComponent('env-token')"},
+		{name: "from the config file", config: `{"auth-token": "config-token"}`, expect: "encodeURIComponent('config-token')"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("THINGS_AUTH_TOKEN", tt.env)
+			useConfig(t, tt.config)
+			defer func() { authToken = "" }()
+			cleanup := setupMockExecutorIntegration(`SUCCESS: 1 ["T1"]`, nil)
+			defer cleanup()
+
+			app := createTestAppWithWriters(io.Discard, io.Discard)
+			if err := app.Run(context.Background(), []string{"things", "move", "--from", "Inbox", "--to", "Someday", "--name", "Task", "--yes"}); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if script := executor.(*MockExecutor).lastScript(); !strings.Contains(script, tt.expect) {
+				t.Errorf("expected %q in the script, got:\n%s", tt.expect, script)
+			}
+		})
+	}
+}
+
+func TestMoveCommand_Tag(t *testing.T) {
+	tests := []struct {
+		name      string
+		args      []string
+		expectErr string
+	}{
+		{"moves tagged to-dos", []string{"things", "move", "--from", "Inbox", "--to", "Later", "--tag", "review"}, ""},
+		{"with a name", []string{"things", "move", "--from", "Inbox", "--to", "Later", "--tag", "review", "--name", "Task"}, "ERROR: --tag cannot be used with --name or --name-regex"},
+		{"to a project", []string{"things", "move", "--from", "Inbox", "--to-project", "Launch", "--tag", "review"}, "ERROR: --tag can only move to-dos to a list given with --to"},
+		{"no selection", []string{"things", "move", "--from", "Inbox", "--to", "Later"}, "ERROR: one of --name, --name-regex, or --tag is required"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cleanup := setupMockExecutorIntegration(`SUCCESS: 2 ["A1","A3"]`, nil)
+			defer cleanup()
+
+			var out strings.Builder
+			app := createTestAppWithWriters(&out, io.Discard)
+			err := app.Run(context.Background(), tt.args)
+			if tt.expectErr != "" {
+				if err == nil || err.Error() != tt.expectErr {
+					t.Fatalf("expected error %q, got %v", tt.expectErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if expected := "Moved 2 to-dos tagged \"review\" from list \"Inbox\" to list \"Later\"!\n"; out.String() != expected {
+				t.Errorf("expected %q, got %q", expected, out.String())
+			}
+		})
+	}
+}
+
+func TestMoveCommand_TagNotFound(t *testing.T) {
+	tests := []struct {
+		name       string
+		output     string
+		expectErr  string
+		expectCode int
+	}{
+		{"source list", "ERROR: List not found: Can't get object.", `ERROR: List "Inbox" not found`, exitNotFound},
+		{"destination list", "ERROR: Destination not found", `ERROR: List "Latr" not found`, exitFailure},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cleanup := setupMockExecutorIntegration(tt.output, nil)
+			defer cleanup()
+
+			app := createTestAppWithWriters(io.Discard, io.Discard)
+			err := app.Run(context.Background(), []string{"things", "move", "--from", "Inbox", "--to", "Latr", "--tag", "review"})
+			exitErr, ok := err.(cli.ExitCoder)
+			if !ok || err.Error() != tt.expectErr || exitErr.ExitCode() != tt.expectCode {
+				t.Errorf("expected exit code %d with %q, got %v", tt.expectCode, tt.expectErr, err)
+			}
+		})
+	}
+}
+
+func TestCompleteCommand_Bulk(t *testing.T) {
+	tests := []struct {
+		name         string
+		args         []string
+		expectErr    string
+		expectScript string
+	}{
+		{"by tag", []string{"things", "complete", "--list", "Today", "--tag", "errand"}, "", "t.name() === 'errand'"},
+		{"whole list", []string{"things", "complete", "--list", "Today", "--all", "--yes"}, "", "todos[i].status() === 'open' && true"},
+		{"tag with name", []string{"things", "complete", "--list", "Today", "--tag", "errand", "--name", "Task"}, "ERROR: --tag cannot be used with --name", ""},
+		{"missing list", []string{"things", "complete", "--tag", "errand"}, "ERROR: --list is required to complete to-dos in bulk", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cleanup := setupMockExecutorIntegration(`SUCCESS: 2 ["A1","A2"]`, nil)
+			defer cleanup()
+
+			var out strings.Builder
+			app := createTestAppWithWriters(&out, io.Discard)
+			err := app.Run(context.Background(), tt.args)
+			if tt.expectErr != "" {
+				if err == nil || err.Error() != tt.expectErr {
+					t.Fatalf("expected error %q, got %v", tt.expectErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if expected := "Completed 2 to-dos in list \"Today\"!\n"; out.String() != expected {
+				t.Errorf("expected %q, got %q", expected, out.String())
+			}
+			if script := executor.(*MockExecutor).lastScript(); !strings.Contains(script, tt.expectScript) {
+				t.Errorf("expected script to contain %q", tt.expectScript)
+			}
+		})
+	}
+}
+
+func TestCompleteCommand_BulkConfirmation(t *testing.T) {
+	tests := []struct {
+		name         string
+		args         []string
+		input        string
+		expectCalls  int
+		expectOutput []string
+		expectErr    string
+	}{
+		{
+			name:         "answer yes",
+			args:         []string{"things", "complete", "--list", "Today", "--all"},
+			input:        "y\n",
+			expectCalls:  1,
+			expectOutput: []string{`Complete every open to-do in list "Today"? [y/N]`, `Completed 2 to-dos in list "Today"!`},
+		},
+		{
+			name:         "answer no",
+			args:         []string{"things", "complete", "--list", "Today", "--all"},
+			input:        "n\n",
+			expectCalls:  0,
+			expectOutput: []string{`Complete every open to-do in list "Today"? [y/N]`},
+			expectErr:    "Aborted; nothing was completed",
+		},
+		{
+			name:         "by tag doesn't ask",
+			args:         []string{"things", "complete", "--list", "Today", "--tag", "errand"},
+			expectCalls:  1,
+			expectOutput: []string{`Completed 2 to-dos in list "Today"!`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cleanup := setupMockExecutorIntegration(`SUCCESS: 2 ["A1","A2"]`, nil)
+			defer cleanup()
+
+			var out strings.Builder
+			app := createTestAppWithWriters(&out, io.Discard)
+			app.Reader = strings.NewReader(tt.input)
+			err := app.Run(context.Background(), tt.args)
+			if tt.expectErr != "" {
+				exitErr, ok := err.(cli.ExitCoder)
+				if !ok || exitErr.Error() != tt.expectErr || exitErr.ExitCode() != exitFailure {
+					t.Errorf("expected exit code %d with %q, got %v", exitFailure, tt.expectErr, err)
+				}
+			} else if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if calls := len(executor.(*MockExecutor).calls); calls != tt.expectCalls {
+				t.Errorf("expected %d executor calls, got %d", tt.expectCalls, calls)
+			}
+			for _, expected := range tt.expectOutput {
+				if !strings.Contains(out.String(), expected) {
+					t.Errorf("expected output containing %q, got %q", expected, out.String())
+				}
+			}
+		})
+	}
+}
+
+func TestVerboseFlag(t *testing.T) {
+	tests := []struct {
+		name      string
+		args      []string
+		expectLog bool
+	}{
+		{"long flag", []string{"things", "--verbose", "show", "--list", "Today"}, true},
+		{"short flag", []string{"things", "-v", "show", "--list", "Today"}, true},
+		{"not set", []string{"things", "show", "--list", "Today"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cleanup := setupMockExecutorIntegration(`[]`, nil)
+			defer cleanup()
+			mock := executor
+
+			var errOut strings.Builder
+			app := createTestAppWithWriters(io.Discard, &errOut)
+			if err := app.Run(context.Background(), tt.args); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			logged := strings.Contains(errOut.String(), "osascript -l JavaScript -e") &&
+				strings.Contains(errOut.String(), "app.lists.byName('Today')")
+			if logged != tt.expectLog {
+				t.Errorf("expected script logged: %v, got stderr %q", tt.expectLog, errOut.String())
+			}
+			if executor != mock {
+				t.Error("expected the executor to be restored after the command")
+			}
+		})
+	}
+}
+
+func TestShowCommand_ListSuggestion(t *testing.T) {
+	tests := []struct {
+		name          string
+		lists         string
+		expectMessage string
+	}{
+		{"near miss", `["Inbox","Today","Upcoming"]`, "ERROR: List \"Todya\" not found\nDid you mean \"Today\"?"},
+		{"nothing close", `["Inbox","Work"]`, "ERROR: List \"Todya\" not found\nUse `things list` to see available lists."},
+		{"lists unavailable", "ERROR: Can't get object.", "ERROR: List \"Todya\" not found\nUse `things list` to see available lists."},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cleanup := setupMockExecutorIntegrationMulti([]string{"ERROR: Can't get object.", tt.lists}, []error{nil, nil})
+			defer cleanup()
+
+			app := createTestApp()
+			err := app.Run(context.Background(), []string{"things", "show", "--list", "Todya"})
+			if err == nil || err.Error() != tt.expectMessage {
+				t.Errorf("expected error %q, got %v", tt.expectMessage, err)
+			}
+		})
+	}
+}
+
+func TestAddCommand_TagFlags(t *testing.T) {
+	tests := []struct {
+		name         string
+		args         []string
+		expectScript []string
+		rejectScript []string
+	}{
+		{
+			name:         "comma-separated tags are split",
+			args:         []string{"things", "add", "--name", "Task", "--tags", "Home, Office"},
+			expectScript: []string{"tagNames: 'Home, Office'"},
+			rejectScript: []string{"todo.tags.push"},
+		},
+		{
+			name:         "repeated tag keeps its comma",
+			args:         []string{"things", "add", "--name", "Task", "--tag", "Home, Office"},
+			expectScript: []string{"todo.tags.push(app.tags.byName('Home, Office'));"},
+			rejectScript: []string{"tagNames"},
+		},
+		{
+			name:         "both flags combine",
+			args:         []string{"things", "add", "--name", "Task", "--tags", "Errands", "--tag", "Home, Office", "--tag", "Mom's"},
+			expectScript: []string{"tagNames: 'Errands, Mom\\'s'", "todo.tags.push(app.tags.byName('Home, Office'));"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cleanup := setupMockExecutorIntegration("SUCCESS", nil)
+			defer cleanup()
+
+			app := createTestApp()
+			if err := app.Run(context.Background(), tt.args); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			script := executor.(*MockExecutor).lastScript()
+			for _, expected := range tt.expectScript {
+				if !strings.Contains(script, expected) {
+					t.Errorf("expected script to contain %q:\n%s", expected, script)
+				}
+			}
+			for _, rejected := range tt.rejectScript {
+				if strings.Contains(script, rejected) {
+					t.Errorf("expected script not to contain %q:\n%s", rejected, script)
+				}
+			}
+		})
+	}
+}
+
+func TestRetagCommand(t *testing.T) {
+	tests := []struct {
+		name      string
+		args      []string
+		expectErr bool
+	}{
+		{"add", []string{"things", "retag", "--list", "Inbox", "--name", "Task", "--tags", "Home, Work"}, false},
+		{"replace with repeated tags", []string{"things", "retag", "--list", "Inbox", "--name", "Task", "--replace", "--tag", "Home, Office"}, false},
+		{"replace with nothing", []string{"things", "retag", "--list", "Inbox", "--name", "Task", "--replace"}, false},
+		{"add with nothing", []string{"things", "retag", "--list", "Inbox", "--name", "Task"}, true},
+		{"both modes", []string{"things", "retag", "--list", "Inbox", "--name", "Task", "--tags", "Home", "--add", "--replace"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cleanup := setupMockExecutorIntegrationMulti([]string{`[]`, "SUCCESS"}, []error{nil, nil})
+			defer cleanup()
+
+			app := createTestApp()
+			err := app.Run(context.Background(), tt.args)
+			if (err != nil) != tt.expectErr {
+				t.Errorf("expected error: %v, got %v", tt.expectErr, err)
+			}
+		})
+	}
+}
+
+func TestShowCommand_AreaProjectFilter(t *testing.T) {
+	tests := []struct {
+		name        string
+		args        []string
+		expectNames []string
+	}{
+		{"area", []string{"--area", "Work"}, []string{"Task 1", "Task 3"}},
+		{"project", []string{"-p", "Project A"}, []string{"Task 1"}},
+		{"area and project", []string{"--area", "Work", "--project", "Project B"}, []string{"Task 3"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cleanup := setupMockExecutorIntegration(areaProjectFixture, nil)
+			defer cleanup()
+
+			var out strings.Builder
+			app := createTestAppWithWriters(&out, io.Discard)
+			args := append([]string{"things", "show", "--list", "Anytime", "--jsonl"}, tt.args...)
+			if err := app.Run(context.Background(), args); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+			if len(lines) != len(tt.expectNames) {
+				t.Fatalf("expected %d to-dos, got %q", len(tt.expectNames), out.String())
+			}
+			for i, name := range tt.expectNames {
+				if !strings.Contains(lines[i], `"name":"`+name+`"`) {
+					t.Errorf("expected line %d to be %q, got %q", i, name, lines[i])
+				}
+			}
+		})
+	}
+}
+
+func TestOutputFlag(t *testing.T) {
+	mockOutput := `[{"name":"Task 1","status":"open"},{"name":"Task 2","status":"open","tagNames":["Home"]}]`
+
+	// The same command written to stdout, for comparison
+	cleanup := setupMockExecutorIntegration(mockOutput, nil)
+	var stdout strings.Builder
+	app := createTestAppWithWriters(&stdout, io.Discard)
+	if err := app.Run(context.Background(), []string{"things", "show", "--list", "Today", "--jsonl"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	cleanup()
+
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{"before the subcommand", []string{"things", "--output", "%s", "show", "--list", "Today", "--jsonl"}},
+		{"after the subcommand", []string{"things", "show", "--list", "Today", "--jsonl", "-o", "%s"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cleanup := setupMockExecutorIntegration(mockOutput, nil)
+			defer cleanup()
+
+			path := filepath.Join(t.TempDir(), "todos.jsonl")
+			if err := os.WriteFile(path, []byte("stale contents that should be truncated\n"), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			args := make([]string, len(tt.args))
+			for i, arg := range tt.args {
+				args[i] = strings.ReplaceAll(arg, "%s", path)
+			}
+
+			var out strings.Builder
+			app := createTestAppWithWriters(&out, io.Discard)
+			if err := app.Run(context.Background(), args); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			contents, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(contents) != stdout.String() {
+				t.Errorf("expected file to contain %q, got %q", stdout.String(), contents)
+			}
+			if out.Len() != 0 {
+				t.Errorf("expected nothing on stdout, got %q", out.String())
+			}
+		})
+	}
+
+	t.Run("unwritable path", func(t *testing.T) {
+		cleanup := setupMockExecutorIntegration(mockOutput, nil)
+		defer cleanup()
+
+		path := filepath.Join(t.TempDir(), "missing", "todos.jsonl")
+		app := createTestApp()
+		err := app.Run(context.Background(), []string{"things", "show", "--list", "Today", "--output", path})
+		if err == nil || !strings.HasPrefix(err.Error(), "ERROR: can't write output:") {
+			t.Errorf("expected output error, got %v", err)
+		}
+	})
+}
+
+func TestJSONFlags(t *testing.T) {
+	mockOutput := `[{"id":"1","name":"Task 1","status":"open"}]`
+
+	tests := []struct {
+		name         string
+		args         []string
+		expectOutput string
+	}{
+		{
+			name:         "json",
+			args:         []string{"things", "show", "--list", "Today", "--json"},
+			expectOutput: `[{"id":"1","name":"Task 1","status":"open"}]` + "\n",
+		},
+		{
+			name:         "pretty implies json",
+			args:         []string{"things", "show", "--list", "Today", "--pretty"},
+			expectOutput: "[\n  {\n    \"id\": \"1\",\n    \"name\": \"Task 1\",\n    \"status\": \"open\"\n  }\n]\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cleanup := setupMockExecutorIntegration(mockOutput, nil)
+			defer cleanup()
+
+			var out strings.Builder
+			app := createTestAppWithWriters(&out, io.Discard)
+			if err := app.Run(context.Background(), tt.args); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if out.String() != tt.expectOutput {
+				t.Errorf("expected %q, got %q", tt.expectOutput, out.String())
+			}
+		})
+	}
+}
+
+func TestShowNotesFlag(t *testing.T) {
+	mockOutput := `[{"id":"1","name":"Task 1","status":"open","notes":"First step, then the second step"}]`
+
+	tests := []struct {
+		name         string
+		args         []string
+		expectErr    bool
+		expectOutput string
+	}{
+		{
+			name:         "notes hidden by default",
+			args:         []string{"things", "show", "--list", "Today", "--long"},
+			expectOutput: "○ Task 1\n",
+		},
+		{
+			name:         "show notes implies long",
+			args:         []string{"things", "show", "--list", "Today", "--show-notes"},
+			expectOutput: "○ Task 1\n  First step, then the second step\n",
+		},
+		{
+			name:         "notes width",
+			args:         []string{"things", "show", "--list", "Today", "--show-notes", "--notes-width", "20"},
+			expectOutput: "○ Task 1\n  First step, then\n  the second step\n",
+		},
+		{
+			name:      "invalid notes width",
+			args:      []string{"things", "show", "--list", "Today", "--show-notes", "--notes-width", "0"},
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cleanup := setupMockExecutorIntegration(mockOutput, nil)
+			defer cleanup()
+
+			var out strings.Builder
+			app := createTestAppWithWriters(&out, io.Discard)
+			err := app.Run(context.Background(), append(tt.args, "--color", "never"))
+			if tt.expectErr {
+				if _, ok := err.(cli.ExitCoder); !ok {
+					t.Errorf("expected cli.ExitCoder, got %T (%v)", err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if out.String() != tt.expectOutput {
+				t.Errorf("expected %q, got %q", tt.expectOutput, out.String())
+			}
+		})
+	}
+}
+
+func TestASCIIFlag(t *testing.T) {
+	mockOutput := `[{"name":"Task 1","status":"open"},{"name":"Task 2","status":"completed"}]`
+	cleanup := setupMockExecutorIntegration(mockOutput, nil)
+	defer cleanup()
+
+	var out strings.Builder
+	app := createTestAppWithWriters(&out, io.Discard)
+	if err := app.Run(context.Background(), []string{"things", "show", "--list", "Today", "--ascii", "--long"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expected := "[ ] Task 1\n[x] Task 2\n"; out.String() != expected {
+		t.Errorf("expected %q, got %q", expected, out.String())
+	}
+}
+
+func TestQuietFlag(t *testing.T) {
+	tests := []struct {
+		name         string
+		args         []string
+		output       string
+		expectErr    bool
+		expectStdout bool
+	}{
+		{"add", []string{"things", "--quiet", "add", "--list", "Inbox", "--name", "Task"}, "SUCCESS", false, false},
+		{"delete", []string{"things", "--quiet", "delete", "--yes", "--list", "Inbox", "--name", "Task"}, "SUCCESS: 1", false, false},
+		{"move", []string{"things", "--quiet", "move", "--yes", "--from", "Inbox", "--to", "Today", "--name", "Task"}, "SUCCESS: 1", false, false},
+		{"rename", []string{"things", "--quiet", "rename", "--list", "Inbox", "--name", "Task", "--new-name", "New"}, "SUCCESS: 1", false, false},
+		{"after the command", []string{"things", "add", "--list", "Inbox", "--name", "Task", "--quiet"}, "SUCCESS", false, false},
+		{"failure still errors", []string{"things", "--quiet", "delete", "--yes", "--list", "Inbox", "--name", "Task"}, "ERROR: To-do not found in list", true, false},
+		{"dry run still prints", []string{"things", "--quiet", "--dry-run", "add", "--list", "Inbox", "--name", "Task"}, "SUCCESS", false, true},
+		{"without quiet", []string{"things", "add", "--list", "Inbox", "--name", "Task"}, "SUCCESS", false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cleanup := setupMockExecutorIntegration(tt.output, nil)
+			defer cleanup()
+			defer func() { dryRun = false }()
+
+			var out strings.Builder
+			app := createTestAppWithWriters(&out, io.Discard)
+			err := app.Run(context.Background(), tt.args)
+			if tt.expectErr {
+				exitErr, ok := err.(cli.ExitCoder)
+				if !ok || exitErr.ExitCode() != exitNotFound {
+					t.Errorf("expected exit code %d, got %v", exitNotFound, err)
+				}
+			} else if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.expectStdout == (out.String() == "") {
+				t.Errorf("expected stdout %v, got %q", tt.expectStdout, out.String())
+			}
+		})
+	}
+}
+
+func TestQuietFlag_SearchQueryAlias(t *testing.T) {
+	cleanup := setupMockExecutorIntegrationMulti([]string{`["Inbox"]`, "[]"}, []error{nil, nil})
+	defer cleanup()
+
+	app := createTestApp()
+	if err := app.Run(context.Background(), []string{"things", "search", "-q", "report"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// -q is search's --query; --quiet has no short form so the two can't be confused
+	if script := executor.(*MockExecutor).lastScript(); !strings.Contains(script, "'report'.toLowerCase()") {
+		t.Errorf("expected -q to set the query, got:\n%s", script)
+	}
+
+	var help strings.Builder
+	app = createTestAppWithWriters(&help, io.Discard)
+	if err := app.Run(context.Background(), []string{"things", "search", "--help"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if count := strings.Count(help.String(), " -q "); count != 1 {
+		t.Errorf("expected one -q flag in search's help, got %d:\n%s", count, help.String())
+	}
+}
+
+func TestJSONResultFlag(t *testing.T) {
+	tests := []struct {
+		name          string
+		args          []string
+		output        string
+		outputErr     error
+		expectSuccess bool
+		expectMessage string
+		expectCode    int
+	}{
+		{
+			name:          "add",
+			args:          []string{"things", "add", "--list", "Inbox", "--name", "Task", "--json"},
+			output:        "SUCCESS",
+			expectSuccess: true,
+			expectMessage: "To-do added successfully to list \"Inbox\"!",
+		},
+		{
+			name:          "rename",
+			args:          []string{"things", "rename", "--list", "Inbox", "--name", "Task", "--new-name", "New", "--json"},
+			output:        "SUCCESS: 1",
+			expectSuccess: true,
+		},
+		{
+			name:          "delete not found",
+			args:          []string{"things", "delete", "--yes", "--list", "Inbox", "--name", "Task", "--json"},
+			output:        "ERROR: To-do not found in list",
+			expectMessage: "ERROR: To-do \"Task\" not found in list \"Inbox\"",
+			expectCode:    exitNotFound,
+		},
+		{
+			name:       "move with a script error",
+			args:       []string{"things", "move", "--yes", "--from", "Inbox", "--to", "Today", "--name", "Task", "--json"},
+			outputErr:  errors.New("exit status 1"),
+			expectCode: exitFailure,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cleanup := setupMockExecutorIntegration(tt.output, tt.outputErr)
+			defer cleanup()
+
+			var out strings.Builder
+			app := createTestAppWithWriters(&out, io.Discard)
+			err := app.Run(context.Background(), tt.args)
+			if tt.expectSuccess && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !tt.expectSuccess {
+				exitErr, ok := err.(cli.ExitCoder)
+				if !ok || exitErr.ExitCode() != tt.expectCode {
+					t.Errorf("expected exit code %d, got %v", tt.expectCode, err)
+				}
+			}
+
+			var result struct {
+				Success *bool   `json:"success"`
+				Message *string `json:"message"`
+			}
+			if err := json.Unmarshal([]byte(out.String()), &result); err != nil {
+				t.Fatalf("expected a JSON object, got %q: %v", out.String(), err)
+			}
+			if result.Success == nil || *result.Success != tt.expectSuccess {
+				t.Errorf("expected success %v, got %q", tt.expectSuccess, out.String())
+			}
+			if result.Message == nil || (tt.expectMessage != "" && *result.Message != tt.expectMessage) {
+				t.Errorf("expected message %q, got %q", tt.expectMessage, out.String())
+			}
+		})
+	}
+}
+
+func TestWidthFlag(t *testing.T) {
+	mockOutput := `[{"id":"1","name":"Write the quarterly report for the team","status":"open"}]`
+
+	tests := []struct {
+		name         string
+		args         []string
+		expectErr    bool
+		expectOutput string
+	}{
+		{
+			name:         "not a terminal doesn't wrap",
+			args:         []string{"things", "show", "--list", "Today"},
+			expectOutput: "○ Write the quarterly report for the team\n",
+		},
+		{
+			name:         "explicit width",
+			args:         []string{"things", "show", "--list", "Today", "--width", "21"},
+			expectOutput: "○ Write the quarterly\n  report for the team\n",
+		},
+		{
+			name:         "zero turns wrapping off",
+			args:         []string{"things", "show", "--list", "Today", "--width", "0"},
+			expectOutput: "○ Write the quarterly report for the team\n",
+		},
+		{
+			name:      "invalid width",
+			args:      []string{"things", "show", "--list", "Today", "--width", "wide"},
+			expectErr: true,
+		},
+		{
+			name:      "negative width",
+			args:      []string{"things", "show", "--list", "Today", "--width", "-5"},
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cleanup := setupMockExecutorIntegration(mockOutput, nil)
+			defer cleanup()
+
+			var out strings.Builder
+			app := createTestAppWithWriters(&out, io.Discard)
+			err := app.Run(context.Background(), tt.args)
+			if tt.expectErr {
+				if _, ok := err.(cli.ExitCoder); !ok {
+					t.Errorf("expected cli.ExitCoder, got %T (%v)", err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if out.String() != tt.expectOutput {
+				t.Errorf("expected %q, got %q", tt.expectOutput, out.String())
+			}
+		})
+	}
+}
+
+func TestFieldsFlag(t *testing.T) {
+	mockOutput := `[{"id":"1","name":"Task 1","status":"open","tagNames":["Home"]},{"id":"2","name":"Task 2","status":"open"}]`
+
+	tests := []struct {
+		name         string
+		args         []string
+		expectErr    bool
+		expectOutput string
+	}{
+		{
+			name:         "two fields",
+			args:         []string{"things", "show", "--list", "Today", "--jsonl", "--fields", "name,tags"},
+			expectOutput: "{\"name\":\"Task 1\",\"tagNames\":[\"Home\"]}\n{\"name\":\"Task 2\"}\n",
+		},
+		{
+			name:      "unknown field",
+			args:      []string{"things", "show", "--list", "Today", "--jsonl", "--fields", "name,priority"},
+			expectErr: true,
+		},
+		{
+			name:      "without jsonl",
+			args:      []string{"things", "show", "--list", "Today", "--fields", "name"},
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cleanup := setupMockExecutorIntegration(mockOutput, nil)
+			defer cleanup()
+
+			var out strings.Builder
+			app := createTestAppWithWriters(&out, io.Discard)
+			err := app.Run(context.Background(), tt.args)
+			if tt.expectErr {
+				if err == nil {
+					t.Error("expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if out.String() != tt.expectOutput {
+				t.Errorf("expected %q, got %q", tt.expectOutput, out.String())
+			}
+		})
+	}
+}
+
+func TestWithURLFlag(t *testing.T) {
+	mockOutput := `[{"id":"A 1","name":"Task 1","status":"open"}]`
+
+	tests := []struct {
+		name         string
+		args         []string
+		expectErr    string
+		expectOutput string
+	}{
+		{
+			name:         "with fields",
+			args:         []string{"things", "show", "--list", "Today", "--jsonl", "--fields", "id", "--with-url"},
+			expectOutput: "{\"id\":\"A 1\",\"thingsURL\":\"things:///show?id=A%201\"}\n",
+		},
+		{
+			name:      "without jsonl",
+			args:      []string{"things", "show", "--list", "Today", "--json", "--with-url"},
+			expectErr: "ERROR: --with-url can only be used with --jsonl",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cleanup := setupMockExecutorIntegration(mockOutput, nil)
+			defer cleanup()
+
+			var out strings.Builder
+			app := createTestAppWithWriters(&out, io.Discard)
+			err := app.Run(context.Background(), tt.args)
+			if tt.expectErr != "" {
+				if err == nil || err.Error() != tt.expectErr {
+					t.Errorf("expected error %q, got %v", tt.expectErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if out.String() != tt.expectOutput {
+				t.Errorf("expected %q, got %q", tt.expectOutput, out.String())
+			}
+		})
+	}
+}
+
+func TestEditCommand(t *testing.T) {
+	tests := []struct {
+		name         string
+		args         []string
+		expectErr    bool
+		expectScript []string
+		rejectScript []string
+	}{
+		{
+			name:         "notes only",
+			args:         []string{"things", "edit", "--list", "Inbox", "--name", "Task", "--notes", "Call back"},
+			expectScript: []string{"todo.notes = 'Call back';"},
+			rejectScript: []string{"todo.dueDate", "todo.tagNames", "todo.name =", "app.schedule"},
+		},
+		{
+			name:         "empty notes clear them",
+			args:         []string{"things", "edit", "-l", "Inbox", "-n", "Task", "--notes", ""},
+			expectScript: []string{"todo.notes = '';"},
+		},
+		{
+			name:         "due, when, and tags",
+			args:         []string{"things", "edit", "-l", "Inbox", "-n", "Task", "--due", "2024-02-01", "--when", "anytime", "--tags", "Work, Home"},
+			expectScript: []string{"todo.dueDate = new Date(2024, 1, 1);", "encodeURIComponent('anytime')", "todo.tagNames = 'Work, Home';"},
+			rejectScript: []string{"todo.notes"},
+		},
+		{
+			name:         "clear due date",
+			args:         []string{"things", "edit", "-l", "Inbox", "-n", "Task", "--due", ""},
+			expectScript: []string{"todo.dueDate = null;"},
+		},
+		{
+			name:         "new name",
+			args:         []string{"things", "edit", "-l", "Inbox", "-n", "Task", "--new-name", "Renamed"},
+			expectScript: []string{"todo.name = 'Renamed';"},
+		},
+		{"nothing to change", []string{"things", "edit", "-l", "Inbox", "-n", "Task"}, true, nil, nil},
+		{"invalid due", []string{"things", "edit", "-l", "Inbox", "-n", "Task", "--due", "soon"}, true, nil, nil},
+		{"invalid when", []string{"things", "edit", "-l", "Inbox", "-n", "Task", "--when", "later"}, true, nil, nil},
+		{"empty new name", []string{"things", "edit", "-l", "Inbox", "-n", "Task", "--new-name", ""}, true, nil, nil},
+		{
+			name:         "reminder",
+			args:         []string{"things", "edit", "-l", "Inbox", "-n", "Task", "--when", "2024-03-05", "--reminder", "09:15"},
+			expectScript: []string{"encodeURIComponent('secret')", "encodeURIComponent('2024-03-05@09:15')"},
+			rejectScript: []string{"app.schedule("},
+		},
+		{
+			name:         "when today",
+			args:         []string{"things", "edit", "-l", "Inbox", "-n", "Task", "--when", "today"},
+			expectScript: []string{"app.schedule(todo, {for: new Date()});"},
+			rejectScript: []string{"app.move("},
+		},
+		{"invalid reminder", []string{"things", "edit", "-l", "Inbox", "-n", "Task", "--when", "today", "--reminder", "25:00"}, true, nil, nil},
+		{"reminder with someday", []string{"things", "edit", "-l", "Inbox", "-n", "Task", "--when", "someday", "--reminder", "09:15"}, true, nil, nil},
+		{"reminder without when", []string{"things", "edit", "-l", "Inbox", "-n", "Task", "--reminder", "09:15"}, true, nil, nil},
+	}
+
+	t.Setenv("THINGS_AUTH_TOKEN", "secret")
+	defer func() { authToken = "" }()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cleanup := setupMockExecutorIntegration("SUCCESS", nil)
+			defer cleanup()
+
+			app := createTestApp()
+			err := app.Run(context.Background(), tt.args)
+
+			mock := executor.(*MockExecutor)
+			if tt.expectErr {
+				if _, ok := err.(cli.ExitCoder); !ok {
+					t.Errorf("expected cli.ExitCoder, got %T (%v)", err, err)
+				}
+				if len(mock.calls) != 0 {
+					t.Errorf("expected no calls, got %d", len(mock.calls))
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			script := mock.lastScript()
+			for _, expected := range tt.expectScript {
+				if !strings.Contains(script, expected) {
+					t.Errorf("expected script to contain %q, got:\n%s", expected, script)
+				}
+			}
+			for _, rejected := range tt.rejectScript {
+				if strings.Contains(script, rejected) {
+					t.Errorf("expected script not to contain %q, got:\n%s", rejected, script)
+				}
+			}
+		})
+	}
+}
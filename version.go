@@ -0,0 +1,46 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"runtime"
+)
+
+// Build metadata - set at build time with -ldflags "-X main.version=... -X main.commit=... -X main.date=..."
+var (
+	version = "dev"
+	commit  = "none"
+	date    = "unknown"
+)
+
+// VersionInfo describes the build of this binary, as printed by the version command
+type VersionInfo struct {
+	Version   string `json:"version"`
+	GoVersion string `json:"goVersion"`
+	Commit    string `json:"commit"`
+	Date      string `json:"date"`
+}
+
+// currentVersionInfo returns the build metadata of the running binary
+func currentVersionInfo() VersionInfo {
+	return VersionInfo{
+		Version:   version,
+		GoVersion: runtime.Version(),
+		Commit:    commit,
+		Date:      date,
+	}
+}
+
+// formatVersionInfo formats info as a human-readable line
+func formatVersionInfo(info VersionInfo) string {
+	return fmt.Sprintf("things %s (commit %s, built %s with %s)", info.Version, info.Commit, info.Date, info.GoVersion)
+}
+
+// formatVersionInfoAsJSON formats info as a single JSON line
+func formatVersionInfoAsJSON(info VersionInfo) (string, error) {
+	jsonBytes, err := json.Marshal(info)
+	if err != nil {
+		return "", fmt.Errorf("error marshaling version: %v", err)
+	}
+	return string(jsonBytes), nil
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestVersionCommand_JSON(t *testing.T) {
+	oldVersion, oldCommit, oldDate := version, commit, date
+	version, commit, date = "1.2.3", "abc1234", "2026-10-15T12:00:00Z"
+	defer func() { version, commit, date = oldVersion, oldCommit, oldDate }()
+
+	var out strings.Builder
+	app := createTestAppWithWriters(&out, io.Discard)
+	if err := app.Run(context.Background(), []string{"things", "version", "--json"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var info VersionInfo
+	if err := json.Unmarshal([]byte(out.String()), &info); err != nil {
+		t.Fatalf("output isn't JSON: %v\n%s", err, out.String())
+	}
+	expected := VersionInfo{Version: "1.2.3", GoVersion: runtime.Version(), Commit: "abc1234", Date: "2026-10-15T12:00:00Z"}
+	if info != expected {
+		t.Errorf("expected %+v, got %+v", expected, info)
+	}
+}
+
+func TestVersionCommand_Text(t *testing.T) {
+	oldVersion, oldCommit, oldDate := version, commit, date
+	version, commit, date = "1.2.3", "abc1234", "2026-10-15T12:00:00Z"
+	defer func() { version, commit, date = oldVersion, oldCommit, oldDate }()
+
+	var out strings.Builder
+	app := createTestAppWithWriters(&out, io.Discard)
+	if err := app.Run(context.Background(), []string{"things", "version"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := "things 1.2.3 (commit abc1234, built 2026-10-15T12:00:00Z with " + runtime.Version() + ")\n"
+	if out.String() != expected {
+		t.Errorf("expected %q, got %q", expected, out.String())
+	}
+}